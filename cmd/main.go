@@ -1,7 +1,12 @@
 package main
 
 import (
+	"context"
+	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/delivery"
 	"github.com/SawitProRecruitment/UserService/generated"
@@ -13,24 +18,127 @@ import (
 )
 
 func main() {
+	repo, err := newRepository()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if pool, ok := repo.(repository.Pool); ok {
+		if err := waitForDatabase(pool); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(context.Background(), repo, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if migrator, ok := repo.(repository.Migrator); ok && migrateOnStart() {
+		if _, err := migrator.Migrate(context.Background()); err != nil {
+			log.Fatalf("migrate: %v", err)
+		}
+	}
+
 	e := echo.New()
 
-	var server generated.ServerInterface = newServer()
+	uc := newUsecase(repo)
+	var server generated.ServerInterface = newServer(repo, uc)
+
+	go runPurgeJob(context.Background(), uc,
+		durationEnv("PURGE_RETENTION", defaultPurgeRetention),
+		durationEnv("PURGE_INTERVAL", defaultPurgeInterval),
+		e.Logger)
 
 	generated.RegisterHandlers(e, server)
 	e.Use(middleware.Logger())
 	e.Logger.Fatal(e.Start(":1323"))
 }
 
-func newServer() *delivery.Server {
+// defaultQueryTimeout bounds each database call unless QUERY_TIMEOUT says
+// otherwise.
+const defaultQueryTimeout = 10 * time.Second
+
+// Defaults of the connection pool, each overridden by its DB_* variable.
+const (
+	defaultMaxOpenConns    = 25
+	defaultMaxIdleConns    = 25
+	defaultConnMaxLifetime = 30 * time.Minute
+	defaultConnMaxIdleTime = 5 * time.Minute
+	defaultConnectTimeout  = 30 * time.Second
+)
+
+// defaultReadYourWrites is how long the reads of an estate stay on the
+// primary after a write, for the replicas to catch up.
+const defaultReadYourWrites = 5 * time.Second
+
+func newRepository() (repository.RepositoryInterface, error) {
 	dbDsn := os.Getenv("DATABASE_URL")
-	var repo repository.RepositoryInterface = repository.NewRepository(repository.NewRepositoryOptions{
-		Dsn: dbDsn,
+	return repository.NewRepository(repository.NewRepositoryOptions{
+		Dsn:             dbDsn,
+		ReplicaDsns:     listEnv("DATABASE_REPLICA_URLS"),
+		ReadYourWrites:  durationEnv("READ_YOUR_WRITES", defaultReadYourWrites),
+		QueryTimeout:    durationEnv("QUERY_TIMEOUT", defaultQueryTimeout),
+		MaxOpenConns:    intEnv("DB_MAX_OPEN_CONNS", defaultMaxOpenConns),
+		MaxIdleConns:    intEnv("DB_MAX_IDLE_CONNS", defaultMaxIdleConns),
+		ConnMaxLifetime: durationEnv("DB_CONN_MAX_LIFETIME", defaultConnMaxLifetime),
+		ConnMaxIdleTime: durationEnv("DB_CONN_MAX_IDLE_TIME", defaultConnMaxIdleTime),
 	})
+}
+
+// waitForDatabase blocks until the database answers or DB_CONNECT_TIMEOUT
+// runs out, so that the service can start alongside its database. A zero
+// timeout does not wait.
+func waitForDatabase(pool repository.Pool) error {
+	timeout := durationEnv("DB_CONNECT_TIMEOUT", defaultConnectTimeout)
+	if timeout == 0 {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return repository.WaitReady(ctx, pool, 500*time.Millisecond, 5*time.Second)
+}
+
+// durationEnv reads a duration such as "720h" from the environment, or the
+// fallback when it is unset or not a duration. Zero is kept as it turns off
+// what the variable bounds, while a negative duration stops the server.
+func durationEnv(name string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(name))
+	if err != nil {
+		return fallback
+	}
+	if value < 0 {
+		log.Fatalf("%s must not be negative", name)
+	}
+	return value
+}
+
+// intEnv reads a positive integer from the environment.
+func intEnv(name string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || value <= 0 {
+		return fallback
+	}
+	return value
+}
+
+// listEnv reads a comma-separated list from the environment.
+func listEnv(name string) (values []string) {
+	for _, value := range strings.Split(os.Getenv(name), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func newUsecase(repo repository.RepositoryInterface) usecase.UsecaseInterface {
+	return usecase.NewUsecase(repo)
+}
 
-	var usecase usecase.UsecaseInterface = usecase.NewUsecase(repo)
+func newServer(repo repository.RepositoryInterface, uc usecase.UsecaseInterface) *delivery.Server {
 	opts := delivery.NewServerOptions{
-		Usecase: usecase,
+		Repository: repo,
+		Usecase:    uc,
 	}
 	return delivery.NewServer(opts)
 }
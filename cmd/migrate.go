@@ -0,0 +1,47 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+)
+
+// migrateOnStart tells if the server applies the pending migrations when it
+// starts, unless AUTO_MIGRATE is false.
+func migrateOnStart() bool {
+	value, err := strconv.ParseBool(os.Getenv("AUTO_MIGRATE"))
+	return err != nil || value
+}
+
+// runMigrate runs the migrate subcommand: "migrate" or "migrate up" applies
+// the pending migrations, "migrate down [n]" reverts the last n, 1 by default.
+func runMigrate(ctx context.Context, repo repository.RepositoryInterface, args []string) error {
+	migrator, ok := repo.(repository.Migrator)
+	if !ok {
+		return errors.New("the repository has no schema to migrate")
+	}
+	if len(args) == 0 || args[0] == "up" {
+		applied, err := migrator.Migrate(ctx)
+		fmt.Printf("applied %d migrations\n", applied)
+		return err
+	}
+	if args[0] != "down" {
+		return errors.New("usage: migrate [up | down [n]]")
+	}
+
+	steps := 1
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return errors.New("usage: migrate [up | down [n]]")
+		}
+		steps = n
+	}
+	reverted, err := migrator.MigrateDown(ctx, steps)
+	fmt.Printf("reverted %d migrations\n", reverted)
+	return err
+}
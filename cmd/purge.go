@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/usecase"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	defaultPurgeRetention = 30 * 24 * time.Hour
+	defaultPurgeInterval  = time.Hour
+)
+
+// runPurgeJob hard-deletes, every interval, the estates and trees that have
+// been soft-deleted for longer than the retention window. A zero interval
+// turns the job off.
+func runPurgeJob(ctx context.Context, uc usecase.UsecaseInterface, retention time.Duration, interval time.Duration, logger echo.Logger) {
+	if interval == 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		purged, err := uc.PurgeDeleted(ctx, retention)
+		if err != nil {
+			logger.Errorf("purge deleted: %v", err)
+		} else if purged.Estates > 0 || purged.Trees > 0 {
+			logger.Infof("purged %d estates and %d trees", purged.Estates, purged.Trees)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -1,11 +1,16 @@
 package delivery
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
 	"io"
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/repository"
 	m "github.com/SawitProRecruitment/UserService/types"
 	"github.com/labstack/echo/v4"
 	openapi_types "github.com/oapi-codegen/runtime/types"
@@ -13,42 +18,863 @@ import (
 
 func (s *Server) PostEstate(ctx echo.Context) error {
 	rawBody, _ := io.ReadAll(ctx.Request().Body)
-	var body m.Estate
+	var body generated.PostEstateJSONRequestBody
 	if err := json.Unmarshal(rawBody, &body); err != nil {
 		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
 	}
-	id, err := s.Usecase.CreateEstate(ctx.Request().Context(), body.Length, body.Width)
+	estate := m.Estate{Length: body.Length, Width: body.Width}
+	if body.Name != nil {
+		estate.Name = *body.Name
+	}
+	if body.Code != nil {
+		estate.Code = *body.Code
+	}
+	if body.Owner != nil {
+		estate.Owner = *body.Owner
+	}
+	if body.Region != nil {
+		estate.Region = *body.Region
+	}
+	if body.PlantingYear != nil {
+		estate.PlantingYear = *body.PlantingYear
+	}
+	if body.Tags != nil {
+		estate.Tags = *body.Tags
+	}
+	if body.Metadata != nil {
+		estate.Metadata = *body.Metadata
+	}
+	if body.Geo != nil {
+		estate.Geo = &m.GeoAnchor{
+			OriginLat: body.Geo.OriginLat,
+			OriginLon: body.Geo.OriginLon,
+			Bearing:   body.Geo.Bearing,
+			PlotSize:  body.Geo.PlotSize,
+		}
+	}
+	if body.Boundary != nil {
+		estate.Boundary = []m.Point{}
+		for _, p := range *body.Boundary {
+			estate.Boundary = append(estate.Boundary, m.Point{X: p.X, Y: p.Y})
+		}
+	}
+	id, err := s.Usecase.CreateEstate(ctx.Request().Context(), estate)
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+		return usecaseError(ctx, err)
 	}
 	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: id})
 }
 
-func (s *Server) PostEstateIdTree(ctx echo.Context, id openapi_types.UUID) error {
+func (s *Server) PatchEstateId(ctx echo.Context, id openapi_types.UUID) error {
 	rawBody, _ := io.ReadAll(ctx.Request().Body)
-	var body m.Tree
+	var body generated.PatchEstateIdJSONRequestBody
 	if err := json.Unmarshal(rawBody, &body); err != nil {
 		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
 	}
-	id_returned, err := s.Usecase.CreateTree(ctx.Request().Context(), id.String(), m.Tree{X: body.X, Y: body.Y, Height: body.Height})
+	update := m.EstateUpdate{
+		Name:         body.Name,
+		Code:         body.Code,
+		Owner:        body.Owner,
+		Region:       body.Region,
+		PlantingYear: body.PlantingYear,
+		Length:       body.Length,
+		Width:        body.Width,
+	}
+	if body.Tags != nil {
+		update.Tags = *body.Tags
+	}
+	if body.Metadata != nil {
+		update.Metadata = *body.Metadata
+	}
+	estate, err := s.Usecase.UpdateEstate(ctx.Request().Context(), id.String(), update)
 	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, toEstateResponse(estate))
+}
+
+func toEstateResponse(estate m.Estate) generated.Estate {
+	response := generated.Estate{
+		Id:        estate.ID,
+		Name:      estate.Name,
+		Owner:     estate.Owner,
+		Region:    estate.Region,
+		Tags:      []string{},
+		Length:    estate.Length,
+		Width:     estate.Width,
+		DeletedAt: estate.DeletedAt,
+	}
+	if estate.Code != "" {
+		response.Code = &estate.Code
+	}
+	if estate.PlantingYear != 0 {
+		response.PlantingYear = &estate.PlantingYear
+	}
+	if estate.Tags != nil {
+		response.Tags = estate.Tags
+	}
+	if estate.Metadata != nil {
+		metadata := generated.Metadata(estate.Metadata)
+		response.Metadata = &metadata
+	}
+	return response
+}
+
+func (s *Server) GetEstate(ctx echo.Context, params generated.GetEstateParams) error {
+	var search m.EstateSearch
+	if params.Q != nil {
+		search.Query = *params.Q
+	}
+	if params.Tag != nil {
+		search.Tag = *params.Tag
+	}
+	estates, err := s.Usecase.SearchEstates(ctx.Request().Context(), search, params.IncludeDeleted != nil && *params.IncludeDeleted)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.EstateList{Estates: []generated.Estate{}}
+	for _, estate := range estates {
+		response.Estates = append(response.Estates, toEstateResponse(estate))
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) DeleteEstateId(ctx echo.Context, id openapi_types.UUID) error {
+	if err := s.Usecase.DeleteEstate(ctx.Request().Context(), id.String()); err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) PostEstateIdRestore(ctx echo.Context, id openapi_types.UUID) error {
+	if err := s.Usecase.RestoreEstate(ctx.Request().Context(), id.String()); err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) GetEstateIdAudit(ctx echo.Context, id openapi_types.UUID) error {
+	entries, err := s.Usecase.GetAuditLog(ctx.Request().Context(), id.String())
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.AuditLog{Entries: []generated.AuditEntry{}}
+	for _, e := range entries {
+		item := generated.AuditEntry{Entity: generated.AuditEntryEntity(e.Entity), Action: generated.AuditEntryAction(e.Action), At: e.At}
+		if e.Entity == "tree" {
+			x, y := e.X, e.Y
+			item.X, item.Y = &x, &y
+		}
+		response.Entries = append(response.Entries, item)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) PostEstateIdTree(ctx echo.Context, id openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdTreeJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
 		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
 	}
+	tree := m.Tree{X: body.X, Y: body.Y, Height: body.Height}
+	if body.Variety != nil {
+		tree.Variety = *body.Variety
+	}
+	if body.PlantedAt != nil {
+		tree.PlantedAt = &body.PlantedAt.Time
+	}
+	if body.Status != nil {
+		tree.Status = string(*body.Status)
+	}
+	id_returned, err := s.Usecase.CreateTree(ctx.Request().Context(), id.String(), tree)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
 	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: id_returned})
 }
 
-func (s *Server) GetEstateIdStats(ctx echo.Context, id openapi_types.UUID) error {
-	stats, err := s.Usecase.GetEstateStats(ctx.Request().Context(), id.String())
+func (s *Server) DeleteEstateIdTreeXY(ctx echo.Context, id openapi_types.UUID, x int, y int) error {
+	if err := s.Usecase.DeleteTree(ctx.Request().Context(), id.String(), x, y); err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) PostEstateIdTreeXYRestore(ctx echo.Context, id openapi_types.UUID, x int, y int) error {
+	if err := s.Usecase.RestoreTree(ctx.Request().Context(), id.String(), x, y); err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+// treeFilter builds the tree filter from the optional query parameters.
+func treeFilter(variety *string, status *generated.TreeStatus, plantedFrom, plantedTo *openapi_types.Date) (filter m.TreeFilter) {
+	if variety != nil {
+		filter.Variety = *variety
+	}
+	if status != nil {
+		filter.Status = string(*status)
+	}
+	if plantedFrom != nil {
+		filter.PlantedFrom = &plantedFrom.Time
+	}
+	if plantedTo != nil {
+		filter.PlantedTo = &plantedTo.Time
+	}
+	return filter
+}
+
+func (s *Server) GetEstateIdStats(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdStatsParams) error {
+	filter := treeFilter(params.Variety, params.Status, params.PlantedFrom, params.PlantedTo)
+	var stats m.Stats
+	var err error
+	if params.BlockId != nil {
+		stats, err = s.Usecase.GetBlockStats(ctx.Request().Context(), id.String(), params.BlockId.String(), filter)
+	} else {
+		stats, err = s.Usecase.GetEstateStats(ctx.Request().Context(), id.String(), filter)
+	}
 	if err != nil {
-		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+		return usecaseError(ctx, err)
 	}
 	return ctx.JSON(http.StatusOK, generated.EstateStats{Count: stats.Count, Max: stats.Max, Min: stats.Min, Median: stats.Median})
 }
 
-func (s *Server) GetEstateIdDronePlan(ctx echo.Context, id openapi_types.UUID) error {
-	distance, err := s.Usecase.GetDroneDistance(ctx.Request().Context(), id.String())
+func (s *Server) GetEstateIdDronePlan(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdDronePlanParams) error {
+	var distance int
+	var err error
+	if params.BlockId != nil {
+		distance, err = s.Usecase.GetBlockDroneDistance(ctx.Request().Context(), id.String(), params.BlockId.String())
+	} else {
+		distance, err = s.Usecase.GetDroneDistance(ctx.Request().Context(), id.String())
+	}
 	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	response := generated.DroneDistance{Distance: distance}
+
+	if params.Waypoints != nil && *params.Waypoints {
+		withGeo := params.Geo != nil && *params.Geo
+		var waypoints []m.Waypoint
+		if params.BlockId != nil {
+			waypoints, err = s.Usecase.GetBlockDroneWaypoints(ctx.Request().Context(), id.String(), params.BlockId.String(), withGeo)
+		} else {
+			waypoints, err = s.Usecase.GetDroneWaypoints(ctx.Request().Context(), id.String(), withGeo)
+		}
+		if err != nil {
+			return usecaseError(ctx, err)
+		}
+		list := []generated.Waypoint{}
+		for _, wp := range waypoints {
+			item := generated.Waypoint{X: wp.X, Y: wp.Y, Altitude: wp.Altitude}
+			if wp.Location != nil {
+				item.Lat, item.Lon = &wp.Location.Lat, &wp.Location.Lon
+			}
+			list = append(list, item)
+		}
+		response.Waypoints = &list
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) GetEstateIdTree(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdTreeParams) error {
+	filter := treeFilter(params.Variety, params.Status, params.PlantedFrom, params.PlantedTo)
+	trees, err := s.Usecase.ListTrees(ctx.Request().Context(), id.String(), filter, params.Geo != nil && *params.Geo, params.IncludeDeleted != nil && *params.IncludeDeleted)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	return ctx.JSON(http.StatusOK, toTreeList(trees))
+}
+
+func toTreeList(trees []m.Tree) generated.TreeList {
+	response := generated.TreeList{Trees: []generated.Tree{}}
+	for _, t := range trees {
+		item := generated.Tree{X: t.X, Y: t.Y, Height: t.Height, Status: generated.TreeStatus(t.Status)}
+		if t.Variety != "" {
+			variety := t.Variety
+			item.Variety = &variety
+		}
+		if t.PlantedAt != nil {
+			item.PlantedAt = &openapi_types.Date{Time: *t.PlantedAt}
+		}
+		if t.Location != nil {
+			item.Lat, item.Lon = &t.Location.Lat, &t.Location.Lon
+		}
+		item.DeletedAt = t.DeletedAt
+		response.Trees = append(response.Trees, item)
+	}
+	return response
+}
+
+func (s *Server) GetEstateIdTreeAtRisk(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdTreeAtRiskParams) error {
+	var obsType string
+	if params.Type != nil {
+		obsType = *params.Type
+	}
+	trees, err := s.Usecase.GetTreesAtRisk(ctx.Request().Context(), id.String(), params.Radius, obsType)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, toTreeList(trees))
+}
+
+func (s *Server) PostEstateIdObservation(ctx echo.Context, id openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdObservationJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
 		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
 	}
-	return ctx.JSON(http.StatusOK, generated.DroneDistance{Distance: distance})
+	observation := m.Observation{EstateID: id.String(), X: body.X, Y: body.Y, Type: body.Type, Severity: body.Severity}
+	if body.ObservedAt != nil {
+		observation.ObservedAt = *body.ObservedAt
+	}
+	if body.Notes != nil {
+		observation.Notes = *body.Notes
+	}
+	observationID, err := s.Usecase.CreateObservation(ctx.Request().Context(), observation)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: observationID})
+}
+
+func (s *Server) GetEstateIdObservation(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdObservationParams) error {
+	filter := m.ObservationFilter{Since: params.Since}
+	if params.Type != nil {
+		filter.Type = *params.Type
+	}
+	if params.X != nil {
+		filter.X = *params.X
+	}
+	if params.Y != nil {
+		filter.Y = *params.Y
+	}
+	observations, err := s.Usecase.ListObservations(ctx.Request().Context(), id.String(), filter)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.ObservationList{Observations: []generated.Observation{}}
+	for _, o := range observations {
+		response.Observations = append(response.Observations, generated.Observation{
+			Id: o.ID, X: o.X, Y: o.Y, Type: o.Type, Severity: o.Severity, ObservedAt: o.ObservedAt, Notes: o.Notes,
+		})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// GetEstateIdTreeCsv writes the trees as they are read, so that an estate of
+// any size is exported without keeping its trees. An error once the export
+// has started cuts it short.
+func (s *Server) GetEstateIdTreeCsv(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdTreeCsvParams) error {
+	withGeo := params.Geo != nil && *params.Geo
+	includeDeleted := params.IncludeDeleted != nil && *params.IncludeDeleted
+	filter := treeFilter(params.Variety, params.Status, params.PlantedFrom, params.PlantedTo)
+
+	w := csv.NewWriter(ctx.Response())
+	started := false
+	// start sends the header once the estate is known to exist
+	start := func() {
+		ctx.Response().Header().Set(echo.HeaderContentType, "text/csv")
+		ctx.Response().WriteHeader(http.StatusOK)
+		header := []string{"x", "y", "height", "variety", "planted_at", "status"}
+		if withGeo {
+			header = append(header, "lat", "lon")
+		}
+		if includeDeleted {
+			header = append(header, "deleted_at")
+		}
+		w.Write(header)
+		started = true
+	}
+	err := s.Usecase.EachTree(ctx.Request().Context(), id.String(), filter, withGeo, includeDeleted, func(t m.Tree) error {
+		if !started {
+			start()
+		}
+		plantedAt := ""
+		if t.PlantedAt != nil {
+			plantedAt = t.PlantedAt.Format(time.DateOnly)
+		}
+		record := []string{strconv.Itoa(t.X), strconv.Itoa(t.Y), strconv.Itoa(t.Height), t.Variety, plantedAt, t.Status}
+		if withGeo {
+			lat, lon := "", ""
+			if t.Location != nil {
+				lat = strconv.FormatFloat(t.Location.Lat, 'f', -1, 64)
+				lon = strconv.FormatFloat(t.Location.Lon, 'f', -1, 64)
+			}
+			record = append(record, lat, lon)
+		}
+		if includeDeleted {
+			deletedAt := ""
+			if t.DeletedAt != nil {
+				deletedAt = t.DeletedAt.Format(time.RFC3339)
+			}
+			record = append(record, deletedAt)
+		}
+		w.Write(record)
+		return w.Error()
+	})
+	if err != nil {
+		if !started {
+			return usecaseError(ctx, err)
+		}
+		// the response is on its way, echo only logs the error
+		return err
+	}
+	if !started {
+		start()
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func (s *Server) GetStatsEstates(ctx echo.Context, params generated.GetStatsEstatesParams) error {
+	filter := m.EstateReportFilter{
+		MinCount:   params.MinCount,
+		MaxCount:   params.MaxCount,
+		MinDensity: params.MinDensity,
+		MaxDensity: params.MaxDensity,
+	}
+	if params.SortBy != nil {
+		filter.SortBy = string(*params.SortBy)
+	}
+	if params.Order != nil {
+		filter.Order = string(*params.Order)
+	}
+
+	reports, err := s.Usecase.GetEstatesReport(ctx.Request().Context(), filter)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.EstatesReport{Estates: []generated.EstateReport{}}
+	for _, r := range reports {
+		response.Estates = append(response.Estates, generated.EstateReport{
+			Id:       r.EstateID,
+			Length:   r.Length,
+			Width:    r.Width,
+			Count:    r.Count,
+			Density:  r.Density,
+			Max:      r.Max,
+			Min:      r.Min,
+			Median:   r.Median,
+			Distance: r.DroneDistance,
+		})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func toPlotRanges(ranges []m.PlotRange) []generated.PlotRange {
+	result := []generated.PlotRange{}
+	for _, r := range ranges {
+		result = append(result, generated.PlotRange{Y: r.Y, XStart: r.XStart, XEnd: r.XEnd, Length: r.Length})
+	}
+	return result
+}
+
+func (s *Server) GetEstateIdDensity(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdDensityParams) error {
+	gapLimit := 5
+	if params.GapLimit != nil {
+		gapLimit = *params.GapLimit
+	}
+
+	analysis, err := s.Usecase.AnalyzeDensity(ctx.Request().Context(), id.String(), gapLimit)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.EstateDensity{
+		Plots:       analysis.Plots,
+		Planted:     analysis.Planted,
+		Empty:       analysis.Empty,
+		Density:     analysis.Density,
+		EmptyRanges: toPlotRanges(analysis.EmptyRanges),
+		LargestGaps: toPlotRanges(analysis.LargestGaps),
+	})
+}
+
+func mapOptions(size *int, dronePath *bool) (int, bool) {
+	resolution := 512
+	if size != nil {
+		resolution = *size
+	}
+	return resolution, dronePath != nil && *dronePath
+}
+
+func (s *Server) GetEstateIdMapPng(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdMapPngParams) error {
+	size, withDronePath := mapOptions(params.Size, params.DronePath)
+	estateMap, err := s.Usecase.GetEstateMap(ctx.Request().Context(), id.String(), size, withDronePath)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	image, err := renderMapPNG(estateMap, size)
+	if err != nil {
+		return ctx.JSON(http.StatusInternalServerError, generated.ErrorResponse{Message: err.Error()})
+	}
+	return ctx.Blob(http.StatusOK, "image/png", image)
+}
+
+func (s *Server) GetEstateIdMapSvg(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdMapSvgParams) error {
+	size, withDronePath := mapOptions(params.Size, params.DronePath)
+	estateMap, err := s.Usecase.GetEstateMap(ctx.Request().Context(), id.String(), size, withDronePath)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.Blob(http.StatusOK, "image/svg+xml", renderMapSVG(estateMap, size))
+}
+
+func toExtent(extent generated.Extent) m.Extent {
+	return m.Extent{XMin: extent.XMin, YMin: extent.YMin, XMax: extent.XMax, YMax: extent.YMax}
+}
+
+func fromExtent(extent m.Extent) generated.Extent {
+	return generated.Extent{XMin: extent.XMin, YMin: extent.YMin, XMax: extent.XMax, YMax: extent.YMax}
+}
+
+func (s *Server) PostEstateIdDivision(ctx echo.Context, id openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdDivisionJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+	}
+	division := m.Division{EstateID: id.String(), Name: body.Name, Supervisor: body.Supervisor, Extent: toExtent(body.Extent)}
+	divisionID, err := s.Usecase.CreateDivision(ctx.Request().Context(), division)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: divisionID})
+}
+
+func (s *Server) GetEstateIdDivision(ctx echo.Context, id openapi_types.UUID) error {
+	estate, err := s.Usecase.GetEstateDivisions(ctx.Request().Context(), id.String())
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.DivisionList{Divisions: []generated.Division{}}
+	for _, d := range estate.Divisions {
+		division := generated.Division{Id: d.ID, Name: d.Name, Supervisor: d.Supervisor, Extent: fromExtent(d.Extent), Blocks: []generated.Block{}}
+		for _, b := range d.Blocks {
+			division.Blocks = append(division.Blocks, generated.Block{Id: b.ID, Name: b.Name, Supervisor: b.Supervisor, Extent: fromExtent(b.Extent)})
+		}
+		response.Divisions = append(response.Divisions, division)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) PostEstateIdDivisionDivisionIdBlock(ctx echo.Context, id openapi_types.UUID, divisionId openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdDivisionDivisionIdBlockJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+	}
+	block := m.Block{EstateID: id.String(), DivisionID: divisionId.String(), Name: body.Name, Supervisor: body.Supervisor, Extent: toExtent(body.Extent)}
+	blockID, err := s.Usecase.CreateBlock(ctx.Request().Context(), block)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: blockID})
+}
+
+func (s *Server) PostEstateIdHarvest(ctx echo.Context, id openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdHarvestJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+	}
+	harvest := m.Harvest{EstateID: id.String(), X: body.X, Y: body.Y, Bunches: body.Bunches, Weight: body.Weight}
+	if body.HarvestedAt != nil {
+		harvest.HarvestedAt = body.HarvestedAt.Time
+	}
+	harvestID, err := s.Usecase.CreateHarvest(ctx.Request().Context(), harvest)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: harvestID})
+}
+
+func (s *Server) GetEstateIdYield(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdYieldParams) error {
+	var filter m.YieldFilter
+	if params.BlockId != nil {
+		filter.BlockID = params.BlockId.String()
+	}
+	if params.From != nil {
+		filter.From = &params.From.Time
+	}
+	if params.To != nil {
+		filter.To = &params.To.Time
+	}
+	if params.Period != nil {
+		filter.Period = string(*params.Period)
+	}
+	report, err := s.Usecase.GetYieldReport(ctx.Request().Context(), id.String(), filter)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.YieldReport{
+		Bunches: report.Bunches, Weight: report.Weight, Trees: report.Trees, HeightCorrelation: report.HeightCorrelation,
+		Periods: []generated.YieldPeriod{}, Blocks: []generated.BlockYield{}, Heights: []generated.HeightYield{},
+	}
+	for _, p := range report.Periods {
+		response.Periods = append(response.Periods, generated.YieldPeriod{Start: openapi_types.Date{Time: p.Start}, Bunches: p.Bunches, Weight: p.Weight})
+	}
+	for _, b := range report.Blocks {
+		item := generated.BlockYield{Bunches: b.Bunches, Weight: b.Weight}
+		if b.BlockID != "" {
+			blockID := b.BlockID
+			item.BlockId = &blockID
+		}
+		response.Blocks = append(response.Blocks, item)
+	}
+	for _, h := range report.Heights {
+		response.Heights = append(response.Heights, generated.HeightYield{MinHeight: h.MinHeight, MaxHeight: h.MaxHeight, Trees: h.Trees, Bunches: h.Bunches, Weight: h.Weight})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) PostEstateIdHarvestRound(ctx echo.Context, id openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdHarvestRoundJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+	}
+	round := m.HarvestRound{EstateID: id.String(), Teams: body.Teams}
+	if body.BlockId != nil {
+		round.BlockID = body.BlockId.String()
+	}
+	if body.ScheduledOn != nil {
+		round.ScheduledOn = body.ScheduledOn.Time
+	}
+	roundID, err := s.Usecase.CreateHarvestRound(ctx.Request().Context(), round)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: roundID})
+}
+
+func (s *Server) GetEstateIdHarvestRound(ctx echo.Context, id openapi_types.UUID) error {
+	rounds, err := s.Usecase.ListHarvestRounds(ctx.Request().Context(), id.String())
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.HarvestRoundList{Rounds: []generated.HarvestRound{}}
+	for _, r := range rounds {
+		item := generated.HarvestRound{Id: r.ID, Teams: r.Teams, ScheduledOn: openapi_types.Date{Time: r.ScheduledOn}, CompletedAt: r.CompletedAt}
+		if r.BlockID != "" {
+			blockID := r.BlockID
+			item.BlockId = &blockID
+		}
+		response.Rounds = append(response.Rounds, item)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) GetEstateIdHarvestRoundRoundIdPlan(ctx echo.Context, id openapi_types.UUID, roundId openapi_types.UUID) error {
+	routes, err := s.Usecase.GetHarvestPlan(ctx.Request().Context(), id.String(), roundId.String())
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.HarvestPlan{Routes: []generated.HarvestRoute{}}
+	for _, r := range routes {
+		route := generated.HarvestRoute{Team: r.Team, Trees: []generated.Point{}}
+		for _, p := range r.Trees {
+			route.Trees = append(route.Trees, generated.Point{X: p.X, Y: p.Y})
+		}
+		response.Routes = append(response.Routes, route)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) PostEstateIdHarvestRoundRoundIdComplete(ctx echo.Context, id openapi_types.UUID, roundId openapi_types.UUID) error {
+	if err := s.Usecase.CompleteHarvestRound(ctx.Request().Context(), id.String(), roundId.String()); err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.NoContent(http.StatusNoContent)
+}
+
+func (s *Server) PostEstateIdFertilizerProgram(ctx echo.Context, id openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdFertilizerProgramJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+	}
+	program := m.FertilizerProgram{EstateID: id.String(), Product: body.Product, Dose: body.Dose, Interval: body.Interval}
+	if body.BlockId != nil {
+		program.BlockID = body.BlockId.String()
+	}
+	if body.StartOn != nil {
+		program.StartOn = body.StartOn.Time
+	}
+	programID, err := s.Usecase.CreateFertilizerProgram(ctx.Request().Context(), program)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: programID})
+}
+
+func (s *Server) GetEstateIdFertilizerProgram(ctx echo.Context, id openapi_types.UUID) error {
+	programs, err := s.Usecase.ListFertilizerPrograms(ctx.Request().Context(), id.String())
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.FertilizerProgramList{Programs: []generated.FertilizerProgram{}}
+	for _, p := range programs {
+		item := generated.FertilizerProgram{Id: p.ID, Product: p.Product, Dose: p.Dose, Interval: p.Interval, StartOn: openapi_types.Date{Time: p.StartOn}}
+		if p.BlockID != "" {
+			blockID := p.BlockID
+			item.BlockId = &blockID
+		}
+		response.Programs = append(response.Programs, item)
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) PostEstateIdFertilizerProgramProgramIdApplication(ctx echo.Context, id openapi_types.UUID, programId openapi_types.UUID) error {
+	rawBody, _ := io.ReadAll(ctx.Request().Body)
+	var body generated.PostEstateIdFertilizerProgramProgramIdApplicationJSONRequestBody
+	if err := json.Unmarshal(rawBody, &body); err != nil {
+		return ctx.JSON(http.StatusBadRequest, generated.ErrorResponse{Message: err.Error()})
+	}
+	application := m.FertilizerApplication{ProgramID: programId.String()}
+	if body.AppliedOn != nil {
+		application.AppliedOn = body.AppliedOn.Time
+	}
+	if body.Trees != nil {
+		application.Trees = *body.Trees
+	}
+	if body.Quantity != nil {
+		application.Quantity = *body.Quantity
+	}
+	applicationID, err := s.Usecase.RecordFertilizerApplication(ctx.Request().Context(), id.String(), application)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+	return ctx.JSON(http.StatusOK, generated.CreateResponse{Id: applicationID})
+}
+
+func (s *Server) GetEstateIdFertilizerProgramProgramIdApplication(ctx echo.Context, id openapi_types.UUID, programId openapi_types.UUID) error {
+	applications, err := s.Usecase.ListFertilizerApplications(ctx.Request().Context(), id.String(), programId.String())
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.FertilizerApplicationList{Applications: []generated.FertilizerApplication{}}
+	for _, a := range applications {
+		response.Applications = append(response.Applications, generated.FertilizerApplication{
+			Id: a.ID, AppliedOn: openapi_types.Date{Time: a.AppliedOn}, Trees: a.Trees, Quantity: a.Quantity,
+		})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) GetEstateIdFertilizerCalendar(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdFertilizerCalendarParams) error {
+	days := 90
+	if params.Days != nil {
+		days = *params.Days
+	}
+	calendar, err := s.Usecase.GetFertilizerCalendar(ctx.Request().Context(), id.String(), days)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.FertilizerCalendar{Due: []generated.FertilizerDue{}, Materials: []generated.Material{}}
+	for _, d := range calendar.Due {
+		item := generated.FertilizerDue{
+			ProgramId: d.ProgramID, Product: d.Product, DueOn: openapi_types.Date{Time: d.DueOn}, Overdue: d.Overdue, Trees: d.Trees, Quantity: d.Quantity,
+		}
+		if d.BlockID != "" {
+			blockID := d.BlockID
+			item.BlockId = &blockID
+		}
+		response.Due = append(response.Due, item)
+	}
+	for _, mat := range calendar.Materials {
+		response.Materials = append(response.Materials, generated.Material{Product: mat.Product, Quantity: mat.Quantity})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+func (s *Server) GetEstateIdReplanting(ctx echo.Context, id openapi_types.UUID, params generated.GetEstateIdReplantingParams) error {
+	var options m.ReplantingOptions
+	if params.MaxAge != nil {
+		options.MaxAge = *params.MaxAge
+	}
+	if params.MaxShare != nil {
+		options.MaxShare = *params.MaxShare
+	}
+	if params.StartYear != nil {
+		options.StartYear = *params.StartYear
+	}
+	plan, err := s.Usecase.GetReplantingPlan(ctx.Request().Context(), id.String(), options)
+	if err != nil {
+		return usecaseError(ctx, err)
+	}
+
+	response := generated.ReplantingPlan{
+		Candidates:  []generated.ReplantingCandidate{},
+		Blocks:      []generated.BlockWorkload{},
+		Phases:      []generated.ReplantingPhase{},
+		AnnualLimit: plan.AnnualLimit,
+	}
+	for _, c := range plan.Candidates {
+		item := generated.ReplantingCandidate{X: c.X, Y: c.Y, Status: generated.TreeStatus(c.Status), Age: c.Age, Year: c.Year}
+		if c.BlockID != "" {
+			blockID := c.BlockID
+			item.BlockId = &blockID
+		}
+		response.Candidates = append(response.Candidates, item)
+	}
+	for _, b := range plan.Blocks {
+		item := generated.BlockWorkload{Trees: b.Trees, Candidates: b.Candidates}
+		if b.BlockID != "" {
+			blockID := b.BlockID
+			item.BlockId = &blockID
+		}
+		response.Blocks = append(response.Blocks, item)
+	}
+	for _, p := range plan.Phases {
+		response.Phases = append(response.Phases, generated.ReplantingPhase{Year: p.Year, Trees: p.Trees})
+	}
+	return ctx.JSON(http.StatusOK, response)
+}
+
+// healthTimeout bounds the ping of the health check.
+const healthTimeout = 2 * time.Second
+
+// GetHealth reports whether the database answers, with the statistics of its
+// connection pool.
+func (s *Server) GetHealth(ctx echo.Context) error {
+	response := generated.Health{Status: generated.Ok}
+	pool, ok := s.Repository.(repository.Pool)
+	if !ok {
+		return ctx.JSON(http.StatusOK, response)
+	}
+
+	status := http.StatusOK
+	pingCtx, cancel := context.WithTimeout(ctx.Request().Context(), healthTimeout)
+	defer cancel()
+	if err := pool.Ping(pingCtx); err != nil {
+		status = http.StatusServiceUnavailable
+		message := err.Error()
+		response.Status, response.Error = generated.Unavailable, &message
+	}
+	stats := pool.Stats()
+	response.Pool = &generated.PoolStats{
+		MaxOpen:           stats.MaxOpenConnections,
+		Open:              stats.OpenConnections,
+		InUse:             stats.InUse,
+		Idle:              stats.Idle,
+		WaitCount:         stats.WaitCount,
+		WaitDurationMs:    stats.WaitDuration.Milliseconds(),
+		MaxIdleClosed:     stats.MaxIdleClosed,
+		MaxIdleTimeClosed: stats.MaxIdleTimeClosed,
+		MaxLifetimeClosed: stats.MaxLifetimeClosed,
+	}
+	return ctx.JSON(status, response)
 }
@@ -1,13 +1,20 @@
 package delivery
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/SawitProRecruitment/UserService/generated"
+	"github.com/SawitProRecruitment/UserService/repository"
 	m "github.com/SawitProRecruitment/UserService/types"
+	uc "github.com/SawitProRecruitment/UserService/usecase"
 	usecase "github.com/SawitProRecruitment/UserService/usecase/mock"
 	"github.com/labstack/echo/v4"
 	openapi_types "github.com/oapi-codegen/runtime/types"
@@ -27,7 +34,7 @@ func TestServer_PostEstate_Positive(t *testing.T) {
 	c := e.NewContext(req, rec)
 	h := &Server{Usecase: mockUC}
 
-	mockUC.EXPECT().CreateEstate(gomock.Any(), 5, 5).Return("aaa", nil)
+	mockUC.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 5, Width: 5}).Return("aaa", nil)
 
 	// Assertions
 	if assert.NoError(t, h.PostEstate(c)) {
@@ -67,7 +74,7 @@ func TestServer_PostEstate_UsecaseError(t *testing.T) {
 	c := e.NewContext(req, rec)
 	h := &Server{Usecase: mockUC}
 
-	mockUC.EXPECT().CreateEstate(gomock.Any(), 5, 5).Return("", errors.New("usecase"))
+	mockUC.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 5, Width: 5}).Return("", errors.New("usecase"))
 
 	// Assertions
 	if assert.NoError(t, h.PostEstate(c)) {
@@ -147,10 +154,10 @@ func TestServer_GetEstateIdStats_Positive(t *testing.T) {
 	c := e.NewContext(req, rec)
 	h := &Server{Usecase: mockUC}
 
-	mockUC.EXPECT().GetEstateStats(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(m.Stats{Count: 3, Max: 10, Min: 3, Median: 5}, nil)
+	mockUC.EXPECT().GetEstateStats(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}).Return(m.Stats{Count: 3, Max: 10, Min: 3, Median: 5}, nil)
 
 	// Assertions
-	if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{})) {
+	if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{}, generated.GetEstateIdStatsParams{})) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
 	}
@@ -166,10 +173,10 @@ func TestServer_GetEstateIdStats_Usecase_error(t *testing.T) {
 	c := e.NewContext(req, rec)
 	h := &Server{Usecase: mockUC}
 
-	mockUC.EXPECT().GetEstateStats(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(m.Stats{}, errors.New("usecase"))
+	mockUC.EXPECT().GetEstateStats(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}).Return(m.Stats{}, errors.New("usecase"))
 
 	// Assertions
-	if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{})) {
+	if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{}, generated.GetEstateIdStatsParams{})) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
 	}
@@ -188,12 +195,59 @@ func TestServer_GetEstateIdDronePlan_Positive(t *testing.T) {
 	mockUC.EXPECT().GetDroneDistance(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(50, nil)
 
 	// Assertions
-	if assert.NoError(t, h.GetEstateIdDronePlan(c, openapi_types.UUID{})) {
+	if assert.NoError(t, h.GetEstateIdDronePlan(c, openapi_types.UUID{}, generated.GetEstateIdDronePlanParams{})) {
 		assert.Equal(t, http.StatusOK, rec.Code)
 		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
 	}
 }
 
+func TestServer_GetEstateIdStats_Usecase_canceled(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	h := &Server{Usecase: mockUC}
+
+	for _, tt := range []struct {
+		err  error
+		code int
+	}{
+		{err: &repository.CanceledError{Err: context.DeadlineExceeded}, code: http.StatusGatewayTimeout},
+		{err: fmt.Errorf("stats: %w", &repository.CanceledError{Err: context.Canceled}), code: 499},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/stats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		mockUC.EXPECT().GetEstateStats(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}).Return(m.Stats{}, tt.err)
+
+		// Assertions
+		if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{}, generated.GetEstateIdStatsParams{})) {
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, `{"message":"`+tt.err.Error()+`"}`, strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	}
+}
+
+func TestServer_GetEstateIdStats_Usecase_notExist(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	h := &Server{Usecase: mockUC}
+
+	// a missing estate reads as sql.ErrNoRows from the repositories
+	for _, err := range []error{&uc.NotExistError{What: "estate"}, sql.ErrNoRows} {
+		req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/stats", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		mockUC.EXPECT().GetEstateStats(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}).Return(m.Stats{}, err)
+
+		// Assertions
+		if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{}, generated.GetEstateIdStatsParams{})) {
+			assert.Equal(t, http.StatusNotFound, rec.Code)
+			assert.Equal(t, `{"message":"estate is not exist"}`, strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	}
+}
+
 func TestServer_GetEstateIdDronePlan_Usecase_error(t *testing.T) {
 	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
 	response := `{"message":"usecase"}`
@@ -207,8 +261,1275 @@ func TestServer_GetEstateIdDronePlan_Usecase_error(t *testing.T) {
 	mockUC.EXPECT().GetDroneDistance(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(0, errors.New("usecase"))
 
 	// Assertions
-	if assert.NoError(t, h.GetEstateIdDronePlan(c, openapi_types.UUID{})) {
+	if assert.NoError(t, h.GetEstateIdDronePlan(c, openapi_types.UUID{}, generated.GetEstateIdDronePlanParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetStatsEstates_Positive(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"estates":[{"count":3,"density":0.6,"distance":54,"id":"aaa","length":5,"max":5,"median":4,"min":3,"width":1}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/stats/estates?sort_by=count&order=desc", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	sortBy := generated.GetStatsEstatesParamsSortBy("count")
+	order := generated.GetStatsEstatesParamsOrder("desc")
+	mockUC.EXPECT().GetEstatesReport(gomock.Any(), m.EstateReportFilter{SortBy: "count", Order: "desc"}).Return([]m.EstateReport{
+		{EstateID: "aaa", Length: 5, Width: 1, Count: 3, Density: 0.6, Max: 5, Min: 3, Median: 4, DroneDistance: 54},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetStatsEstates(c, generated.GetStatsEstatesParams{SortBy: &sortBy, Order: &order})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetStatsEstates_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/stats/estates", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetEstatesReport(gomock.Any(), m.EstateReportFilter{}).Return(nil, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetStatsEstates(c, generated.GetStatsEstatesParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdDensity_Positive(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"density":0.5,"empty":1,"empty_ranges":[{"length":1,"x_end":2,"x_start":2,"y":1}],"largest_gaps":[{"length":1,"x_end":2,"x_start":2,"y":1}],"planted":1,"plots":2}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/density", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	gap := m.PlotRange{Y: 1, XStart: 2, XEnd: 2, Length: 1}
+	mockUC.EXPECT().AnalyzeDensity(gomock.Any(), "00000000-0000-0000-0000-000000000000", 5).Return(m.DensityAnalysis{
+		Plots: 2, Planted: 1, Empty: 1, Density: 0.5, EmptyRanges: []m.PlotRange{gap}, LargestGaps: []m.PlotRange{gap},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdDensity(c, openapi_types.UUID{}, generated.GetEstateIdDensityParams{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdDensity_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/density?gap_limit=2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	gapLimit := 2
+	mockUC.EXPECT().AnalyzeDensity(gomock.Any(), "00000000-0000-0000-0000-000000000000", 2).Return(m.DensityAnalysis{}, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdDensity(c, openapi_types.UUID{}, generated.GetEstateIdDensityParams{GapLimit: &gapLimit})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdMapPng_Positive(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/map.png", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetEstateMap(gomock.Any(), "00000000-0000-0000-0000-000000000000", 512, false).Return(m.EstateMap{Columns: 1, Rows: 1, Scale: 1, Heights: []int{5}}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdMapPng(c, openapi_types.UUID{}, generated.GetEstateIdMapPngParams{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "\x89PNG"))
+	}
+}
+
+func TestServer_GetEstateIdMapPng_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/map.png", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetEstateMap(gomock.Any(), "00000000-0000-0000-0000-000000000000", 512, false).Return(m.EstateMap{}, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdMapPng(c, openapi_types.UUID{}, generated.GetEstateIdMapPngParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdMapSvg_Positive(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/map.svg?size=64&drone_path=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	size := 64
+	dronePath := true
+	mockUC.EXPECT().GetEstateMap(gomock.Any(), "00000000-0000-0000-0000-000000000000", 64, true).Return(m.EstateMap{Columns: 1, Rows: 1, Scale: 1, Heights: []int{5}}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdMapSvg(c, openapi_types.UUID{}, generated.GetEstateIdMapSvgParams{Size: &size, DronePath: &dronePath})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "image/svg+xml", rec.Header().Get(echo.HeaderContentType))
+		assert.True(t, strings.HasPrefix(rec.Body.String(), "<svg"))
+	}
+}
+
+func TestServer_GetEstateIdMapSvg_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/map.svg", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetEstateMap(gomock.Any(), "00000000-0000-0000-0000-000000000000", 512, false).Return(m.EstateMap{}, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdMapSvg(c, openapi_types.UUID{}, generated.GetEstateIdMapSvgParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstate_Georeferenced(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"length":5,"width":5,"geo":{"origin_lat":1.5,"origin_lon":101.5,"bearing":90,"plot_size":9}}`
+	response := `{"id":"aaa"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 5, Width: 5, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: 101.5, Bearing: 90, PlotSize: 9}}).Return("aaa", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdDronePlan_Waypoints(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"distance":20,"waypoints":[{"altitude":0,"lat":1.5,"lon":101.5,"x":1,"y":1},{"altitude":0,"x":2,"y":1}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/drone-plan?waypoints=true&geo=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	withWaypoints, withGeo := true, true
+	mockUC.EXPECT().GetDroneDistance(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(20, nil)
+	mockUC.EXPECT().GetDroneWaypoints(gomock.Any(), "00000000-0000-0000-0000-000000000000", true).Return([]m.Waypoint{
+		{X: 1, Y: 1, Altitude: 0, Location: &m.Coordinate{Lat: 1.5, Lon: 101.5}},
+		{X: 2, Y: 1, Altitude: 0},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdDronePlan(c, openapi_types.UUID{}, generated.GetEstateIdDronePlanParams{Waypoints: &withWaypoints, Geo: &withGeo})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdDronePlan_Waypoints_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/drone-plan?waypoints=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	withWaypoints := true
+	mockUC.EXPECT().GetDroneDistance(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(20, nil)
+	mockUC.EXPECT().GetDroneWaypoints(gomock.Any(), "00000000-0000-0000-0000-000000000000", false).Return(nil, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdDronePlan(c, openapi_types.UUID{}, generated.GetEstateIdDronePlanParams{Waypoints: &withWaypoints})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdTree_Positive(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"trees":[{"height":5,"lat":1.5,"lon":101.5,"status":"healthy","x":1,"y":1},{"height":6,"planted_at":"2015-03-01","status":"replanted","variety":"DxP","x":2,"y":1}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree?geo=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	withGeo := true
+	planted := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().ListTrees(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}, true, false).Return([]m.Tree{
+		{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy, Location: &m.Coordinate{Lat: 1.5, Lon: 101.5}},
+		{X: 2, Y: 1, Height: 6, Variety: "DxP", PlantedAt: &planted, Status: m.TreeReplanted},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdTree(c, openapi_types.UUID{}, generated.GetEstateIdTreeParams{Geo: &withGeo})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdTree_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().ListTrees(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}, false, false).Return(nil, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdTree(c, openapi_types.UUID{}, generated.GetEstateIdTreeParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+// eachTree lets the mock stream the trees, then fail with err.
+func eachTree(trees []m.Tree, err error) func(ctx context.Context, estateID string, filter m.TreeFilter, withGeo bool, includeDeleted bool, fn func(tree m.Tree) error) error {
+	return func(ctx context.Context, estateID string, filter m.TreeFilter, withGeo bool, includeDeleted bool, fn func(tree m.Tree) error) error {
+		for _, t := range trees {
+			if err := fn(t); err != nil {
+				return err
+			}
+		}
+		return err
+	}
+}
+
+func TestServer_GetEstateIdTreeCsv_Positive(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := "x,y,height,variety,planted_at,status,lat,lon\n1,1,5,,,healthy,1.5,101.5\n2,1,6,DxP,2015-03-01,dead,,\n"
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree.csv?geo=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	withGeo := true
+	planted := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().EachTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}, true, false, gomock.Any()).DoAndReturn(eachTree([]m.Tree{
+		{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy, Location: &m.Coordinate{Lat: 1.5, Lon: 101.5}},
+		{X: 2, Y: 1, Height: 6, Variety: "DxP", PlantedAt: &planted, Status: m.TreeDead},
+	}, nil))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdTreeCsv(c, openapi_types.UUID{}, generated.GetEstateIdTreeCsvParams{Geo: &withGeo})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "text/csv", rec.Header().Get(echo.HeaderContentType))
+		assert.Equal(t, response, rec.Body.String())
+	}
+}
+
+func TestServer_GetEstateIdTreeCsv_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree.csv", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().EachTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}, false, false, gomock.Any()).DoAndReturn(eachTree(nil, errors.New("usecase")))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdTreeCsv(c, openapi_types.UUID{}, generated.GetEstateIdTreeCsvParams{})) {
 		assert.Equal(t, http.StatusBadRequest, rec.Code)
 		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
 	}
 }
+
+func TestServer_PostEstate_Boundary(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"length":5,"width":5,"boundary":[{"x":1,"y":1},{"x":5,"y":1},{"x":1,"y":5}]}`
+	response := `{"id":"aaa"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 5, Width: 5, Boundary: []m.Point{{X: 1, Y: 1}, {X: 5, Y: 1}, {X: 1, Y: 5}}}).Return("aaa", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdStats_Block(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	blockID := openapi_types.UUID{1}
+	response := `{"count":2,"max":4,"median":3,"min":2}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/stats?block_id="+blockID.String(), nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetBlockStats(gomock.Any(), "00000000-0000-0000-0000-000000000000", blockID.String(), m.TreeFilter{}).Return(m.Stats{Count: 2, Max: 4, Min: 2, Median: 3}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{}, generated.GetEstateIdStatsParams{BlockId: &blockID})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdDronePlan_Block(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	blockID := openapi_types.UUID{1}
+	withWaypoints := true
+	response := `{"distance":40,"waypoints":[{"altitude":0,"x":5,"y":3},{"altitude":0,"x":5,"y":4}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/drone-plan", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetBlockDroneDistance(gomock.Any(), "00000000-0000-0000-0000-000000000000", blockID.String()).Return(40, nil)
+	mockUC.EXPECT().GetBlockDroneWaypoints(gomock.Any(), "00000000-0000-0000-0000-000000000000", blockID.String(), false).Return([]m.Waypoint{{X: 5, Y: 3}, {X: 5, Y: 4}}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdDronePlan(c, openapi_types.UUID{}, generated.GetEstateIdDronePlanParams{BlockId: &blockID, Waypoints: &withWaypoints})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdDivision(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"name":"north","supervisor":"budi","extent":{"x_min":1,"y_min":1,"x_max":5,"y_max":3}}`
+	response := `{"id":"ddd"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/division", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CreateDivision(gomock.Any(), m.Division{
+		EstateID: "00000000-0000-0000-0000-000000000000", Name: "north", Supervisor: "budi",
+		Extent: m.Extent{XMin: 1, YMin: 1, XMax: 5, YMax: 3},
+	}).Return("ddd", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdDivision(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdDivisionDivisionIdBlock_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"name":"A1","supervisor":"siti","extent":{"x_min":1,"y_min":1,"x_max":2,"y_max":2}}`
+	response := `{"message":"block overlaps another block"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/division/00000000-0000-0000-0000-000000000000/block", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CreateBlock(gomock.Any(), gomock.Any()).Return("", errors.New("block overlaps another block"))
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdDivisionDivisionIdBlock(c, openapi_types.UUID{}, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdDivision(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"divisions":[{"blocks":[{"extent":{"x_max":2,"x_min":1,"y_max":2,"y_min":1},"id":"bbb","name":"A1","supervisor":"siti"}],"extent":{"x_max":5,"x_min":1,"y_max":3,"y_min":1},"id":"ddd","name":"north","supervisor":"budi"}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/division", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetEstateDivisions(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(m.Estate{ID: "aaa", Divisions: []m.Division{{
+		ID: "ddd", Name: "north", Supervisor: "budi", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 5, YMax: 3},
+		Blocks: []m.Block{{ID: "bbb", Name: "A1", Supervisor: "siti", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 2, YMax: 2}}},
+	}}}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdDivision(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstate_NameAndMetadata(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"name":"north","length":5,"width":5,"metadata":{"owner":"budi"}}`
+	response := `{"id":"aaa"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CreateEstate(gomock.Any(), m.Estate{Name: "north", Length: 5, Width: 5, Metadata: map[string]string{"owner": "budi"}}).Return("aaa", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PatchEstateId_Positive(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"name":"north","width":3}`
+	response := `{"id":"aaa","length":5,"metadata":{"owner":"budi"},"name":"north","owner":"","region":"","tags":[],"width":3}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPatch, "/estate/00000000-0000-0000-0000-000000000000", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	name, width := "north", 3
+	mockUC.EXPECT().UpdateEstate(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.EstateUpdate{Name: &name, Width: &width}).
+		Return(m.Estate{ID: "aaa", Name: "north", Length: 5, Width: 3, Metadata: map[string]string{"owner": "budi"}}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.PatchEstateId(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PatchEstateId_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"length":1}`
+	response := `{"message":"resize would leave trees outside estate"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPatch, "/estate/00000000-0000-0000-0000-000000000000", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().UpdateEstate(gomock.Any(), "00000000-0000-0000-0000-000000000000", gomock.Any()).Return(m.Estate{}, errors.New("resize would leave trees outside estate"))
+
+	// Assertions
+	if assert.NoError(t, h.PatchEstateId(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_DeleteEstateId(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/estate/00000000-0000-0000-0000-000000000000", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().DeleteEstate(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(nil)
+
+	// Assertions
+	if assert.NoError(t, h.DeleteEstateId(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+		assert.Equal(t, "", rec.Body.String())
+	}
+}
+
+func TestServer_PostEstateIdRestore_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"estate is not deleted"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/restore", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().RestoreEstate(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return(errors.New("estate is not deleted"))
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdRestore(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_DeleteEstateIdTreeXY(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodDelete, "/estate/00000000-0000-0000-0000-000000000000/tree/2/3", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().DeleteTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", 2, 3).Return(nil)
+
+	// Assertions
+	if assert.NoError(t, h.DeleteEstateIdTreeXY(c, openapi_types.UUID{}, 2, 3)) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestServer_PostEstateIdTreeXYRestore(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/tree/2/3/restore", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().RestoreTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", 2, 3).Return(nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdTreeXYRestore(c, openapi_types.UUID{}, 2, 3)) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestServer_GetEstateIdAudit(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"entries":[{"action":"delete","at":"2024-01-02T03:04:05Z","entity":"tree","x":2,"y":3},{"action":"delete","at":"2024-01-02T03:04:05Z","entity":"estate"}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/audit", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUC.EXPECT().GetAuditLog(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return([]m.AuditEntry{
+		{EstateID: "aaa", Entity: "tree", Action: "delete", X: 2, Y: 3, At: at},
+		{EstateID: "aaa", Entity: "estate", Action: "delete", At: at},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdAudit(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdTreeCsv_Streamed(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	// Setup
+	e := echo.New()
+	h := &Server{Usecase: mockUC}
+
+	// an estate without trees has the header only
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree.csv", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	mockUC.EXPECT().EachTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}, false, false, gomock.Any()).DoAndReturn(eachTree(nil, nil))
+	if assert.NoError(t, h.GetEstateIdTreeCsv(c, openapi_types.UUID{}, generated.GetEstateIdTreeCsvParams{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, "x,y,height,variety,planted_at,status\n", rec.Body.String())
+	}
+
+	// an error once the trees are on their way cuts the export short
+	rec = httptest.NewRecorder()
+	c = e.NewContext(req, rec)
+	mockUC.EXPECT().EachTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}, false, false, gomock.Any()).DoAndReturn(eachTree([]m.Tree{{X: 1, Y: 1, Height: 5}}, errors.New("usecase")))
+
+	// Assertions
+	assert.Error(t, h.GetEstateIdTreeCsv(c, openapi_types.UUID{}, generated.GetEstateIdTreeCsvParams{}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, c.Response().Committed)
+}
+
+func TestServer_GetEstateIdTreeCsv_IncludeDeleted(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	includeDeleted := true
+	response := "x,y,height,variety,planted_at,status,deleted_at\n1,1,5,,,healthy,\n2,1,7,,,dead,2024-01-02T03:04:05Z\n"
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree.csv?include_deleted=true", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUC.EXPECT().EachTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{}, false, true, gomock.Any()).DoAndReturn(eachTree([]m.Tree{
+		{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy},
+		{X: 2, Y: 1, Height: 7, Status: m.TreeDead, DeletedAt: &deletedAt},
+	}, nil))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdTreeCsv(c, openapi_types.UUID{}, generated.GetEstateIdTreeCsvParams{IncludeDeleted: &includeDeleted})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, rec.Body.String())
+	}
+}
+
+func TestServer_PostEstate_Details(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"name":"Sungai Lilin","code":"SL-01","owner":"PT Sawit","region":"Riau","planting_year":2015,"tags":["young"],"length":5,"width":5}`
+	response := `{"id":"aaa"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CreateEstate(gomock.Any(), m.Estate{
+		Name: "Sungai Lilin", Code: "SL-01", Owner: "PT Sawit", Region: "Riau", PlantingYear: 2015, Tags: []string{"young"}, Length: 5, Width: 5,
+	}).Return("aaa", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstate(c)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstate(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	q, tag := "lilin", "young"
+	response := `{"estates":[{"code":"SL-01","id":"aaa","length":5,"name":"Sungai Lilin","owner":"PT Sawit","planting_year":2015,"region":"Riau","tags":["young"],"width":5}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate?q=lilin&tag=young", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().SearchEstates(gomock.Any(), m.EstateSearch{Query: "lilin", Tag: "young"}, false).Return([]m.Estate{{
+		ID: "aaa", Name: "Sungai Lilin", Code: "SL-01", Owner: "PT Sawit", Region: "Riau", PlantingYear: 2015, Tags: []string{"young"}, Length: 5, Width: 5,
+	}}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstate(c, generated.GetEstateParams{Q: &q, Tag: &tag})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstate_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().SearchEstates(gomock.Any(), m.EstateSearch{}, false).Return(nil, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstate(c, generated.GetEstateParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdTree_Attributes(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"x":5,"y":5,"height":5,"variety":"DxP","planted_at":"2015-03-01","status":"diseased"}`
+	response := `{"id":"00000000-0000-0000-0000-000000000000"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/tree", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	planted := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().CreateTree(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.Tree{X: 5, Y: 5, Height: 5, Variety: "DxP", PlantedAt: &planted, Status: m.TreeDiseased}).Return("00000000-0000-0000-0000-000000000000", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdTree(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdStats_Filter(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"count":1,"max":4,"median":4,"min":4}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/stats?variety=DxP&status=healthy&planted_from=2015-01-01", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	variety := "DxP"
+	status := generated.TreeStatus(m.TreeHealthy)
+	from := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().GetEstateStats(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.TreeFilter{Variety: "DxP", Status: m.TreeHealthy, PlantedFrom: &from}).Return(m.Stats{Count: 1, Max: 4, Min: 4, Median: 4}, nil)
+
+	// Assertions
+	params := generated.GetEstateIdStatsParams{Variety: &variety, Status: &status, PlantedFrom: &openapi_types.Date{Time: from}}
+	if assert.NoError(t, h.GetEstateIdStats(c, openapi_types.UUID{}, params)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdObservation(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"x":2,"y":3,"type":"ganoderma","severity":2,"observed_at":"2024-01-02T03:04:05Z","notes":"fruiting body at base"}`
+	response := `{"id":"ooo"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/observation", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUC.EXPECT().CreateObservation(gomock.Any(), m.Observation{
+		EstateID: "00000000-0000-0000-0000-000000000000", X: 2, Y: 3, Type: "ganoderma", Severity: 2, ObservedAt: at, Notes: "fruiting body at base",
+	}).Return("ooo", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdObservation(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdObservation(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"observations":[{"id":"ooo","notes":"","observed_at":"2024-01-02T03:04:05Z","severity":2,"type":"ganoderma","x":2,"y":3}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/observation?type=ganoderma&x=2", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	obsType, x := "ganoderma", 2
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mockUC.EXPECT().ListObservations(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.ObservationFilter{Type: "ganoderma", X: 2}).Return([]m.Observation{
+		{ID: "ooo", EstateID: "00000000-0000-0000-0000-000000000000", X: 2, Y: 3, Type: "ganoderma", Severity: 2, ObservedAt: at},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdObservation(c, openapi_types.UUID{}, generated.GetEstateIdObservationParams{Type: &obsType, X: &x})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdTreeAtRisk(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"trees":[{"height":5,"status":"healthy","x":4,"y":5}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree/at-risk?radius=1&type=ganoderma", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	obsType := "ganoderma"
+	mockUC.EXPECT().GetTreesAtRisk(gomock.Any(), "00000000-0000-0000-0000-000000000000", 1, "ganoderma").Return([]m.Tree{{X: 4, Y: 5, Height: 5, Status: m.TreeHealthy}}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdTreeAtRisk(c, openapi_types.UUID{}, generated.GetEstateIdTreeAtRiskParams{Radius: 1, Type: &obsType})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdTreeAtRisk_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/tree/at-risk?radius=0", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetTreesAtRisk(gomock.Any(), "00000000-0000-0000-0000-000000000000", 0, "").Return(nil, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdTreeAtRisk(c, openapi_types.UUID{}, generated.GetEstateIdTreeAtRiskParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdHarvest(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"x":2,"y":3,"harvested_at":"2024-01-02","bunches":2,"weight":41.5}`
+	response := `{"id":"hhh"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/harvest", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().CreateHarvest(gomock.Any(), m.Harvest{EstateID: "00000000-0000-0000-0000-000000000000", X: 2, Y: 3, HarvestedAt: day, Bunches: 2, Weight: 41.5}).Return("hhh", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdHarvest(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdYield(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"blocks":[{"block_id":"b1","bunches":2,"weight":20},{"bunches":1,"weight":30}],"bunches":3,"height_correlation":1,` +
+		`"heights":[{"bunches":3,"max_height":5,"min_height":1,"trees":2,"weight":50}],"periods":[{"bunches":3,"start":"2024-01-01","weight":50}],"trees":2,"weight":50}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/yield?period=month&from=2024-01-01", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	period := generated.Month
+	mockUC.EXPECT().GetYieldReport(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.YieldFilter{From: &from, Period: m.PeriodMonth}).Return(m.YieldReport{
+		Bunches: 3, Weight: 50, Trees: 2, HeightCorrelation: 1,
+		Periods: []m.YieldPeriod{{Start: from, Bunches: 3, Weight: 50}},
+		Blocks:  []m.BlockYield{{BlockID: "b1", Bunches: 2, Weight: 20}, {Bunches: 1, Weight: 30}},
+		Heights: []m.HeightYield{{MinHeight: 1, MaxHeight: 5, Trees: 2, Bunches: 3, Weight: 50}},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdYield(c, openapi_types.UUID{}, generated.GetEstateIdYieldParams{From: &openapi_types.Date{Time: from}, Period: &period})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdYield_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/yield", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetYieldReport(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.YieldFilter{}).Return(m.YieldReport{}, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdYield(c, openapi_types.UUID{}, generated.GetEstateIdYieldParams{})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdHarvestRound(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	blockID := openapi_types.UUID{1}
+	request := `{"teams":3,"block_id":"` + blockID.String() + `","scheduled_on":"2024-01-10"}`
+	response := `{"id":"rrr"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/harvest-round", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().CreateHarvestRound(gomock.Any(), m.HarvestRound{EstateID: "00000000-0000-0000-0000-000000000000", BlockID: blockID.String(), Teams: 3, ScheduledOn: day}).Return("rrr", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdHarvestRound(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdHarvestRound(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"rounds":[{"completed_at":"2024-01-10T15:00:00Z","id":"r1","scheduled_on":"2024-01-10","teams":3},{"block_id":"b1","id":"r2","scheduled_on":"2024-01-18","teams":1}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/harvest-round", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	completedAt := day.Add(15 * time.Hour)
+	mockUC.EXPECT().ListHarvestRounds(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return([]m.HarvestRound{
+		{ID: "r1", Teams: 3, ScheduledOn: day, CompletedAt: &completedAt},
+		{ID: "r2", BlockID: "b1", Teams: 1, ScheduledOn: day.AddDate(0, 0, 8)},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdHarvestRound(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdHarvestRoundRoundIdPlan(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	roundID := openapi_types.UUID{2}
+	response := `{"routes":[{"team":1,"trees":[{"x":1,"y":1},{"x":2,"y":1}]},{"team":2,"trees":[{"x":2,"y":2}]}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/harvest-round/"+roundID.String()+"/plan", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().GetHarvestPlan(gomock.Any(), "00000000-0000-0000-0000-000000000000", roundID.String()).Return([]m.HarvestRoute{
+		{Team: 1, Trees: []m.Point{{X: 1, Y: 1}, {X: 2, Y: 1}}},
+		{Team: 2, Trees: []m.Point{{X: 2, Y: 2}}},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdHarvestRoundRoundIdPlan(c, openapi_types.UUID{}, roundID)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdHarvestRoundRoundIdComplete(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	roundID := openapi_types.UUID{2}
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/harvest-round/"+roundID.String()+"/complete", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CompleteHarvestRound(gomock.Any(), "00000000-0000-0000-0000-000000000000", roundID.String()).Return(nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdHarvestRoundRoundIdComplete(c, openapi_types.UUID{}, roundID)) {
+		assert.Equal(t, http.StatusNoContent, rec.Code)
+	}
+}
+
+func TestServer_PostEstateIdHarvestRoundRoundIdComplete_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	roundID := openapi_types.UUID{2}
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/harvest-round/"+roundID.String()+"/complete", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	mockUC.EXPECT().CompleteHarvestRound(gomock.Any(), "00000000-0000-0000-0000-000000000000", roundID.String()).Return(errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdHarvestRoundRoundIdComplete(c, openapi_types.UUID{}, roundID)) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdFertilizerProgram(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	request := `{"product":"NPK","dose":1.5,"interval":90,"start_on":"2024-01-02"}`
+	response := `{"id":"ppp"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/fertilizer-program", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().CreateFertilizerProgram(gomock.Any(), m.FertilizerProgram{EstateID: "00000000-0000-0000-0000-000000000000", Product: "NPK", Dose: 1.5, Interval: 90, StartOn: day}).Return("ppp", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdFertilizerProgram(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdFertilizerProgram(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"programs":[{"block_id":"b1","dose":1.5,"id":"ppp","interval":90,"product":"NPK","start_on":"2024-01-02"}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/fertilizer-program", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().ListFertilizerPrograms(gomock.Any(), "00000000-0000-0000-0000-000000000000").Return([]m.FertilizerProgram{
+		{ID: "ppp", BlockID: "b1", Product: "NPK", Dose: 1.5, Interval: 90, StartOn: day},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdFertilizerProgram(c, openapi_types.UUID{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_PostEstateIdFertilizerProgramProgramIdApplication(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	programID := openapi_types.UUID{3}
+	request := `{"applied_on":"2024-01-02","trees":10}`
+	response := `{"id":"fff"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/estate/00000000-0000-0000-0000-000000000000/fertilizer-program/"+programID.String()+"/application", strings.NewReader(request))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().RecordFertilizerApplication(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.FertilizerApplication{ProgramID: programID.String(), AppliedOn: day, Trees: 10}).Return("fff", nil)
+
+	// Assertions
+	if assert.NoError(t, h.PostEstateIdFertilizerProgramProgramIdApplication(c, openapi_types.UUID{}, programID)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdFertilizerProgramProgramIdApplication(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	programID := openapi_types.UUID{3}
+	response := `{"applications":[{"applied_on":"2024-01-02","id":"fff","quantity":15,"trees":10}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/fertilizer-program/"+programID.String()+"/application", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().ListFertilizerApplications(gomock.Any(), "00000000-0000-0000-0000-000000000000", programID.String()).Return([]m.FertilizerApplication{
+		{ID: "fff", ProgramID: programID.String(), AppliedOn: day, Trees: 10, Quantity: 15},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdFertilizerProgramProgramIdApplication(c, openapi_types.UUID{}, programID)) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdFertilizerCalendar(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"due":[{"due_on":"2024-01-02","overdue":true,"product":"Urea","program_id":"p2","quantity":2,"trees":4},` +
+		`{"block_id":"b1","due_on":"2024-01-12","overdue":false,"product":"NPK","program_id":"p1","quantity":3,"trees":2}],` +
+		`"materials":[{"product":"NPK","quantity":3},{"product":"Urea","quantity":2}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/fertilizer-calendar", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mockUC.EXPECT().GetFertilizerCalendar(gomock.Any(), "00000000-0000-0000-0000-000000000000", 90).Return(m.FertilizerCalendar{
+		Due: []m.FertilizerDue{
+			{ProgramID: "p2", Product: "Urea", DueOn: day, Overdue: true, Trees: 4, Quantity: 2},
+			{ProgramID: "p1", BlockID: "b1", Product: "NPK", DueOn: day.AddDate(0, 0, 10), Trees: 2, Quantity: 3},
+		},
+		Materials: []m.Material{{Product: "NPK", Quantity: 3}, {Product: "Urea", Quantity: 2}},
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdFertilizerCalendar(c, openapi_types.UUID{}, generated.GetEstateIdFertilizerCalendarParams{})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdFertilizerCalendar_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/fertilizer-calendar?days=400", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	days := 400
+	mockUC.EXPECT().GetFertilizerCalendar(gomock.Any(), "00000000-0000-0000-0000-000000000000", 400).Return(m.FertilizerCalendar{}, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdFertilizerCalendar(c, openapi_types.UUID{}, generated.GetEstateIdFertilizerCalendarParams{Days: &days})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdReplanting(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"annual_limit":2,"blocks":[{"block_id":"b1","candidates":2,"trees":3},{"candidates":1,"trees":1}],` +
+		`"candidates":[{"age":5,"block_id":"b1","status":"dead","x":2,"y":1,"year":2030},{"age":30,"block_id":"b1","status":"healthy","x":1,"y":1,"year":2030},` +
+		`{"age":28,"status":"diseased","x":1,"y":2,"year":2031}],"phases":[{"trees":2,"year":2030},{"trees":1,"year":2031}]}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/replanting?max_share=40&start_year=2030", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	share, year := 40.0, 2030
+	mockUC.EXPECT().GetReplantingPlan(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.ReplantingOptions{MaxShare: 40, StartYear: 2030}).Return(m.ReplantingPlan{
+		Candidates: []m.ReplantingCandidate{
+			{X: 2, Y: 1, BlockID: "b1", Status: m.TreeDead, Age: 5, Year: 2030},
+			{X: 1, Y: 1, BlockID: "b1", Status: m.TreeHealthy, Age: 30, Year: 2030},
+			{X: 1, Y: 2, Status: m.TreeDiseased, Age: 28, Year: 2031},
+		},
+		Blocks:      []m.BlockWorkload{{BlockID: "b1", Trees: 3, Candidates: 2}, {Trees: 1, Candidates: 1}},
+		Phases:      []m.ReplantingPhase{{Year: 2030, Trees: 2}, {Year: 2031, Trees: 1}},
+		AnnualLimit: 2,
+	}, nil)
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdReplanting(c, openapi_types.UUID{}, generated.GetEstateIdReplantingParams{MaxShare: &share, StartYear: &year})) {
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+func TestServer_GetEstateIdReplanting_Usecase_error(t *testing.T) {
+	mockUC := usecase.NewMockUsecaseInterface(gomock.NewController(t))
+	response := `{"message":"usecase"}`
+	// Setup
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/estate/00000000-0000-0000-0000-000000000000/replanting?max_age=-1", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	h := &Server{Usecase: mockUC}
+
+	age := -1
+	mockUC.EXPECT().GetReplantingPlan(gomock.Any(), "00000000-0000-0000-0000-000000000000", m.ReplantingOptions{MaxAge: -1}).Return(m.ReplantingPlan{}, errors.New("usecase"))
+
+	// Assertions
+	if assert.NoError(t, h.GetEstateIdReplanting(c, openapi_types.UUID{}, generated.GetEstateIdReplantingParams{MaxAge: &age})) {
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Equal(t, response, strings.TrimSuffix(rec.Body.String(), "\n"))
+	}
+}
+
+// healthRepository is a repository with a connection pool that answers the
+// ping with err.
+type healthRepository struct {
+	repository.RepositoryInterface
+	err error
+}
+
+func (r healthRepository) Ping(ctx context.Context) error { return r.err }
+
+func (r healthRepository) Stats() sql.DBStats {
+	return sql.DBStats{MaxOpenConnections: 25, OpenConnections: 2, InUse: 1, Idle: 1, WaitCount: 3, WaitDuration: 1500 * time.Millisecond}
+}
+
+func TestServer_GetHealth(t *testing.T) {
+	pool := `"pool":{"idle":1,"in_use":1,"max_idle_closed":0,"max_idle_time_closed":0,"max_lifetime_closed":0,"max_open":25,"open":2,"wait_count":3,"wait_duration_ms":1500}`
+	// Setup
+	e := echo.New()
+
+	for _, tt := range []struct {
+		repo     repository.RepositoryInterface
+		code     int
+		response string
+	}{
+		{repo: repository.NewMemoryRepository(), code: http.StatusOK, response: `{"status":"ok"}`},
+		{repo: healthRepository{}, code: http.StatusOK, response: `{` + pool + `,"status":"ok"}`},
+		{repo: healthRepository{err: errors.New("connection refused")}, code: http.StatusServiceUnavailable, response: `{"error":"connection refused",` + pool + `,"status":"unavailable"}`},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/health", nil)
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		h := &Server{Repository: tt.repo}
+
+		// Assertions
+		if assert.NoError(t, h.GetHealth(c)) {
+			assert.Equal(t, tt.code, rec.Code)
+			assert.Equal(t, tt.response, strings.TrimSuffix(rec.Body.String(), "\n"))
+		}
+	}
+}
@@ -0,0 +1,157 @@
+// This file renders estate maps into images.
+package delivery
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+const maxTreeHeight = 30
+
+var (
+	emptyPlotColor = color.RGBA{R: 0xe6, G: 0xd5, B: 0xb8, A: 0xff}
+	outsideColor   = color.RGBA{R: 0xbd, G: 0xbd, B: 0xbd, A: 0xff}
+	shortTreeColor = color.RGBA{R: 0xd9, G: 0xf0, B: 0xa3, A: 0xff}
+	tallTreeColor  = color.RGBA{R: 0x00, G: 0x68, B: 0x37, A: 0xff}
+	dronePathColor = color.RGBA{R: 0xd7, G: 0x30, B: 0x1f, A: 0xff}
+)
+
+// heightColor returns the colour of a plot, going from light to dark green as
+// the tree gets taller.
+func heightColor(height int) color.RGBA {
+	if height <= 0 {
+		return emptyPlotColor
+	}
+	ratio := float64(min(height, maxTreeHeight)-1) / float64(maxTreeHeight-1)
+	mix := func(a, b uint8) uint8 {
+		return uint8(float64(a) + (float64(b)-float64(a))*ratio + 0.5)
+	}
+	return color.RGBA{
+		R: mix(shortTreeColor.R, tallTreeColor.R),
+		G: mix(shortTreeColor.G, tallTreeColor.G),
+		B: mix(shortTreeColor.B, tallTreeColor.B),
+		A: 0xff,
+	}
+}
+
+// cellColor returns the colour of the cell at index i of the map. A cell
+// outside the estate boundary is greyed out unless a tree stands there.
+func cellColor(estateMap m.EstateMap, i int) color.RGBA {
+	if estateMap.Heights[i] <= 0 && estateMap.Outside != nil && estateMap.Outside[i] {
+		return outsideColor
+	}
+	return heightColor(estateMap.Heights[i])
+}
+
+// cellPixels returns how many pixels a map cell takes so the whole map fits
+// in size pixels.
+func cellPixels(estateMap m.EstateMap, size int) int {
+	return max(1, size/max(estateMap.Columns, estateMap.Rows))
+}
+
+func renderMapPNG(estateMap m.EstateMap, size int) ([]byte, error) {
+	px := cellPixels(estateMap, size)
+	img := image.NewRGBA(image.Rect(0, 0, estateMap.Columns*px, estateMap.Rows*px))
+
+	for row := 0; row < estateMap.Rows; row++ {
+		for col := 0; col < estateMap.Columns; col++ {
+			c := cellColor(estateMap, row*estateMap.Columns+col)
+			for y := row * px; y < (row+1)*px; y++ {
+				for x := col * px; x < (col+1)*px; x++ {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+	}
+
+	brush := max(1, px/4)
+	for i := 1; i < len(estateMap.DronePath); i++ {
+		from, to := estateMap.DronePath[i-1], estateMap.DronePath[i]
+		drawLine(img, from.X*px+px/2, from.Y*px+px/2, to.X*px+px/2, to.Y*px+px/2, brush, dronePathColor)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// drawLine draws a line with Bresenham's algorithm using a square brush.
+func drawLine(img *image.RGBA, x0, y0, x1, y1, brush int, c color.RGBA) {
+	dx, sx := x1-x0, 1
+	if dx < 0 {
+		dx, sx = -dx, -1
+	}
+	dy, sy := y0-y1, 1
+	if dy > 0 {
+		dy, sy = -dy, -1
+	}
+	e := dx + dy
+	for {
+		for y := y0 - brush/2; y < y0-brush/2+brush; y++ {
+			for x := x0 - brush/2; x < x0-brush/2+brush; x++ {
+				if (image.Point{X: x, Y: y}).In(img.Bounds()) {
+					img.SetRGBA(x, y, c)
+				}
+			}
+		}
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func renderMapSVG(estateMap m.EstateMap, size int) []byte {
+	px := cellPixels(estateMap, size)
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" shape-rendering="crispEdges">`,
+		estateMap.Columns*px, estateMap.Rows*px, estateMap.Columns*px, estateMap.Rows*px)
+
+	c := emptyPlotColor
+	fmt.Fprintf(&buf, `<rect width="100%%" height="100%%" fill="#%02x%02x%02x"/>`, c.R, c.G, c.B)
+	for row := 0; row < estateMap.Rows; row++ {
+		for col := 0; col < estateMap.Columns; col++ {
+			i := row*estateMap.Columns + col
+			height := estateMap.Heights[i]
+			c := cellColor(estateMap, i)
+			switch {
+			case height > 0:
+				fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="#%02x%02x%02x"><title>%d</title></rect>`,
+					col*px, row*px, px, px, c.R, c.G, c.B, height)
+			case c == outsideColor:
+				fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="%d" height="%d" fill="#%02x%02x%02x"/>`,
+					col*px, row*px, px, px, c.R, c.G, c.B)
+			}
+		}
+	}
+
+	if len(estateMap.DronePath) > 0 {
+		c := dronePathColor
+		fmt.Fprintf(&buf, `<polyline fill="none" stroke="#%02x%02x%02x" stroke-width="%d" points="`, c.R, c.G, c.B, max(1, px/4))
+		for i, p := range estateMap.DronePath {
+			if i > 0 {
+				buf.WriteByte(' ')
+			}
+			fmt.Fprintf(&buf, "%d,%d", p.X*px+px/2, p.Y*px+px/2)
+		}
+		buf.WriteString(`"/>`)
+	}
+
+	buf.WriteString(`</svg>`)
+	return buf.Bytes()
+}
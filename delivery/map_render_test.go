@@ -0,0 +1,65 @@
+package delivery
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"testing"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHeightColor(t *testing.T) {
+	assert.Equal(t, emptyPlotColor, heightColor(0))
+	assert.Equal(t, shortTreeColor, heightColor(1))
+	assert.Equal(t, tallTreeColor, heightColor(30))
+	assert.Equal(t, tallTreeColor, heightColor(45))
+}
+
+func TestCellColor(t *testing.T) {
+	estateMap := m.EstateMap{Columns: 3, Rows: 1, Scale: 1, Heights: []int{30, 0, 0}, Outside: []bool{true, true, false}}
+	assert.Equal(t, tallTreeColor, cellColor(estateMap, 0))
+	assert.Equal(t, outsideColor, cellColor(estateMap, 1))
+	assert.Equal(t, emptyPlotColor, cellColor(estateMap, 2))
+}
+
+func TestRenderMapPNG(t *testing.T) {
+	estateMap := m.EstateMap{
+		Columns:   2,
+		Rows:      1,
+		Scale:     1,
+		Heights:   []int{30, 0},
+		DronePath: []m.Point{{X: 0, Y: 0}, {X: 1, Y: 0}},
+	}
+
+	raw, err := renderMapPNG(estateMap, 8)
+	if assert.NoError(t, err) {
+		img, err := png.Decode(bytes.NewReader(raw))
+		assert.NoError(t, err)
+		assert.Equal(t, 8, img.Bounds().Dx())
+		assert.Equal(t, 4, img.Bounds().Dy())
+		assert.Equal(t, color.RGBAModel.Convert(tallTreeColor), color.RGBAModel.Convert(img.At(0, 0)))
+		assert.Equal(t, color.RGBAModel.Convert(emptyPlotColor), color.RGBAModel.Convert(img.At(7, 0)))
+		assert.Equal(t, color.RGBAModel.Convert(dronePathColor), color.RGBAModel.Convert(img.At(4, 2)))
+	}
+}
+
+func TestRenderMapSVG(t *testing.T) {
+	estateMap := m.EstateMap{
+		Columns:   2,
+		Rows:      1,
+		Scale:     1,
+		Heights:   []int{30, 0},
+		Outside:   []bool{false, true},
+		DronePath: []m.Point{{X: 0, Y: 0}, {X: 1, Y: 0}},
+	}
+
+	expected := `<svg xmlns="http://www.w3.org/2000/svg" width="8" height="4" viewBox="0 0 8 4" shape-rendering="crispEdges">` +
+		`<rect width="100%" height="100%" fill="#e6d5b8"/>` +
+		`<rect x="0" y="0" width="4" height="4" fill="#006837"><title>30</title></rect>` +
+		`<rect x="4" y="0" width="4" height="4" fill="#bdbdbd"/>` +
+		`<polyline fill="none" stroke="#d7301f" stroke-width="1" points="2,2 6,2"/>` +
+		`</svg>`
+	assert.Equal(t, expected, string(renderMapSVG(estateMap, 8)))
+}
@@ -1,8 +1,14 @@
 package delivery
 
 import (
+	"database/sql"
+	"errors"
+	"net/http"
+
+	"github.com/SawitProRecruitment/UserService/generated"
 	"github.com/SawitProRecruitment/UserService/repository"
 	"github.com/SawitProRecruitment/UserService/usecase"
+	"github.com/labstack/echo/v4"
 )
 
 type Server struct {
@@ -21,3 +27,30 @@ func NewServer(opts NewServerOptions) *Server {
 		Usecase:    opts.Usecase,
 	}
 }
+
+// statusClientClosedRequest is the non-standard status of a request whose
+// client went away before the response.
+const statusClientClosedRequest = 499
+
+// usecaseError responds to a failed usecase call: 404 when what was asked
+// for does not exist, 499 when the client went away, 504 when the database
+// ran out of time, a bad request otherwise. The repositories report a missing
+// estate as sql.ErrNoRows.
+func usecaseError(ctx echo.Context, err error) error {
+	status := http.StatusBadRequest
+	if errors.Is(err, sql.ErrNoRows) {
+		err = &usecase.NotExistError{What: "estate"}
+	}
+	var notExist *usecase.NotExistError
+	if errors.As(err, &notExist) {
+		status = http.StatusNotFound
+	}
+	var canceled *repository.CanceledError
+	if errors.As(err, &canceled) {
+		status = statusClientClosedRequest
+		if canceled.Timeout() {
+			status = http.StatusGatewayTimeout
+		}
+	}
+	return ctx.JSON(status, generated.ErrorResponse{Message: err.Error()})
+}
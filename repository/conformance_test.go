@@ -0,0 +1,478 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+	"github.com/google/uuid"
+)
+
+// testConformance checks that an implementation of RepositoryInterface
+// behaves like the others. open returns the repository a test runs against;
+// it may be shared with other tests and hold other data, so every test
+// creates its own estates under unique names and codes.
+func testConformance(t *testing.T, open func(t *testing.T) RepositoryInterface) {
+	t.Run("Estate", func(t *testing.T) { conformEstate(t, open(t)) })
+	t.Run("DeleteEstate", func(t *testing.T) { conformDeleteEstate(t, open(t)) })
+	t.Run("Tree", func(t *testing.T) { conformTree(t, open(t)) })
+	t.Run("Block", func(t *testing.T) { conformBlock(t, open(t)) })
+	t.Run("Records", func(t *testing.T) { conformRecords(t, open(t)) })
+	t.Run("Unknown", func(t *testing.T) { conformUnknown(t, open(t)) })
+	t.Run("Tx", func(t *testing.T) { conformTx(t, open(t)) })
+}
+
+func TestMemoryRepository_Conformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) RepositoryInterface {
+		return NewMemoryRepository()
+	})
+}
+
+func TestSQLiteRepository_Conformance(t *testing.T) {
+	testConformance(t, func(t *testing.T) RepositoryInterface {
+		return newTestSQLite(t)
+	})
+}
+
+// TestRepository_Conformance runs against the Postgres database of
+// DATABASE_URL, migrated first. Its data is left behind.
+func TestRepository_Conformance(t *testing.T) {
+	dsn := os.Getenv("DATABASE_URL")
+	if !strings.HasPrefix(dsn, "postgres") {
+		t.Skip("DATABASE_URL is not a Postgres database")
+	}
+	repo, err := NewRepository(NewRepositoryOptions{Dsn: dsn})
+	if err != nil {
+		t.Fatalf("NewRepository() error = %v", err)
+	}
+	r := repo.(*Repository)
+	t.Cleanup(func() { r.Db.Close() })
+	if err := WaitReady(context.Background(), r, time.Second, time.Second); err != nil {
+		t.Fatalf("WaitReady() error = %v", err)
+	}
+	if _, err := r.Migrate(context.Background()); err != nil {
+		t.Fatalf("Repository.Migrate() error = %v", err)
+	}
+	testConformance(t, func(t *testing.T) RepositoryInterface {
+		return r
+	})
+}
+
+// unique returns name with a random suffix, so tests sharing a database do
+// not collide.
+func unique(name string) string {
+	return name + "-" + uuid.NewString()[:8]
+}
+
+func conformEstate(t *testing.T, r RepositoryInterface) {
+	ctx := context.Background()
+	name, code, tag := unique("Sungai"), unique("SG"), unique("north")
+
+	estate := m.Estate{Name: name, Code: code, Tags: []string{tag}, Length: 10, Width: 5,
+		Metadata: map[string]string{"soil": "peat"}, Geo: &m.GeoAnchor{OriginLat: 1, OriginLon: 101, PlotSize: 10}}
+	id, err := r.CreateEstate(ctx, estate)
+	if err != nil || id == "" {
+		t.Fatalf("CreateEstate() = %q, %v", id, err)
+	}
+	got, err := r.GetEstateByID(ctx, id)
+	estate.ID = id
+	if err != nil || !reflect.DeepEqual(got, estate) {
+		t.Errorf("GetEstateByID() = %+v, %v, want %+v", got, err, estate)
+	}
+	if _, err := r.CreateEstate(ctx, m.Estate{Code: code, Length: 1, Width: 1}); err == nil {
+		t.Errorf("CreateEstate() expected error for duplicate code")
+	}
+
+	estate.Name, estate.Tags, estate.Length = name+" Baru", nil, 12
+	if err := r.UpdateEstate(ctx, estate); err != nil {
+		t.Errorf("UpdateEstate() error = %v", err)
+	}
+	if got, _ := r.GetEstateByID(ctx, id); got.Name != name+" Baru" || got.Tags != nil || got.Length != 12 || got.Geo == nil {
+		t.Errorf("UpdateEstate() stored %+v", got)
+	}
+
+	other, _ := r.CreateEstate(ctx, m.Estate{Name: unique("Bukit"), Tags: []string{tag}, Length: 3, Width: 3})
+	found, _ := r.SearchEstates(ctx, m.EstateSearch{Query: code, Tag: tag})
+	if len(found) != 0 {
+		t.Errorf("SearchEstates() = %+v, want nothing", found)
+	}
+	found, _ = r.SearchEstates(ctx, m.EstateSearch{Tag: tag})
+	if len(found) != 1 || found[0].ID != other {
+		t.Errorf("SearchEstates() = %+v, want Bukit", found)
+	}
+	found, _ = r.SearchEstates(ctx, m.EstateSearch{Query: strings.ToUpper(code)})
+	if len(found) != 1 || found[0].ID != id {
+		t.Errorf("SearchEstates() = %+v, want Sungai", found)
+	}
+
+	// other estates may be listed in between
+	listed, _ := r.ListEstates(ctx)
+	var ids []string
+	for _, estate := range listed {
+		ids = append(ids, estate.ID)
+	}
+	first, second := slices.Index(ids, min(id, other)), slices.Index(ids, max(id, other))
+	if first < 0 || second < first || listed[first].Name != "" || listed[first].Length == 0 {
+		t.Errorf("ListEstates() = %+v, want both estates by id", listed)
+	}
+}
+
+func conformDeleteEstate(t *testing.T, r RepositoryInterface) {
+	ctx := context.Background()
+	code := unique("SG")
+	id, _ := r.CreateEstate(ctx, m.Estate{Code: code, Length: 5, Width: 5})
+	r.CreateTree(ctx, id, m.Tree{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy})
+	r.CreateObservation(ctx, m.Observation{EstateID: id, X: 1, Y: 1, Type: "ganoderma", Severity: 2, ObservedAt: time.Now()})
+
+	if deleted, err := r.DeleteEstate(ctx, id); !deleted || err != nil {
+		t.Errorf("DeleteEstate() = %v, %v", deleted, err)
+	}
+	if deleted, _ := r.DeleteEstate(ctx, id); deleted {
+		t.Errorf("DeleteEstate() deleted an estate twice")
+	}
+	if _, err := r.GetEstateByID(ctx, id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetEstateByID() error = %v for a deleted estate, want sql.ErrNoRows", err)
+	}
+	if got, err := r.GetEstateByID(WithDeleted(ctx), id); err != nil || got.DeletedAt == nil {
+		t.Errorf("GetEstateByID() with deleted = %+v, %v", got, err)
+	}
+
+	// the code of a deleted estate can be used again, which blocks its restore
+	reused, _ := r.CreateEstate(ctx, m.Estate{Code: code, Length: 5, Width: 5})
+	if _, err := r.RestoreEstate(ctx, id); err == nil {
+		t.Errorf("RestoreEstate() expected error for used code")
+	}
+	r.DeleteEstate(ctx, reused)
+	if restored, err := r.RestoreEstate(ctx, id); !restored || err != nil {
+		t.Errorf("RestoreEstate() = %v, %v", restored, err)
+	}
+
+	// other tests may have deleted estates too
+	r.DeleteEstate(ctx, id)
+	purged, _ := r.PurgeDeleted(ctx, time.Now().Add(time.Second))
+	if purged.Estates < 2 {
+		t.Errorf("PurgeDeleted() = %+v, want at least 2 estates", purged)
+	}
+	if _, err := r.GetEstateByID(WithDeleted(ctx), id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetEstateByID() error = %v for a purged estate, want sql.ErrNoRows", err)
+	}
+	// everything in the estate goes with it, the audit log stays
+	trees, _ := r.GetTree(WithDeleted(ctx), id)
+	observations, _ := r.GetObservations(ctx, id, m.ObservationFilter{})
+	if len(trees) != 0 || len(observations) != 0 {
+		t.Errorf("PurgeDeleted() left %d trees and %d observations", len(trees), len(observations))
+	}
+	var actions []string
+	entries, _ := r.GetAuditLog(ctx, id)
+	for _, entry := range entries {
+		actions = append(actions, entry.Action)
+	}
+	if want := []string{"delete", "restore", "delete", "purge"}; !reflect.DeepEqual(actions, want) {
+		t.Errorf("GetAuditLog() = %v, want %v", actions, want)
+	}
+}
+
+func conformTree(t *testing.T, r RepositoryInterface) {
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	planted := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	if got, err := r.GetTree(ctx, id); err != nil || len(got) != 0 {
+		t.Errorf("GetTree() = %+v, %v for an empty estate", got, err)
+	}
+	for _, tree := range []m.Tree{
+		{X: 2, Y: 2, Height: 5, Status: m.TreeHealthy},
+		{X: 3, Y: 1, Height: 7, Variety: "DxP", PlantedAt: &planted, Status: m.TreeDiseased},
+		{X: 1, Y: 2, Height: 9, Status: m.TreeHealthy},
+	} {
+		if treeID, err := r.CreateTree(ctx, id, tree); err != nil || treeID == "" || treeID == id {
+			t.Errorf("CreateTree() = %q, %v", treeID, err)
+		}
+	}
+	if _, err := r.CreateTree(ctx, id, m.Tree{X: 2, Y: 2, Height: 5}); err == nil {
+		t.Errorf("CreateTree() expected error for occupied plot")
+	}
+	if _, err := r.CreateTree(ctx, uuid.NewString(), m.Tree{X: 1, Y: 1, Height: 5}); err == nil {
+		t.Errorf("CreateTree() expected error for unknown estate")
+	}
+
+	// row by row
+	got, err := r.GetTree(ctx, id)
+	want := []m.Tree{
+		{X: 3, Y: 1, Height: 7, Variety: "DxP", Status: m.TreeDiseased},
+		{X: 1, Y: 2, Height: 9, Status: m.TreeHealthy},
+		{X: 2, Y: 2, Height: 5, Status: m.TreeHealthy},
+	}
+	if len(got) == 3 {
+		if got[0].PlantedAt == nil || !got[0].PlantedAt.Equal(planted) {
+			t.Errorf("GetTree() planted at %v, want %v", got[0].PlantedAt, planted)
+		}
+		got[0].PlantedAt = nil
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("GetTree() = %+v, %v, want %+v", got, err, want)
+	}
+
+	// streamed in the same order, until fn fails
+	var streamed []m.Tree
+	err = r.EachTree(ctx, id, func(tree m.Tree) error {
+		streamed = append(streamed, tree)
+		if len(streamed) == 2 {
+			return errors.New("enough")
+		}
+		return nil
+	})
+	if err == nil || len(streamed) != 2 || streamed[1] != want[1] {
+		t.Errorf("EachTree() = %+v, %v, want the first 2 trees then enough", streamed, err)
+	}
+
+	if deleted, _ := r.DeleteTree(ctx, id, 2, 2); !deleted {
+		t.Errorf("DeleteTree() found no tree")
+	}
+	if deleted, _ := r.DeleteTree(ctx, id, 2, 2); deleted {
+		t.Errorf("DeleteTree() deleted a tree twice")
+	}
+	if got, _ := r.GetTree(ctx, id); len(got) != 2 {
+		t.Errorf("GetTree() = %d trees after delete, want 2", len(got))
+	}
+	if got, _ := r.GetTree(WithDeleted(ctx), id); len(got) != 3 || got[2].DeletedAt == nil {
+		t.Errorf("GetTree() with deleted = %+v", got)
+	}
+
+	// the plot is free again, but then the deleted tree can not come back
+	r.CreateTree(ctx, id, m.Tree{X: 2, Y: 2, Height: 1, Status: m.TreeReplanted})
+	r.DeleteTree(ctx, id, 2, 2)
+	if restored, _ := r.RestoreTree(ctx, id, 2, 2); !restored {
+		t.Errorf("RestoreTree() found no deleted tree")
+	}
+	if got, _ := r.GetTree(ctx, id); len(got) != 3 || got[2].Height != 1 {
+		t.Errorf("RestoreTree() restored %+v, want the tree deleted last", got)
+	}
+	if restored, _ := r.RestoreTree(ctx, id, 3, 3); restored {
+		t.Errorf("RestoreTree() restored an empty plot")
+	}
+
+	// one plot at a time, the live tree before the deleted one
+	if tree, found, err := r.GetTreeAt(ctx, id, 2, 2); err != nil || !found || tree.Height != 1 || tree.DeletedAt != nil {
+		t.Errorf("GetTreeAt() = %+v, %v, %v, want the restored tree", tree, found, err)
+	}
+	if tree, found, err := r.GetTreeAt(WithDeleted(ctx), id, 2, 2); err != nil || !found || tree.Height != 1 {
+		t.Errorf("GetTreeAt() with deleted = %+v, %v, %v, want the restored tree", tree, found, err)
+	}
+	if _, found, err := r.GetTreeAt(ctx, id, 3, 3); err != nil || found {
+		t.Errorf("GetTreeAt() = %v, %v for an empty plot", found, err)
+	}
+
+	// only trees deleted before the purge go
+	r.PurgeDeleted(ctx, time.Now().Add(time.Second))
+	if got, _ := r.GetTree(WithDeleted(ctx), id); len(got) != 3 {
+		t.Errorf("PurgeDeleted() left %d trees, want 3", len(got))
+	}
+
+	// the trees of several estates come estate by estate, row by row
+	other, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	r.CreateTree(ctx, other, m.Tree{X: 4, Y: 4, Height: 2, Status: m.TreeHealthy})
+	wantPlots := map[string][]m.Point{id: {{X: 3, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}}, other: {{X: 4, Y: 4}}}
+	plots := map[string][]m.Point{}
+	estates := []string{}
+	err = r.EachTreeOfEstates(ctx, []string{other, id, uuid.NewString()}, func(estateID string, tree m.Tree) error {
+		if len(estates) == 0 || estates[len(estates)-1] != estateID {
+			estates = append(estates, estateID)
+		}
+		plots[estateID] = append(plots[estateID], m.Point{X: tree.X, Y: tree.Y})
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(plots, wantPlots) || len(estates) != 2 || estates[0] > estates[1] {
+		t.Errorf("EachTreeOfEstates() = %+v in order %v, %v, want %+v", plots, estates, err, wantPlots)
+	}
+}
+
+func conformBlock(t *testing.T, r RepositoryInterface) {
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	r.CreateTree(ctx, id, m.Tree{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy})
+	r.CreateTree(ctx, id, m.Tree{X: 4, Y: 1, Height: 5, Status: m.TreeHealthy})
+
+	if _, err := r.CreateBlock(ctx, m.Block{EstateID: id, DivisionID: uuid.NewString(), Name: "B1"}); err == nil {
+		t.Errorf("CreateBlock() expected error for unknown division")
+	}
+	divisionID, _ := r.CreateDivision(ctx, m.Division{EstateID: id, Name: "North", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 5, YMax: 5}})
+	r.CreateBlock(ctx, m.Block{EstateID: id, DivisionID: divisionID, Name: "B2", Extent: m.Extent{XMin: 3, YMin: 1, XMax: 5, YMax: 5}})
+	block := m.Block{EstateID: id, DivisionID: divisionID, Name: "B1", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 2, YMax: 5}}
+	block.ID, _ = r.CreateBlock(ctx, block)
+	r.AssignTreesToBlock(ctx, block)
+
+	blocks, _ := r.GetBlocks(ctx, id)
+	if len(blocks) != 2 || blocks[0].ID != block.ID || blocks[1].Name != "B2" {
+		t.Errorf("GetBlocks() = %+v, want by name", blocks)
+	}
+	divisions, _ := r.GetDivisions(ctx, id)
+	if len(divisions) != 1 || divisions[0].ID != divisionID {
+		t.Errorf("GetDivisions() = %+v", divisions)
+	}
+	trees, _ := r.GetTree(ctx, id)
+	if len(trees) != 2 || trees[0].BlockID != block.ID || trees[1].BlockID != "" {
+		t.Errorf("AssignTreesToBlock() = %+v", trees)
+	}
+}
+
+func conformRecords(t *testing.T, r RepositoryInterface) {
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	r.CreateObservation(ctx, m.Observation{EstateID: id, X: 1, Y: 1, Type: "rat", Severity: 1, ObservedAt: day.Add(time.Hour)})
+	r.CreateObservation(ctx, m.Observation{EstateID: id, X: 2, Y: 1, Type: "ganoderma", Severity: 3, ObservedAt: day})
+	since := day.Add(time.Minute)
+	if got, _ := r.GetObservations(ctx, id, m.ObservationFilter{}); len(got) != 2 || got[0].Type != "ganoderma" {
+		t.Errorf("GetObservations() = %+v, want oldest first", got)
+	}
+	if got, _ := r.GetObservations(ctx, id, m.ObservationFilter{Since: &since}); len(got) != 1 || got[0].Type != "rat" {
+		t.Errorf("GetObservations() since = %+v", got)
+	}
+	if got, _ := r.GetObservations(ctx, id, m.ObservationFilter{Type: "rat", X: 2}); len(got) != 0 {
+		t.Errorf("GetObservations() type and x = %+v", got)
+	}
+
+	r.CreateHarvest(ctx, m.Harvest{EstateID: id, X: 2, Y: 1, HarvestedAt: day.AddDate(0, 0, 1), Bunches: 2, Weight: 30})
+	r.CreateHarvest(ctx, m.Harvest{EstateID: id, X: 1, Y: 1, HarvestedAt: day, Bunches: 1, Weight: 20})
+	if _, err := r.CreateHarvest(ctx, m.Harvest{EstateID: id, X: 1, Y: 1, HarvestedAt: day, Bunches: 1}); err == nil {
+		t.Errorf("CreateHarvest() expected error for second harvest on the day")
+	}
+	if got, _ := r.GetHarvests(ctx, id, nil, nil); len(got) != 2 || got[0].X != 1 {
+		t.Errorf("GetHarvests() = %+v, want oldest first", got)
+	}
+	if got, _ := r.GetHarvests(ctx, id, &day, &day); len(got) != 1 || got[0].Weight != 20 || !got[0].HarvestedAt.Equal(day) {
+		t.Errorf("GetHarvests() on the day = %+v", got)
+	}
+	if got, found, err := r.GetHarvestAt(ctx, id, 1, 1, day); err != nil || !found || got.Weight != 20 || !got.HarvestedAt.Equal(day) {
+		t.Errorf("GetHarvestAt() = %+v, %v, %v, want the harvest of the day", got, found, err)
+	}
+	if _, found, err := r.GetHarvestAt(ctx, id, 2, 1, day); err != nil || found {
+		t.Errorf("GetHarvestAt() = %v, %v for a plot harvested another day", found, err)
+	}
+
+	roundID, _ := r.CreateHarvestRound(ctx, m.HarvestRound{EstateID: id, Teams: 2, ScheduledOn: day})
+	if completed, _ := r.CompleteHarvestRound(ctx, uuid.NewString(), roundID); completed {
+		t.Errorf("CompleteHarvestRound() completed the round of another estate")
+	}
+	if completed, _ := r.CompleteHarvestRound(ctx, id, roundID); !completed {
+		t.Errorf("CompleteHarvestRound() found no round")
+	}
+	if completed, _ := r.CompleteHarvestRound(ctx, id, roundID); completed {
+		t.Errorf("CompleteHarvestRound() completed a round twice")
+	}
+	if rounds, _ := r.GetHarvestRounds(ctx, id); len(rounds) != 1 || rounds[0].CompletedAt == nil {
+		t.Errorf("GetHarvestRounds() = %+v", rounds)
+	}
+
+	other, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	programID, _ := r.CreateFertilizerProgram(ctx, m.FertilizerProgram{EstateID: id, Product: "NPK", Dose: 1, Interval: 30, StartOn: day})
+	otherID, _ := r.CreateFertilizerProgram(ctx, m.FertilizerProgram{EstateID: other, Product: "Urea", Dose: 1, Interval: 30, StartOn: day})
+	r.CreateFertilizerApplication(ctx, m.FertilizerApplication{ProgramID: programID, AppliedOn: day.AddDate(0, 0, 30), Trees: 2, Quantity: 2})
+	r.CreateFertilizerApplication(ctx, m.FertilizerApplication{ProgramID: programID, AppliedOn: day, Trees: 2, Quantity: 2})
+	r.CreateFertilizerApplication(ctx, m.FertilizerApplication{ProgramID: otherID, AppliedOn: day, Trees: 1, Quantity: 1})
+	if _, err := r.CreateFertilizerApplication(ctx, m.FertilizerApplication{ProgramID: uuid.NewString(), AppliedOn: day}); err == nil {
+		t.Errorf("CreateFertilizerApplication() expected error for unknown program")
+	}
+	if programs, _ := r.GetFertilizerPrograms(ctx, id); len(programs) != 1 || programs[0].ID != programID {
+		t.Errorf("GetFertilizerPrograms() = %+v", programs)
+	}
+	if applications, _ := r.GetFertilizerApplications(ctx, id); len(applications) != 2 || !applications[0].AppliedOn.Equal(day) {
+		t.Errorf("GetFertilizerApplications() = %+v, want oldest first", applications)
+	}
+}
+
+// conformUnknown checks that an unknown estate is not found, without error
+// except for GetEstateByID.
+func conformUnknown(t *testing.T, r RepositoryInterface) {
+	ctx := context.Background()
+	id := uuid.NewString()
+
+	if _, err := r.GetEstateByID(ctx, id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("GetEstateByID() error = %v, want sql.ErrNoRows", err)
+	}
+	if trees, err := r.GetTree(ctx, id); err != nil || len(trees) != 0 {
+		t.Errorf("GetTree() = %+v, %v", trees, err)
+	}
+	if err := r.EachTreeOfEstates(ctx, nil, func(string, m.Tree) error { return errors.New("tree") }); err != nil {
+		t.Errorf("EachTreeOfEstates() = %v for no estates", err)
+	}
+	if deleted, err := r.DeleteEstate(ctx, id); deleted || err != nil {
+		t.Errorf("DeleteEstate() = %v, %v", deleted, err)
+	}
+	if restored, err := r.RestoreEstate(ctx, id); restored || err != nil {
+		t.Errorf("RestoreEstate() = %v, %v", restored, err)
+	}
+	if deleted, err := r.DeleteTree(ctx, id, 1, 1); deleted || err != nil {
+		t.Errorf("DeleteTree() = %v, %v", deleted, err)
+	}
+	if restored, err := r.RestoreTree(ctx, id, 1, 1); restored || err != nil {
+		t.Errorf("RestoreTree() = %v, %v", restored, err)
+	}
+	if completed, err := r.CompleteHarvestRound(ctx, id, uuid.NewString()); completed || err != nil {
+		t.Errorf("CompleteHarvestRound() = %v, %v", completed, err)
+	}
+	if blocks, err := r.GetBlocks(ctx, id); err != nil || len(blocks) != 0 {
+		t.Errorf("GetBlocks() = %+v, %v", blocks, err)
+	}
+	if entries, err := r.GetAuditLog(ctx, id); err != nil || len(entries) != 0 {
+		t.Errorf("GetAuditLog() = %+v, %v", entries, err)
+	}
+	if found, err := r.SearchEstates(ctx, m.EstateSearch{Query: id}); err != nil || len(found) != 0 {
+		t.Errorf("SearchEstates() = %+v, %v", found, err)
+	}
+}
+
+func conformTx(t *testing.T, r RepositoryInterface) {
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+
+	// the calls in the transaction see each other
+	err := r.WithTx(ctx, func(tx RepositoryInterface) error {
+		if _, err := tx.GetEstateByID(ctx, id); err != nil {
+			return err
+		}
+		if _, err := tx.CreateTree(ctx, id, m.Tree{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy}); err != nil {
+			return err
+		}
+		if trees, _ := tx.GetTree(ctx, id); len(trees) != 1 {
+			t.Errorf("GetTree() in transaction = %+v, want the new tree", trees)
+		}
+		_, err := tx.DeleteTree(ctx, id, 1, 1)
+		return err
+	})
+	if err != nil {
+		t.Errorf("WithTx() error = %v", err)
+	}
+	if trees, _ := r.GetTree(WithDeleted(ctx), id); len(trees) != 1 || trees[0].DeletedAt == nil {
+		t.Errorf("WithTx() committed %+v, want the deleted tree", trees)
+	}
+
+	// nothing is kept when fn fails
+	failed := errors.New("failed")
+	err = r.WithTx(ctx, func(tx RepositoryInterface) error {
+		tx.CreateTree(ctx, id, m.Tree{X: 2, Y: 2, Height: 5, Status: m.TreeHealthy})
+		tx.DeleteEstate(ctx, id)
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("WithTx() error = %v, want %v", err, failed)
+	}
+	if trees, _ := r.GetTree(ctx, id); len(trees) != 0 {
+		t.Errorf("WithTx() kept %+v after failing", trees)
+	}
+	if _, err := r.GetEstateByID(ctx, id); err != nil {
+		t.Errorf("WithTx() kept the delete after failing: %v", err)
+	}
+	if entries, _ := r.GetAuditLog(ctx, id); len(entries) != 1 {
+		t.Errorf("GetAuditLog() = %+v, want only the committed delete", entries)
+	}
+}
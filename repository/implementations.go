@@ -2,48 +2,811 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
 
 	m "github.com/SawitProRecruitment/UserService/types"
+	"github.com/lib/pq"
 )
 
-func (r *Repository) GetEstateByID(ctx context.Context, id string) (estate m.Estate, err error) {
-	err = r.Db.QueryRowContext(ctx, "SELECT * FROM estate WHERE id = $1", id).Scan(&estate.ID, &estate.Length, &estate.Width)
+// encodeBoundary stores the boundary polygon as a JSON array of [x, y] pairs,
+// or NULL when the estate has no boundary.
+func encodeBoundary(boundary []m.Point) (value any, err error) {
+	if boundary == nil {
+		return nil, nil
+	}
+	pairs := make([][2]int, 0, len(boundary))
+	for _, p := range boundary {
+		pairs = append(pairs, [2]int{p.X, p.Y})
+	}
+	encoded, err := json.Marshal(pairs)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func decodeBoundary(value []byte) (boundary []m.Point, err error) {
+	if value == nil {
+		return nil, nil
+	}
+	var pairs [][2]int
+	if err = json.Unmarshal(value, &pairs); err != nil {
+		return nil, err
+	}
+	boundary = make([]m.Point, 0, len(pairs))
+	for _, p := range pairs {
+		boundary = append(boundary, m.Point{X: p[0], Y: p[1]})
+	}
+	return boundary, nil
+}
+
+type includeDeletedKey struct{}
+
+// WithDeleted makes the reads done with the returned context include
+// soft-deleted estates and trees.
+func WithDeleted(ctx context.Context) context.Context {
+	return context.WithValue(ctx, includeDeletedKey{}, true)
+}
+
+// notDeleted returns the condition excluding soft-deleted rows, unless the
+// context asks for them.
+func notDeleted(ctx context.Context) string {
+	if include, _ := ctx.Value(includeDeletedKey{}).(bool); include {
+		return ""
+	}
+	return " AND deleted_at IS NULL"
+}
+
+func nullTime(value sql.NullTime) *time.Time {
+	if !value.Valid {
+		return nil
+	}
+	return &value.Time
+}
+
+// estateColumns are the columns read by scanEstate, in order.
+const estateColumns = "id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at"
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEstate(row rowScanner) (estate m.Estate, err error) {
+	var lat, lon, bearing, plotSize sql.NullFloat64
+	var boundary, metadata []byte
+	var code sql.NullString
+	var plantingYear sql.NullInt64
+	var tags pq.StringArray
+	var deletedAt sql.NullTime
+	err = row.Scan(&estate.ID, &estate.Name, &code, &estate.Owner, &estate.Region, &plantingYear, &tags,
+		&estate.Length, &estate.Width, &lat, &lon, &bearing, &plotSize, &boundary, &metadata, &deletedAt)
 	if err != nil {
 		return
 	}
+	estate.Code = code.String
+	estate.PlantingYear = int(plantingYear.Int64)
+	if len(tags) > 0 {
+		estate.Tags = []string(tags)
+	}
+	estate.DeletedAt = nullTime(deletedAt)
+	if lat.Valid && lon.Valid && bearing.Valid && plotSize.Valid {
+		estate.Geo = &m.GeoAnchor{OriginLat: lat.Float64, OriginLon: lon.Float64, Bearing: bearing.Float64, PlotSize: plotSize.Float64}
+	}
+	estate.Boundary, err = decodeBoundary(boundary)
+	if err != nil {
+		return m.Estate{}, err
+	}
+	estate.Metadata, err = decodeMetadata(metadata)
+	if err != nil {
+		return m.Estate{}, err
+	}
 	return
 }
 
-func (r *Repository) CreateEstate(ctx context.Context, length int, width int) (id string, err error) {
-	err = r.Db.QueryRow(`INSERT INTO estate (length, width) VALUES($1, $2) RETURNING id`, length, width).Scan(&id)
+// encodeMetadata stores the metadata as a JSON object, or NULL when there is
+// none.
+func encodeMetadata(metadata map[string]string) (value any, err error) {
+	if metadata == nil {
+		return nil, nil
+	}
+	encoded, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+	return string(encoded), nil
+}
+
+func decodeMetadata(value []byte) (metadata map[string]string, err error) {
+	if value == nil {
+		return nil, nil
+	}
+	err = json.Unmarshal(value, &metadata)
+	return
+}
+
+func (r *Repository) GetEstateByID(ctx context.Context, id string) (estate m.Estate, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	query := "SELECT " + estateColumns + " FROM estate WHERE id = $1" + notDeleted(ctx)
+	if r.tx != nil {
+		// a resize or delete of the estate waits for the unit of work
+		query += " FOR UPDATE"
+	}
+	return scanEstate(r.reader(id).QueryRowContext(ctx, query, id))
+}
+
+func (r *Repository) CreateEstate(ctx context.Context, estate m.Estate) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	var lat, lon, bearing, plotSize sql.NullFloat64
+	if estate.Geo != nil {
+		lat = sql.NullFloat64{Float64: estate.Geo.OriginLat, Valid: true}
+		lon = sql.NullFloat64{Float64: estate.Geo.OriginLon, Valid: true}
+		bearing = sql.NullFloat64{Float64: estate.Geo.Bearing, Valid: true}
+		plotSize = sql.NullFloat64{Float64: estate.Geo.PlotSize, Valid: true}
+	}
+	boundary, err := encodeBoundary(estate.Boundary)
+	if err != nil {
+		return
+	}
+	metadata, err := encodeMetadata(estate.Metadata)
+	if err != nil {
+		return
+	}
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO estate (name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`,
+		estate.Name, nullString(estate.Code), estate.Owner, estate.Region, nullInt(estate.PlantingYear), textArray(estate.Tags),
+		estate.Length, estate.Width, lat, lon, bearing, plotSize, boundary, metadata).Scan(&id)
 	if err != nil {
 		return
 	}
+	r.wrote(id)
 	return
 }
 
 func (r *Repository) CreateTree(ctx context.Context, estateID string, tree m.Tree) (id string, err error) {
-	sqlStatement := `INSERT INTO tree (estate_id, x, y, height) VALUES($1, $2, $3, $4)`
-	_, err = r.Db.Exec(sqlStatement, estateID, tree.X, tree.Y, tree.Height)
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(estateID)
+
+	sqlStatement := `INSERT INTO tree (estate_id, x, y, height, block_id, variety, planted_at, status)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	err = r.conn().QueryRowContext(ctx, sqlStatement, estateID, tree.X, tree.Y, tree.Height, nullString(tree.BlockID),
+		tree.Variety, tree.PlantedAt, tree.Status).Scan(&id)
+	return
+}
+
+func (r *Repository) GetTree(ctx context.Context, estateID string) (trees []m.Tree, err error) {
+	err = r.EachTree(ctx, estateID, func(tree m.Tree) error {
+		trees = append(trees, tree)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+// EachTree streams the trees of the estate, ordered by row then column. The
+// timeout bounds opening the query only, as reading it lasts as long as fn.
+func (r *Repository) EachTree(ctx context.Context, estateID string, fn func(tree m.Tree) error) (err error) {
+	ctx, opened, done := boundOpen(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, "SELECT "+treeColumns+" FROM tree WHERE estate_id = $1"+notDeleted(ctx)+" ORDER BY y, x", estateID)
 	if err != nil {
 		return
 	}
-	return estateID, err
+	opened()
+	return eachTree(rows, fn)
 }
 
-func (r *Repository) GetTree(ctx context.Context, estateID string) (trees []m.Tree, err error) {
-	rows, err := r.Db.Query("SELECT x,y,height FROM tree WHERE estate_id = $1", estateID)
+// GetTreeAt returns the tree of plot (x, y), the live one before those
+// deleted. found is false when the plot has none.
+func (r *Repository) GetTreeAt(ctx context.Context, estateID string, x int, y int) (tree m.Tree, found bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, "SELECT "+treeColumns+" FROM tree WHERE estate_id = $1 AND x = $2 AND y = $3"+notDeleted(ctx)+treeAtOrder, estateID, x, y)
 	if err != nil {
 		return
 	}
+	err = eachTree(rows, func(t m.Tree) error {
+		tree, found = t, true
+		return nil
+	})
+	return
+}
+
+// treeAtOrder picks the live tree of a plot, or else the one deleted last.
+const treeAtOrder = " ORDER BY deleted_at IS NOT NULL, deleted_at DESC LIMIT 1"
+
+// treeColumns are the columns read by eachTree, in order.
+const treeColumns = "x,y,height,block_id,variety,planted_at,status,deleted_at"
+
+// eachTree calls fn with the tree of every row, closing the rows.
+func eachTree(rows *sql.Rows, fn func(tree m.Tree) error) error {
 	defer rows.Close()
+
 	for rows.Next() {
-		var tree m.Tree
-		err = rows.Scan(&tree.X, &tree.Y, &tree.Height)
+		tree, err := scanTree(rows)
 		if err != nil {
-			continue
+			return err
+		}
+		if err := fn(tree); err != nil {
+			return err
 		}
-		trees = append(trees, tree)
 	}
+	return rows.Err()
+}
+
+// eachEstateTree calls fn with the estate ID and the tree of every row, read
+// from "estate_id,"+treeColumns, closing the rows.
+func eachEstateTree(rows *sql.Rows, fn func(estateID string, tree m.Tree) error) error {
+	defer rows.Close()
+
+	for rows.Next() {
+		var estateID string
+		tree, err := scanTree(rows, &estateID)
+		if err != nil {
+			return err
+		}
+		if err := fn(estateID, tree); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// scanTree reads treeColumns, after the columns of before.
+func scanTree(rows *sql.Rows, before ...any) (tree m.Tree, err error) {
+	var blockID sql.NullString
+	var plantedAt, deletedAt sql.NullTime
+	err = rows.Scan(append(before, &tree.X, &tree.Y, &tree.Height, &blockID, &tree.Variety, &plantedAt, &tree.Status, &deletedAt)...)
+	if err != nil {
+		return m.Tree{}, err
+	}
+	tree.BlockID = blockID.String
+	tree.PlantedAt = nullTime(plantedAt)
+	tree.DeletedAt = nullTime(deletedAt)
+	return tree, nil
+}
+
+// EachTreeOfEstates streams the trees of the estates in a single query,
+// ordered by estate ID then row then column. The timeout bounds opening the
+// query only, like EachTree.
+func (r *Repository) EachTreeOfEstates(ctx context.Context, estateIDs []string, fn func(estateID string, tree m.Tree) error) (err error) {
+	ctx, opened, done := boundOpen(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateIDs...).QueryContext(ctx, "SELECT estate_id,"+treeColumns+" FROM tree WHERE estate_id = ANY($1)"+notDeleted(ctx)+" ORDER BY estate_id, y, x", pq.Array(estateIDs))
+	if err != nil {
+		return
+	}
+	opened()
+	return eachEstateTree(rows, fn)
+}
+
+func (r *Repository) ListEstates(ctx context.Context) (estates []m.Estate, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	query := "SELECT id, length, width FROM estate ORDER BY id"
+	if notDeleted(ctx) != "" {
+		query = "SELECT id, length, width FROM estate WHERE deleted_at IS NULL ORDER BY id"
+	}
+	rows, err := r.reader().QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var estate m.Estate
+		err = rows.Scan(&estate.ID, &estate.Length, &estate.Width)
+		if err != nil {
+			return nil, err
+		}
+		estates = append(estates, estate)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return estates, nil
+}
+
+// nullString stores an empty string as NULL.
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// textArray stores a nil slice as an empty array.
+func textArray(values []string) any {
+	if values == nil {
+		values = []string{}
+	}
+	return pq.Array(values)
+}
+
+// nullInt stores zero as NULL.
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
+
+func (r *Repository) CreateDivision(ctx context.Context, division m.Division) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(division.EstateID)
+
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO division (estate_id, name, supervisor, x_min, y_min, x_max, y_max) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		division.EstateID, division.Name, division.Supervisor,
+		division.Extent.XMin, division.Extent.YMin, division.Extent.XMax, division.Extent.YMax).Scan(&id)
 	return
 }
+
+func (r *Repository) GetDivisions(ctx context.Context, estateID string) (divisions []m.Division, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, "SELECT id, estate_id, name, supervisor, x_min, y_min, x_max, y_max FROM division WHERE estate_id = $1 ORDER BY name", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var division m.Division
+		err = rows.Scan(&division.ID, &division.EstateID, &division.Name, &division.Supervisor,
+			&division.Extent.XMin, &division.Extent.YMin, &division.Extent.XMax, &division.Extent.YMax)
+		if err != nil {
+			return nil, err
+		}
+		divisions = append(divisions, division)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return divisions, nil
+}
+
+func (r *Repository) CreateBlock(ctx context.Context, block m.Block) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(block.EstateID)
+
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO block (estate_id, division_id, name, supervisor, x_min, y_min, x_max, y_max) VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`,
+		block.EstateID, block.DivisionID, block.Name, block.Supervisor,
+		block.Extent.XMin, block.Extent.YMin, block.Extent.XMax, block.Extent.YMax).Scan(&id)
+	return
+}
+
+func (r *Repository) GetBlocks(ctx context.Context, estateID string) (blocks []m.Block, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, "SELECT id, estate_id, division_id, name, supervisor, x_min, y_min, x_max, y_max FROM block WHERE estate_id = $1 ORDER BY name", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var block m.Block
+		err = rows.Scan(&block.ID, &block.EstateID, &block.DivisionID, &block.Name, &block.Supervisor,
+			&block.Extent.XMin, &block.Extent.YMin, &block.Extent.XMax, &block.Extent.YMax)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// AssignTreesToBlock moves the trees already planted inside the extent of the
+// block into it.
+func (r *Repository) AssignTreesToBlock(ctx context.Context, block m.Block) (err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(block.EstateID)
+
+	_, err = r.conn().ExecContext(ctx, `UPDATE tree SET block_id = $1 WHERE estate_id = $2 AND x BETWEEN $3 AND $4 AND y BETWEEN $5 AND $6`,
+		block.ID, block.EstateID, block.Extent.XMin, block.Extent.XMax, block.Extent.YMin, block.Extent.YMax)
+	return
+}
+
+// UpdateEstate stores the descriptive fields, metadata and size of the
+// estate.
+func (r *Repository) UpdateEstate(ctx context.Context, estate m.Estate) (err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(estate.ID)
+
+	metadata, err := encodeMetadata(estate.Metadata)
+	if err != nil {
+		return
+	}
+	_, err = r.conn().ExecContext(ctx, `UPDATE estate SET name = $2, code = $3, owner = $4, region = $5, planting_year = $6, tags = $7,
+		metadata = $8, length = $9, width = $10 WHERE id = $1`,
+		estate.ID, estate.Name, nullString(estate.Code), estate.Owner, estate.Region, nullInt(estate.PlantingYear), textArray(estate.Tags),
+		metadata, estate.Length, estate.Width)
+	return
+}
+
+// SearchEstates lists the estates whose name or code contains the query and
+// that carry the tag, ordered by name. Empty criteria match every estate.
+func (r *Repository) SearchEstates(ctx context.Context, search m.EstateSearch) (estates []m.Estate, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	query := "SELECT " + estateColumns + ` FROM estate
+		WHERE ($1 = '' OR name ILIKE '%' || $1 || '%' OR code ILIKE '%' || $1 || '%')
+		AND ($2 = '' OR $2 = ANY(tags))` + notDeleted(ctx) + " ORDER BY name, id"
+	rows, err := r.reader().QueryContext(ctx, query, search.Query, search.Tag)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var estate m.Estate
+		estate, err = scanEstate(rows)
+		if err != nil {
+			return nil, err
+		}
+		estates = append(estates, estate)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return estates, nil
+}
+
+// DeleteEstate soft-deletes the estate and records it in the audit log. It
+// reports whether the estate was found.
+func (r *Repository) DeleteEstate(ctx context.Context, id string) (deleted bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(id)
+
+	result, err := r.conn().ExecContext(ctx, `WITH deleted AS (UPDATE estate SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL RETURNING id)
+		INSERT INTO audit_log (estate_id, entity, action) SELECT id, 'estate', 'delete' FROM deleted`, id)
+	return affected(result, err)
+}
+
+// RestoreEstate undoes the soft-delete of the estate and records it in the
+// audit log. It reports whether a deleted estate was found.
+func (r *Repository) RestoreEstate(ctx context.Context, id string) (restored bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(id)
+
+	result, err := r.conn().ExecContext(ctx, `WITH restored AS (UPDATE estate SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL RETURNING id)
+		INSERT INTO audit_log (estate_id, entity, action) SELECT id, 'estate', 'restore' FROM restored`, id)
+	return affected(result, err)
+}
+
+// DeleteTree soft-deletes the trees of plot (x, y) and records it in the
+// audit log. It reports whether a tree was found.
+func (r *Repository) DeleteTree(ctx context.Context, estateID string, x int, y int) (deleted bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(estateID)
+
+	result, err := r.conn().ExecContext(ctx, `WITH deleted AS (UPDATE tree SET deleted_at = now() WHERE estate_id = $1 AND x = $2 AND y = $3 AND deleted_at IS NULL RETURNING estate_id)
+		INSERT INTO audit_log (estate_id, entity, action, x, y) SELECT DISTINCT estate_id, 'tree', 'delete', $2::INT, $3::INT FROM deleted`, estateID, x, y)
+	return affected(result, err)
+}
+
+// RestoreTree restores the trees of plot (x, y) deleted last and records it
+// in the audit log. It reports whether a deleted tree was found.
+func (r *Repository) RestoreTree(ctx context.Context, estateID string, x int, y int) (restored bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(estateID)
+
+	result, err := r.conn().ExecContext(ctx, `WITH restored AS (UPDATE tree SET deleted_at = NULL WHERE estate_id = $1 AND x = $2 AND y = $3
+			AND deleted_at = (SELECT max(deleted_at) FROM tree WHERE estate_id = $1 AND x = $2 AND y = $3) RETURNING estate_id)
+		INSERT INTO audit_log (estate_id, entity, action, x, y) SELECT DISTINCT estate_id, 'tree', 'restore', $2::INT, $3::INT FROM restored`, estateID, x, y)
+	return affected(result, err)
+}
+
+func affected(result sql.Result, err error) (bool, error) {
+	if err != nil {
+		return false, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rows > 0, nil
+}
+
+// PurgeDeleted hard-deletes the trees and estates soft-deleted before the
+// given time and records them in the audit log.
+func (r *Repository) PurgeDeleted(ctx context.Context, before time.Time) (purged m.PurgeResult, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote("")
+
+	result, err := r.conn().ExecContext(ctx, `WITH purged AS (DELETE FROM tree WHERE deleted_at < $1 RETURNING estate_id, x, y)
+		INSERT INTO audit_log (estate_id, entity, action, x, y) SELECT estate_id, 'tree', 'purge', x, y FROM purged`, before)
+	if err != nil {
+		return
+	}
+	trees, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+
+	result, err = r.conn().ExecContext(ctx, `WITH purged AS (DELETE FROM estate WHERE deleted_at < $1 RETURNING id)
+		INSERT INTO audit_log (estate_id, entity, action) SELECT id, 'estate', 'purge' FROM purged`, before)
+	if err != nil {
+		return
+	}
+	estates, err := result.RowsAffected()
+	if err != nil {
+		return
+	}
+	return m.PurgeResult{Estates: int(estates), Trees: int(trees)}, nil
+}
+
+func (r *Repository) GetAuditLog(ctx context.Context, estateID string) (entries []m.AuditEntry, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, "SELECT estate_id, entity, action, x, y, created_at FROM audit_log WHERE estate_id = $1 ORDER BY id", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry m.AuditEntry
+		var x, y sql.NullInt64
+		err = rows.Scan(&entry.EstateID, &entry.Entity, &entry.Action, &x, &y, &entry.At)
+		if err != nil {
+			return nil, err
+		}
+		entry.X, entry.Y = int(x.Int64), int(y.Int64)
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *Repository) CreateObservation(ctx context.Context, observation m.Observation) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(observation.EstateID)
+
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO observation (estate_id, x, y, type, severity, observed_at, notes) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		observation.EstateID, observation.X, observation.Y, observation.Type, observation.Severity, observation.ObservedAt, observation.Notes).Scan(&id)
+	return
+}
+
+// GetObservations returns the observations of the estate matching the filter,
+// oldest first.
+func (r *Repository) GetObservations(ctx context.Context, estateID string, filter m.ObservationFilter) (observations []m.Observation, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, `SELECT id, estate_id, x, y, type, severity, observed_at, notes FROM observation
+		WHERE estate_id = $1 AND ($2 = '' OR type = $2) AND ($3 = 0 OR x = $3) AND ($4 = 0 OR y = $4)
+		AND ($5::timestamptz IS NULL OR observed_at >= $5) ORDER BY observed_at, id`,
+		estateID, filter.Type, filter.X, filter.Y, filter.Since)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var observation m.Observation
+		err = rows.Scan(&observation.ID, &observation.EstateID, &observation.X, &observation.Y,
+			&observation.Type, &observation.Severity, &observation.ObservedAt, &observation.Notes)
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, observation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return observations, nil
+}
+
+func (r *Repository) CreateHarvest(ctx context.Context, harvest m.Harvest) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(harvest.EstateID)
+
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO harvest (estate_id, x, y, harvested_at, bunches, weight) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+		harvest.EstateID, harvest.X, harvest.Y, harvest.HarvestedAt, harvest.Bunches, harvest.Weight).Scan(&id)
+	return
+}
+
+// GetHarvests returns the harvests of the estate between from and to, both
+// included when set, oldest first.
+func (r *Repository) GetHarvests(ctx context.Context, estateID string, from *time.Time, to *time.Time) (harvests []m.Harvest, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, `SELECT id, estate_id, x, y, harvested_at, bunches, weight FROM harvest
+		WHERE estate_id = $1 AND ($2::date IS NULL OR harvested_at >= $2) AND ($3::date IS NULL OR harvested_at <= $3)
+		ORDER BY harvested_at, y, x`, estateID, from, to)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var harvest m.Harvest
+		err = rows.Scan(&harvest.ID, &harvest.EstateID, &harvest.X, &harvest.Y, &harvest.HarvestedAt, &harvest.Bunches, &harvest.Weight)
+		if err != nil {
+			return nil, err
+		}
+		harvests = append(harvests, harvest)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return harvests, nil
+}
+
+// GetHarvestAt returns the harvest of plot (x, y) on the day. found is false
+// when the plot was not harvested that day.
+func (r *Repository) GetHarvestAt(ctx context.Context, estateID string, x int, y int, day time.Time) (harvest m.Harvest, found bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, `SELECT id, estate_id, x, y, harvested_at, bunches, weight FROM harvest
+		WHERE estate_id = $1 AND x = $2 AND y = $3 AND harvested_at = $4`, estateID, x, y, day)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&harvest.ID, &harvest.EstateID, &harvest.X, &harvest.Y, &harvest.HarvestedAt, &harvest.Bunches, &harvest.Weight)
+		if err != nil {
+			return m.Harvest{}, false, err
+		}
+		found = true
+	}
+	if err = rows.Err(); err != nil {
+		return m.Harvest{}, false, err
+	}
+	return harvest, found, nil
+}
+
+func (r *Repository) CreateHarvestRound(ctx context.Context, round m.HarvestRound) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(round.EstateID)
+
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO harvest_round (estate_id, block_id, teams, scheduled_on) VALUES($1, $2, $3, $4) RETURNING id`,
+		round.EstateID, nullString(round.BlockID), round.Teams, round.ScheduledOn).Scan(&id)
+	return
+}
+
+// GetHarvestRounds returns the harvest rounds of the estate, earliest first.
+func (r *Repository) GetHarvestRounds(ctx context.Context, estateID string) (rounds []m.HarvestRound, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, `SELECT id, estate_id, block_id, teams, scheduled_on, completed_at FROM harvest_round
+		WHERE estate_id = $1 ORDER BY scheduled_on, id`, estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var round m.HarvestRound
+		var blockID sql.NullString
+		var completedAt sql.NullTime
+		err = rows.Scan(&round.ID, &round.EstateID, &blockID, &round.Teams, &round.ScheduledOn, &completedAt)
+		if err != nil {
+			return nil, err
+		}
+		round.BlockID = blockID.String
+		round.CompletedAt = nullTime(completedAt)
+		rounds = append(rounds, round)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// CompleteHarvestRound marks the round as completed now. It reports whether
+// an uncompleted round was found.
+func (r *Repository) CompleteHarvestRound(ctx context.Context, estateID string, roundID string) (completed bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(estateID)
+
+	result, err := r.conn().ExecContext(ctx, "UPDATE harvest_round SET completed_at = now() WHERE id = $1 AND estate_id = $2 AND completed_at IS NULL", roundID, estateID)
+	return affected(result, err)
+}
+
+func (r *Repository) CreateFertilizerProgram(ctx context.Context, program m.FertilizerProgram) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote(program.EstateID)
+
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO fertilizer_program (estate_id, block_id, product, dose, interval_days, start_on) VALUES($1, $2, $3, $4, $5, $6) RETURNING id`,
+		program.EstateID, nullString(program.BlockID), program.Product, program.Dose, program.Interval, program.StartOn).Scan(&id)
+	return
+}
+
+func (r *Repository) GetFertilizerPrograms(ctx context.Context, estateID string) (programs []m.FertilizerProgram, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, "SELECT id, estate_id, block_id, product, dose, interval_days, start_on FROM fertilizer_program WHERE estate_id = $1 ORDER BY product, id", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var program m.FertilizerProgram
+		var blockID sql.NullString
+		err = rows.Scan(&program.ID, &program.EstateID, &blockID, &program.Product, &program.Dose, &program.Interval, &program.StartOn)
+		if err != nil {
+			return nil, err
+		}
+		program.BlockID = blockID.String
+		programs = append(programs, program)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return programs, nil
+}
+
+func (r *Repository) CreateFertilizerApplication(ctx context.Context, application m.FertilizerApplication) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+	r.wrote("")
+
+	err = r.conn().QueryRowContext(ctx, `INSERT INTO fertilizer_application (program_id, applied_on, trees, quantity) VALUES($1, $2, $3, $4) RETURNING id`,
+		application.ProgramID, application.AppliedOn, application.Trees, application.Quantity).Scan(&id)
+	return
+}
+
+// GetFertilizerApplications returns the applications of every program of the
+// estate, oldest first.
+func (r *Repository) GetFertilizerApplications(ctx context.Context, estateID string) (applications []m.FertilizerApplication, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.reader(estateID).QueryContext(ctx, `SELECT a.id, a.program_id, a.applied_on, a.trees, a.quantity FROM fertilizer_application a
+		JOIN fertilizer_program p ON p.id = a.program_id WHERE p.estate_id = $1 ORDER BY a.applied_on, a.id`, estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var application m.FertilizerApplication
+		err = rows.Scan(&application.ID, &application.ProgramID, &application.AppliedOn, &application.Trees, &application.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
@@ -2,13 +2,16 @@ package repository
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"reflect"
 	"regexp"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	m "github.com/SawitProRecruitment/UserService/types"
+	"github.com/lib/pq"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -46,8 +49,76 @@ func TestRepository_GetEstateByID(t *testing.T) {
 			wantEstate: m.Estate{ID: "aaa", Length: 2, Width: 2},
 			wantErr:    false,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				rows := sqlmock.NewRows([]string{"id", "length", "width"}).AddRow("aaa", 2, 2)
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM estate WHERE id = $1")).WithArgs("aaa").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"id", "name", "code", "owner", "region", "planting_year", "tags", "length", "width", "origin_lat", "origin_lon", "bearing", "plot_size", "boundary", "metadata", "deleted_at"}).AddRow("aaa", "", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, nil, nil, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at FROM estate WHERE id = $1 AND deleted_at IS NULL")).WithArgs("aaa").WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when estate is georeferenced, return estate with anchor",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "aaa",
+			},
+			wantEstate: m.Estate{ID: "aaa", Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: 101.5, Bearing: 90, PlotSize: 9}},
+			wantErr:    false,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"id", "name", "code", "owner", "region", "planting_year", "tags", "length", "width", "origin_lat", "origin_lon", "bearing", "plot_size", "boundary", "metadata", "deleted_at"}).AddRow("aaa", "", nil, "", "", nil, "{}", 2, 2, 1.5, 101.5, 90.0, 9.0, nil, nil, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at FROM estate WHERE id = $1 AND deleted_at IS NULL")).WithArgs("aaa").WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when estate has boundary, return estate with boundary",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "aaa",
+			},
+			wantEstate: m.Estate{ID: "aaa", Length: 2, Width: 2, Boundary: []m.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}}},
+			wantErr:    false,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"id", "name", "code", "owner", "region", "planting_year", "tags", "length", "width", "origin_lat", "origin_lon", "bearing", "plot_size", "boundary", "metadata", "deleted_at"}).AddRow("aaa", "", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, []byte("[[1,1],[2,1],[1,2]]"), nil, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at FROM estate WHERE id = $1 AND deleted_at IS NULL")).WithArgs("aaa").WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when estate has name and metadata, return them",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "aaa",
+			},
+			wantEstate: m.Estate{ID: "aaa", Name: "Sungai Lilin", Length: 2, Width: 2, Metadata: map[string]string{"company": "sawitpro"}},
+			wantErr:    false,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"id", "name", "code", "owner", "region", "planting_year", "tags", "length", "width", "origin_lat", "origin_lon", "bearing", "plot_size", "boundary", "metadata", "deleted_at"}).AddRow("aaa", "Sungai Lilin", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, nil, []byte(`{"company":"sawitpro"}`), nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at FROM estate WHERE id = $1 AND deleted_at IS NULL")).WithArgs("aaa").WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when boundary is corrupted, return error",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx: context.Background(),
+				id:  "aaa",
+			},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"id", "name", "code", "owner", "region", "planting_year", "tags", "length", "width", "origin_lat", "origin_lon", "bearing", "plot_size", "boundary", "metadata", "deleted_at"}).AddRow("aaa", "", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, []byte("{"), nil, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at FROM estate WHERE id = $1 AND deleted_at IS NULL")).WithArgs("aaa").WillReturnRows(rows)
 				return mock
 			},
 		},
@@ -63,7 +134,7 @@ func TestRepository_GetEstateByID(t *testing.T) {
 			wantEstate: m.Estate{},
 			wantErr:    true,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM estate WHERE id = $1")).WithArgs("aaa").WillReturnError(errors.New(""))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at FROM estate WHERE id = $1 AND deleted_at IS NULL")).WithArgs("aaa").WillReturnError(errors.New(""))
 				return mock
 			},
 		},
@@ -102,8 +173,7 @@ func TestRepository_CreateEstate(t *testing.T) {
 
 	type args struct {
 		ctx    context.Context
-		length int
-		width  int
+		estate m.Estate
 	}
 	tests := []struct {
 		name    string
@@ -120,14 +190,68 @@ func TestRepository_CreateEstate(t *testing.T) {
 			},
 			args: args{
 				ctx:    context.Background(),
-				length: 2,
-				width:  2,
+				estate: m.Estate{Length: 2, Width: 2},
+			},
+			wantId:  "aaa",
+			wantErr: false,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("aaa")
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estate (name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`)).WithArgs("", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, nil, nil).WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when estate is georeferenced, store the anchor",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: 101.5, Bearing: 90, PlotSize: 9}},
+			},
+			wantId:  "aaa",
+			wantErr: false,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("aaa")
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estate (name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`)).WithArgs("", nil, "", "", nil, "{}", 2, 2, 1.5, 101.5, 90.0, 9.0, nil, nil).WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when estate has boundary, store the boundary",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 2, Width: 2, Boundary: []m.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}}},
+			},
+			wantId:  "aaa",
+			wantErr: false,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"id"}).AddRow("aaa")
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estate (name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`)).WithArgs("", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, "[[1,1],[2,1],[1,2]]", nil).WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when estate has name and metadata, store them",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Name: "Sungai Lilin", Length: 2, Width: 2, Metadata: map[string]string{"company": "sawitpro"}},
 			},
 			wantId:  "aaa",
 			wantErr: false,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
 				rows := sqlmock.NewRows([]string{"id"}).AddRow("aaa")
-				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO estate (length, width) VALUES($1, $2) RETURNING id")).WithArgs(2, 2).WillReturnRows(rows)
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estate (name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`)).WithArgs("Sungai Lilin", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, nil, `{"company":"sawitpro"}`).WillReturnRows(rows)
 				return mock
 			},
 		},
@@ -138,13 +262,13 @@ func TestRepository_CreateEstate(t *testing.T) {
 			},
 			args: args{
 				ctx:    context.Background(),
-				length: 2,
-				width:  2,
+				estate: m.Estate{Length: 2, Width: 2},
 			},
 			wantId:  "",
 			wantErr: true,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO estate (length, width) VALUES($1, $2) RETURNING id")).WithArgs(2, 2).WillReturnError(errors.New("new"))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO estate (name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14) RETURNING id`)).WithArgs("", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, nil, nil).WillReturnError(errors.New("new"))
 				return mock
 			},
 		},
@@ -159,7 +283,7 @@ func TestRepository_CreateEstate(t *testing.T) {
 				Db: db,
 			}
 
-			gotId, err := r.CreateEstate(tt.args.ctx, tt.args.length, tt.args.width)
+			gotId, err := r.CreateEstate(tt.args.ctx, tt.args.estate)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Repository.CreateEstate() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -182,6 +306,8 @@ func TestRepository_CreateTree(t *testing.T) {
 		client sqlmock.Sqlmock
 	}
 
+	planted := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+
 	type args struct {
 		ctx      context.Context
 		estateID string
@@ -196,19 +322,20 @@ func TestRepository_CreateTree(t *testing.T) {
 		mock    func(ctrl *gomock.Controller) sqlmock.Sqlmock
 	}{
 		{
-			name: "when all good, return estate id",
+			name: "when all good, return tree id",
 			fields: fields{
 				client: mock,
 			},
 			args: args{
 				ctx:      context.Background(),
 				estateID: "aaa",
-				tree:     m.Tree{X: 1, Y: 2, Height: 3},
+				tree:     m.Tree{X: 1, Y: 2, Height: 3, Variety: "DxP", PlantedAt: &planted, Status: m.TreeHealthy},
 			},
-			wantId:  "aaa",
+			wantId:  "ttt",
 			wantErr: false,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tree (estate_id, x, y, height) VALUES($1, $2, $3, $4)")).WithArgs("aaa", 1, 2, 3).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO tree (estate_id, x, y, height, block_id, variety, planted_at, status)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`)).WithArgs("aaa", 1, 2, 3, nil, "DxP", planted, m.TreeHealthy).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ttt"))
 				return mock
 			},
 		},
@@ -225,7 +352,8 @@ func TestRepository_CreateTree(t *testing.T) {
 			wantId:  "",
 			wantErr: true,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				mock.ExpectExec(regexp.QuoteMeta("INSERT INTO tree (estate_id, x, y, height) VALUES($1, $2, $3, $4)")).WithArgs("aaa", 1, 2, 3).WillReturnError(errors.New("create tree"))
+				mock.ExpectQuery(regexp.QuoteMeta(`INSERT INTO tree (estate_id, x, y, height, block_id, variety, planted_at, status)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`)).WithArgs("aaa", 1, 2, 3, nil, "", nil, "").WillReturnError(errors.New("create tree"))
 				return mock
 			},
 		},
@@ -263,6 +391,8 @@ func TestRepository_GetTree(t *testing.T) {
 		client sqlmock.Sqlmock
 	}
 
+	planted := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+
 	type args struct {
 		ctx      context.Context
 		estateID string
@@ -284,11 +414,11 @@ func TestRepository_GetTree(t *testing.T) {
 				ctx:      context.Background(),
 				estateID: "aaa",
 			},
-			wantTrees: []m.Tree{{X: 1, Y: 1, Height: 1}, {X: 2, Y: 2, Height: 2}},
+			wantTrees: []m.Tree{{X: 1, Y: 1, Height: 1, Status: m.TreeHealthy}, {X: 2, Y: 2, Height: 2, BlockID: "bbb", Variety: "DxP", PlantedAt: &planted, Status: m.TreeDiseased}},
 			wantErr:   false,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				rows := sqlmock.NewRows([]string{"x", "y", "height"}).AddRow(1, 1, 1).AddRow(2, 2, 2)
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height FROM tree WHERE estate_id = $1")).WithArgs("aaa").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).AddRow(1, 1, 1, nil, "", nil, m.TreeHealthy, nil).AddRow(2, 2, 2, "bbb", "DxP", planted, m.TreeDiseased, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1 AND deleted_at IS NULL ORDER BY y, x")).WithArgs("aaa").WillReturnRows(rows)
 				return mock
 			},
 		},
@@ -304,12 +434,12 @@ func TestRepository_GetTree(t *testing.T) {
 			wantTrees: []m.Tree(nil),
 			wantErr:   true,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height FROM tree WHERE estate_id = $1")).WithArgs("aaa").WillReturnError(errors.New("tree"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1 AND deleted_at IS NULL ORDER BY y, x")).WithArgs("aaa").WillReturnError(errors.New("tree"))
 				return mock
 			},
 		},
 		{
-			name: "when some data is corrupted, skip it",
+			name: "when a row does not scan, return error",
 			fields: fields{
 				client: mock,
 			},
@@ -317,11 +447,28 @@ func TestRepository_GetTree(t *testing.T) {
 				ctx:      context.Background(),
 				estateID: "aaa",
 			},
-			wantTrees: []m.Tree{{X: 1, Y: 1, Height: 1}, {X: 2, Y: 2, Height: 2}},
-			wantErr:   false,
+			wantTrees: nil,
+			wantErr:   true,
+			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
+				rows := sqlmock.NewRows([]string{"x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).AddRow(1, 1, 1, nil, "", nil, m.TreeHealthy, nil).AddRow("two", 2, 2, "bbb", "DxP", planted, m.TreeDiseased, nil)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1 AND deleted_at IS NULL ORDER BY y, x")).WithArgs("aaa").WillReturnRows(rows)
+				return mock
+			},
+		},
+		{
+			name: "when the rows fail, return error",
+			fields: fields{
+				client: mock,
+			},
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantTrees: nil,
+			wantErr:   true,
 			mock: func(ctrl *gomock.Controller) sqlmock.Sqlmock {
-				rows := sqlmock.NewRows([]string{"x", "y", "height"}).AddRow(1, 1, 1).AddRow(2, 2, 2).RowError(3, errors.New("row"))
-				mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height FROM tree WHERE estate_id = $1")).WithArgs("aaa").WillReturnRows(rows)
+				rows := sqlmock.NewRows([]string{"x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).AddRow(1, 1, 1, nil, "", nil, m.TreeHealthy, nil).AddRow(2, 2, 2, "bbb", "DxP", planted, m.TreeDiseased, nil).RowError(1, errors.New("row"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1 AND deleted_at IS NULL ORDER BY y, x")).WithArgs("aaa").WillReturnRows(rows)
 				return mock
 			},
 		},
@@ -347,3 +494,812 @@ func TestRepository_GetTree(t *testing.T) {
 		})
 	}
 }
+
+func TestRepository_EachTree(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	// the first error of fn stops the rows
+	rows := sqlmock.NewRows([]string{"x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).
+		AddRow(1, 1, 1, nil, "", nil, m.TreeHealthy, nil).AddRow(2, 1, 2, nil, "", nil, m.TreeHealthy, nil).AddRow(3, 1, 3, nil, "", nil, m.TreeHealthy, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1")).WithArgs("aaa").WillReturnRows(rows).RowsWillBeClosed()
+	seen := 0
+	err = r.EachTree(context.Background(), "aaa", func(tree m.Tree) error {
+		seen++
+		if tree.X == 2 {
+			return errors.New("full")
+		}
+		return nil
+	})
+	if err == nil || err.Error() != "full" || seen != 2 {
+		t.Errorf("Repository.EachTree() = %v after %d trees, want full after 2", err, seen)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Repository.EachTree() %v", err)
+	}
+}
+
+func TestRepository_EachTreeOfEstates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	rows := sqlmock.NewRows([]string{"estate_id", "x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).
+		AddRow("aaa", 1, 1, 1, nil, "", nil, m.TreeHealthy, nil).AddRow("bbb", 2, 1, 2, nil, "", nil, m.TreeHealthy, nil)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT estate_id,x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = ANY($1) AND deleted_at IS NULL ORDER BY estate_id, y, x")).
+		WithArgs(pq.Array([]string{"aaa", "bbb"})).WillReturnRows(rows).RowsWillBeClosed()
+	got := map[string][]m.Tree{}
+	err = r.EachTreeOfEstates(context.Background(), []string{"aaa", "bbb"}, func(estateID string, tree m.Tree) error {
+		got[estateID] = append(got[estateID], tree)
+		return nil
+	})
+	want := map[string][]m.Tree{"aaa": {{X: 1, Y: 1, Height: 1, Status: m.TreeHealthy}}, "bbb": {{X: 2, Y: 1, Height: 2, Status: m.TreeHealthy}}}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.EachTreeOfEstates() = %+v, %v, want %+v", got, err, want)
+	}
+
+	mock.ExpectQuery("SELECT estate_id").WillReturnError(errors.New("tree"))
+	if err := r.EachTreeOfEstates(context.Background(), []string{"aaa"}, func(string, m.Tree) error { return nil }); err == nil {
+		t.Errorf("Repository.EachTreeOfEstates() expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Repository.EachTreeOfEstates() %v", err)
+	}
+}
+
+func TestRepository_GetTreeAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+	query := regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1 AND x = $2 AND y = $3 AND deleted_at IS NULL ORDER BY deleted_at IS NOT NULL, deleted_at DESC LIMIT 1")
+
+	mock.ExpectQuery(query).WithArgs("aaa", 2, 3).WillReturnRows(sqlmock.NewRows([]string{"x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).AddRow(2, 3, 4, nil, "", nil, m.TreeHealthy, nil))
+	if tree, found, err := r.GetTreeAt(context.Background(), "aaa", 2, 3); err != nil || !found || tree != (m.Tree{X: 2, Y: 3, Height: 4, Status: m.TreeHealthy}) {
+		t.Errorf("Repository.GetTreeAt() = %+v, %v, %v", tree, found, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa", 2, 3).WillReturnRows(sqlmock.NewRows([]string{"x"}))
+	if _, found, err := r.GetTreeAt(context.Background(), "aaa", 2, 3); err != nil || found {
+		t.Errorf("Repository.GetTreeAt() = %v, %v for an empty plot", found, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa", 2, 3).WillReturnError(errors.New("tree"))
+	if _, _, err := r.GetTreeAt(context.Background(), "aaa", 2, 3); err == nil {
+		t.Errorf("Repository.GetTreeAt() expected error")
+	}
+}
+
+func TestRepository_GetHarvestAt(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	query := regexp.QuoteMeta(`SELECT id, estate_id, x, y, harvested_at, bunches, weight FROM harvest
+		WHERE estate_id = $1 AND x = $2 AND y = $3 AND harvested_at = $4`)
+
+	mock.ExpectQuery(query).WithArgs("aaa", 2, 3, day).WillReturnRows(sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "harvested_at", "bunches", "weight"}).AddRow("hhh", "aaa", 2, 3, day, 2, 41.5))
+	want := m.Harvest{ID: "hhh", EstateID: "aaa", X: 2, Y: 3, HarvestedAt: day, Bunches: 2, Weight: 41.5}
+	if harvest, found, err := r.GetHarvestAt(context.Background(), "aaa", 2, 3, day); err != nil || !found || harvest != want {
+		t.Errorf("Repository.GetHarvestAt() = %+v, %v, %v", harvest, found, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa", 2, 3, day).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	if _, found, err := r.GetHarvestAt(context.Background(), "aaa", 2, 3, day); err != nil || found {
+		t.Errorf("Repository.GetHarvestAt() = %v, %v for a plot not harvested", found, err)
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa", 2, 3, day).WillReturnError(errors.New("harvest"))
+	if _, _, err := r.GetHarvestAt(context.Background(), "aaa", 2, 3, day); err == nil {
+		t.Errorf("Repository.GetHarvestAt() expected error")
+	}
+}
+
+func TestRepository_ListEstates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		name        string
+		wantEstates []m.Estate
+		wantErr     bool
+		mock        func()
+	}{
+		{
+			name:        "when all good, return estates",
+			wantEstates: []m.Estate{{ID: "aaa", Length: 2, Width: 3}, {ID: "bbb", Length: 4, Width: 5}},
+			wantErr:     false,
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "length", "width"}).AddRow("aaa", 2, 3).AddRow("bbb", 4, 5)
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, length, width FROM estate WHERE deleted_at IS NULL ORDER BY id")).WillReturnRows(rows)
+			},
+		},
+		{
+			name:        "when database return error, return error",
+			wantEstates: nil,
+			wantErr:     true,
+			mock: func() {
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, length, width FROM estate WHERE deleted_at IS NULL ORDER BY id")).WillReturnError(errors.New("estate"))
+			},
+		},
+		{
+			name:        "when row is corrupted, return error",
+			wantEstates: nil,
+			wantErr:     true,
+			mock: func() {
+				rows := sqlmock.NewRows([]string{"id", "length", "width"}).AddRow("aaa", 2, 3).AddRow("bbb", 4, 5).RowError(1, errors.New("row"))
+				mock.ExpectQuery(regexp.QuoteMeta("SELECT id, length, width FROM estate WHERE deleted_at IS NULL ORDER BY id")).WillReturnRows(rows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mock()
+			r := &Repository{
+				Db: db,
+			}
+
+			gotEstates, err := r.ListEstates(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Repository.ListEstates() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotEstates, tt.wantEstates) {
+				t.Errorf("Repository.ListEstates() = %v, want %v", gotEstates, tt.wantEstates)
+			}
+		})
+	}
+}
+
+func TestRepository_Divisions(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	division := m.Division{EstateID: "aaa", Name: "north", Supervisor: "budi", Extent: m.Extent{XMin: 1, YMin: 2, XMax: 3, YMax: 4}}
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO division (estate_id, name, supervisor, x_min, y_min, x_max, y_max) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id")).
+		WithArgs("aaa", "north", "budi", 1, 2, 3, 4).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ddd"))
+	if id, err := r.CreateDivision(context.Background(), division); err != nil || id != "ddd" {
+		t.Errorf("Repository.CreateDivision() = %v, %v, want ddd", id, err)
+	}
+
+	query := regexp.QuoteMeta("SELECT id, estate_id, name, supervisor, x_min, y_min, x_max, y_max FROM division WHERE estate_id = $1 ORDER BY name")
+	rows := sqlmock.NewRows([]string{"id", "estate_id", "name", "supervisor", "x_min", "y_min", "x_max", "y_max"}).AddRow("ddd", "aaa", "north", "budi", 1, 2, 3, 4)
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnRows(rows)
+	division.ID = "ddd"
+	if got, err := r.GetDivisions(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, []m.Division{division}) {
+		t.Errorf("Repository.GetDivisions() = %v, %v, want %v", got, err, []m.Division{division})
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnError(errors.New("division"))
+	if _, err := r.GetDivisions(context.Background(), "aaa"); err == nil {
+		t.Errorf("Repository.GetDivisions() expected error")
+	}
+}
+
+func TestRepository_Blocks(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	block := m.Block{EstateID: "aaa", DivisionID: "ddd", Name: "A1", Supervisor: "siti", Extent: m.Extent{XMin: 1, YMin: 2, XMax: 3, YMax: 4}}
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO block (estate_id, division_id, name, supervisor, x_min, y_min, x_max, y_max) VALUES($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id")).
+		WithArgs("aaa", "ddd", "A1", "siti", 1, 2, 3, 4).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("bbb"))
+	if id, err := r.CreateBlock(context.Background(), block); err != nil || id != "bbb" {
+		t.Errorf("Repository.CreateBlock() = %v, %v, want bbb", id, err)
+	}
+
+	query := regexp.QuoteMeta("SELECT id, estate_id, division_id, name, supervisor, x_min, y_min, x_max, y_max FROM block WHERE estate_id = $1 ORDER BY name")
+	rows := sqlmock.NewRows([]string{"id", "estate_id", "division_id", "name", "supervisor", "x_min", "y_min", "x_max", "y_max"}).AddRow("bbb", "aaa", "ddd", "A1", "siti", 1, 2, 3, 4)
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnRows(rows)
+	block.ID = "bbb"
+	if got, err := r.GetBlocks(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, []m.Block{block}) {
+		t.Errorf("Repository.GetBlocks() = %v, %v, want %v", got, err, []m.Block{block})
+	}
+
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE tree SET block_id = $1 WHERE estate_id = $2 AND x BETWEEN $3 AND $4 AND y BETWEEN $5 AND $6")).
+		WithArgs("bbb", "aaa", 1, 3, 2, 4).WillReturnResult(sqlmock.NewResult(0, 2))
+	if err := r.AssignTreesToBlock(context.Background(), block); err != nil {
+		t.Errorf("Repository.AssignTreesToBlock() error = %v", err)
+	}
+}
+
+func TestRepository_UpdateEstate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta(`UPDATE estate SET name = $2, code = $3, owner = $4, region = $5, planting_year = $6, tags = $7,
+		metadata = $8, length = $9, width = $10 WHERE id = $1`)
+	mock.ExpectExec(query).WithArgs("aaa", "north", "NTH", "PT Sawit", "Riau", 2015, "{\"young\",\"flat\"}", `{"owner":"budi"}`, 3, 4).WillReturnResult(sqlmock.NewResult(0, 1))
+	estate := m.Estate{
+		ID: "aaa", Name: "north", Code: "NTH", Owner: "PT Sawit", Region: "Riau", PlantingYear: 2015, Tags: []string{"young", "flat"},
+		Length: 3, Width: 4, Metadata: map[string]string{"owner": "budi"},
+	}
+	if err := r.UpdateEstate(context.Background(), estate); err != nil {
+		t.Errorf("Repository.UpdateEstate() error = %v", err)
+	}
+
+	mock.ExpectExec(query).WithArgs("aaa", "", nil, "", "", nil, "{}", nil, 3, 4).WillReturnError(errors.New("update"))
+	if err := r.UpdateEstate(context.Background(), m.Estate{ID: "aaa", Length: 3, Width: 4}); err == nil {
+		t.Errorf("Repository.UpdateEstate() expected error")
+	}
+}
+
+func TestRepository_DeleteEstate(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta(`WITH deleted AS (UPDATE estate SET deleted_at = now() WHERE id = $1 AND deleted_at IS NULL RETURNING id)
+		INSERT INTO audit_log (estate_id, entity, action) SELECT id, 'estate', 'delete' FROM deleted`)
+	mock.ExpectExec(query).WithArgs("aaa").WillReturnResult(sqlmock.NewResult(0, 1))
+	if deleted, err := r.DeleteEstate(context.Background(), "aaa"); err != nil || !deleted {
+		t.Errorf("Repository.DeleteEstate() = %v, %v, want true", deleted, err)
+	}
+
+	mock.ExpectExec(query).WithArgs("bbb").WillReturnResult(sqlmock.NewResult(0, 0))
+	if deleted, err := r.DeleteEstate(context.Background(), "bbb"); err != nil || deleted {
+		t.Errorf("Repository.DeleteEstate() = %v, %v, want false", deleted, err)
+	}
+
+	mock.ExpectExec(query).WithArgs("ccc").WillReturnError(errors.New("delete"))
+	if _, err := r.DeleteEstate(context.Background(), "ccc"); err == nil {
+		t.Errorf("Repository.DeleteEstate() expected error")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_RestoreTree(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	mock.ExpectExec(regexp.QuoteMeta(`WITH restored AS (UPDATE tree SET deleted_at = NULL WHERE estate_id = $1 AND x = $2 AND y = $3
+			AND deleted_at = (SELECT max(deleted_at) FROM tree WHERE estate_id = $1 AND x = $2 AND y = $3) RETURNING estate_id)
+		INSERT INTO audit_log (estate_id, entity, action, x, y) SELECT DISTINCT estate_id, 'tree', 'restore', $2::INT, $3::INT FROM restored`)).
+		WithArgs("aaa", 2, 3).WillReturnResult(sqlmock.NewResult(0, 1))
+	if restored, err := r.RestoreTree(context.Background(), "aaa", 2, 3); err != nil || !restored {
+		t.Errorf("Repository.RestoreTree() = %v, %v, want true", restored, err)
+	}
+}
+
+func TestRepository_WithDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	deletedAt := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).AddRow(1, 1, 1, nil, "", nil, m.TreeDead, deletedAt)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1 ORDER BY y, x")).WithArgs("aaa").WillReturnRows(rows)
+
+	want := []m.Tree{{X: 1, Y: 1, Height: 1, Status: m.TreeDead, DeletedAt: &deletedAt}}
+	if got, err := r.GetTree(WithDeleted(context.Background()), "aaa"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.GetTree() = %v, %v, want %v", got, err, want)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_PurgeDeleted(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	before := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectExec(regexp.QuoteMeta(`WITH purged AS (DELETE FROM tree WHERE deleted_at < $1 RETURNING estate_id, x, y)
+		INSERT INTO audit_log (estate_id, entity, action, x, y) SELECT estate_id, 'tree', 'purge', x, y FROM purged`)).
+		WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 5))
+	mock.ExpectExec(regexp.QuoteMeta(`WITH purged AS (DELETE FROM estate WHERE deleted_at < $1 RETURNING id)
+		INSERT INTO audit_log (estate_id, entity, action) SELECT id, 'estate', 'purge' FROM purged`)).
+		WithArgs(before).WillReturnResult(sqlmock.NewResult(0, 2))
+
+	want := m.PurgeResult{Estates: 2, Trees: 5}
+	if got, err := r.PurgeDeleted(context.Background(), before); err != nil || got != want {
+		t.Errorf("Repository.PurgeDeleted() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestRepository_GetAuditLog(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"estate_id", "entity", "action", "x", "y", "created_at"}).
+		AddRow("aaa", "tree", "delete", 2, 3, at).
+		AddRow("aaa", "estate", "delete", nil, nil, at)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT estate_id, entity, action, x, y, created_at FROM audit_log WHERE estate_id = $1 ORDER BY id")).WithArgs("aaa").WillReturnRows(rows)
+
+	want := []m.AuditEntry{
+		{EstateID: "aaa", Entity: "tree", Action: "delete", X: 2, Y: 3, At: at},
+		{EstateID: "aaa", Entity: "estate", Action: "delete", At: at},
+	}
+	if got, err := r.GetAuditLog(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.GetAuditLog() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestRepository_SearchEstates(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta(`SELECT id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata, deleted_at FROM estate
+		WHERE ($1 = '' OR name ILIKE '%' || $1 || '%' OR code ILIKE '%' || $1 || '%')
+		AND ($2 = '' OR $2 = ANY(tags)) AND deleted_at IS NULL ORDER BY name, id`)
+	columns := []string{"id", "name", "code", "owner", "region", "planting_year", "tags", "length", "width", "origin_lat", "origin_lon", "bearing", "plot_size", "boundary", "metadata", "deleted_at"}
+	rows := sqlmock.NewRows(columns).
+		AddRow("aaa", "Sungai Lilin", "SL-01", "PT Sawit", "Riau", 2015, "{young,flat}", 2, 2, nil, nil, nil, nil, nil, nil, nil)
+	mock.ExpectQuery(query).WithArgs("lilin", "young").WillReturnRows(rows)
+
+	want := []m.Estate{{
+		ID: "aaa", Name: "Sungai Lilin", Code: "SL-01", Owner: "PT Sawit", Region: "Riau", PlantingYear: 2015,
+		Tags: []string{"young", "flat"}, Length: 2, Width: 2,
+	}}
+	got, err := r.SearchEstates(context.Background(), m.EstateSearch{Query: "lilin", Tag: "young"})
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.SearchEstates() = %v, %v, want %v", got, err, want)
+	}
+
+	mock.ExpectQuery(query).WithArgs("", "").WillReturnError(errors.New("search"))
+	if _, err := r.SearchEstates(context.Background(), m.EstateSearch{}); err == nil {
+		t.Errorf("Repository.SearchEstates() expected error")
+	}
+}
+
+func TestRepository_CreateObservation(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO observation (estate_id, x, y, type, severity, observed_at, notes) VALUES($1, $2, $3, $4, $5, $6, $7) RETURNING id")).
+		WithArgs("aaa", 2, 3, "ganoderma", 2, at, "fruiting body at base").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ooo"))
+
+	observation := m.Observation{EstateID: "aaa", X: 2, Y: 3, Type: "ganoderma", Severity: 2, ObservedAt: at, Notes: "fruiting body at base"}
+	if id, err := r.CreateObservation(context.Background(), observation); err != nil || id != "ooo" {
+		t.Errorf("Repository.CreateObservation() = %v, %v, want ooo", id, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_GetObservations(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta(`SELECT id, estate_id, x, y, type, severity, observed_at, notes FROM observation
+		WHERE estate_id = $1 AND ($2 = '' OR type = $2) AND ($3 = 0 OR x = $3) AND ($4 = 0 OR y = $4)
+		AND ($5::timestamptz IS NULL OR observed_at >= $5) ORDER BY observed_at, id`)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "type", "severity", "observed_at", "notes"}).
+		AddRow("ooo", "aaa", 2, 3, "ganoderma", 2, at, "")
+	mock.ExpectQuery(query).WithArgs("aaa", "ganoderma", 0, 0, at).WillReturnRows(rows)
+
+	want := []m.Observation{{ID: "ooo", EstateID: "aaa", X: 2, Y: 3, Type: "ganoderma", Severity: 2, ObservedAt: at}}
+	got, err := r.GetObservations(context.Background(), "aaa", m.ObservationFilter{Type: "ganoderma", Since: &at})
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.GetObservations() = %v, %v, want %v", got, err, want)
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa", "", 0, 0, nil).WillReturnError(errors.New("observation"))
+	if _, err := r.GetObservations(context.Background(), "aaa", m.ObservationFilter{}); err == nil {
+		t.Errorf("Repository.GetObservations() expected error")
+	}
+}
+
+func TestRepository_CreateHarvest(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO harvest (estate_id, x, y, harvested_at, bunches, weight) VALUES($1, $2, $3, $4, $5, $6) RETURNING id")).
+		WithArgs("aaa", 2, 3, day, 2, 41.5).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("hhh"))
+
+	harvest := m.Harvest{EstateID: "aaa", X: 2, Y: 3, HarvestedAt: day, Bunches: 2, Weight: 41.5}
+	if id, err := r.CreateHarvest(context.Background(), harvest); err != nil || id != "hhh" {
+		t.Errorf("Repository.CreateHarvest() = %v, %v, want hhh", id, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_GetHarvests(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta(`SELECT id, estate_id, x, y, harvested_at, bunches, weight FROM harvest
+		WHERE estate_id = $1 AND ($2::date IS NULL OR harvested_at >= $2) AND ($3::date IS NULL OR harvested_at <= $3)
+		ORDER BY harvested_at, y, x`)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "estate_id", "x", "y", "harvested_at", "bunches", "weight"}).
+		AddRow("hhh", "aaa", 2, 3, day, 2, 41.5)
+	mock.ExpectQuery(query).WithArgs("aaa", day, nil).WillReturnRows(rows)
+
+	want := []m.Harvest{{ID: "hhh", EstateID: "aaa", X: 2, Y: 3, HarvestedAt: day, Bunches: 2, Weight: 41.5}}
+	if got, err := r.GetHarvests(context.Background(), "aaa", &day, nil); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.GetHarvests() = %v, %v, want %v", got, err, want)
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa", nil, nil).WillReturnError(errors.New("harvest"))
+	if _, err := r.GetHarvests(context.Background(), "aaa", nil, nil); err == nil {
+		t.Errorf("Repository.GetHarvests() expected error")
+	}
+}
+
+func TestRepository_CreateHarvestRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO harvest_round (estate_id, block_id, teams, scheduled_on) VALUES($1, $2, $3, $4) RETURNING id")).
+		WithArgs("aaa", nil, 3, day).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("rrr"))
+
+	if id, err := r.CreateHarvestRound(context.Background(), m.HarvestRound{EstateID: "aaa", Teams: 3, ScheduledOn: day}); err != nil || id != "rrr" {
+		t.Errorf("Repository.CreateHarvestRound() = %v, %v, want rrr", id, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_GetHarvestRounds(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta(`SELECT id, estate_id, block_id, teams, scheduled_on, completed_at FROM harvest_round
+		WHERE estate_id = $1 ORDER BY scheduled_on, id`)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	completedAt := time.Date(2024, 1, 3, 15, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "estate_id", "block_id", "teams", "scheduled_on", "completed_at"}).
+		AddRow("r1", "aaa", nil, 3, day, completedAt).
+		AddRow("r2", "aaa", "bbb", 2, day.AddDate(0, 0, 8), nil)
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnRows(rows)
+
+	want := []m.HarvestRound{
+		{ID: "r1", EstateID: "aaa", Teams: 3, ScheduledOn: day, CompletedAt: &completedAt},
+		{ID: "r2", EstateID: "aaa", BlockID: "bbb", Teams: 2, ScheduledOn: day.AddDate(0, 0, 8)},
+	}
+	if got, err := r.GetHarvestRounds(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.GetHarvestRounds() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestRepository_CompleteHarvestRound(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta("UPDATE harvest_round SET completed_at = now() WHERE id = $1 AND estate_id = $2 AND completed_at IS NULL")
+	mock.ExpectExec(query).WithArgs("rrr", "aaa").WillReturnResult(sqlmock.NewResult(0, 1))
+	if completed, err := r.CompleteHarvestRound(context.Background(), "aaa", "rrr"); err != nil || !completed {
+		t.Errorf("Repository.CompleteHarvestRound() = %v, %v, want true", completed, err)
+	}
+
+	mock.ExpectExec(query).WithArgs("rrr", "aaa").WillReturnResult(sqlmock.NewResult(0, 0))
+	if completed, err := r.CompleteHarvestRound(context.Background(), "aaa", "rrr"); err != nil || completed {
+		t.Errorf("Repository.CompleteHarvestRound() = %v, %v, want false", completed, err)
+	}
+}
+
+func TestRepository_CreateFertilizerProgram(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO fertilizer_program (estate_id, block_id, product, dose, interval_days, start_on) VALUES($1, $2, $3, $4, $5, $6) RETURNING id")).
+		WithArgs("aaa", "bbb", "NPK 15-15-15", 1.5, 90, day).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("ppp"))
+
+	program := m.FertilizerProgram{EstateID: "aaa", BlockID: "bbb", Product: "NPK 15-15-15", Dose: 1.5, Interval: 90, StartOn: day}
+	if id, err := r.CreateFertilizerProgram(context.Background(), program); err != nil || id != "ppp" {
+		t.Errorf("Repository.CreateFertilizerProgram() = %v, %v, want ppp", id, err)
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestRepository_GetFertilizerPrograms(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	rows := sqlmock.NewRows([]string{"id", "estate_id", "block_id", "product", "dose", "interval_days", "start_on"}).
+		AddRow("ppp", "aaa", nil, "Urea", 0.5, 60, day)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT id, estate_id, block_id, product, dose, interval_days, start_on FROM fertilizer_program WHERE estate_id = $1 ORDER BY product, id")).
+		WithArgs("aaa").WillReturnRows(rows)
+
+	want := []m.FertilizerProgram{{ID: "ppp", EstateID: "aaa", Product: "Urea", Dose: 0.5, Interval: 60, StartOn: day}}
+	if got, err := r.GetFertilizerPrograms(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.GetFertilizerPrograms() = %v, %v, want %v", got, err, want)
+	}
+}
+
+func TestRepository_CreateFertilizerApplication(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO fertilizer_application (program_id, applied_on, trees, quantity) VALUES($1, $2, $3, $4) RETURNING id")).
+		WithArgs("ppp", day, 10, 15.0).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("fff"))
+
+	application := m.FertilizerApplication{ProgramID: "ppp", AppliedOn: day, Trees: 10, Quantity: 15}
+	if id, err := r.CreateFertilizerApplication(context.Background(), application); err != nil || id != "fff" {
+		t.Errorf("Repository.CreateFertilizerApplication() = %v, %v, want fff", id, err)
+	}
+}
+
+func TestRepository_GetFertilizerApplications(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	query := regexp.QuoteMeta(`SELECT a.id, a.program_id, a.applied_on, a.trees, a.quantity FROM fertilizer_application a
+		JOIN fertilizer_program p ON p.id = a.program_id WHERE p.estate_id = $1 ORDER BY a.applied_on, a.id`)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnRows(sqlmock.NewRows([]string{"id", "program_id", "applied_on", "trees", "quantity"}).AddRow("fff", "ppp", day, 10, 15.0))
+
+	want := []m.FertilizerApplication{{ID: "fff", ProgramID: "ppp", AppliedOn: day, Trees: 10, Quantity: 15}}
+	if got, err := r.GetFertilizerApplications(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Repository.GetFertilizerApplications() = %v, %v, want %v", got, err, want)
+	}
+
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnError(errors.New("application"))
+	if _, err := r.GetFertilizerApplications(context.Background(), "aaa"); err == nil {
+		t.Errorf("Repository.GetFertilizerApplications() expected error")
+	}
+}
+
+func TestRepository_WithTx(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db}
+
+	// the estate read in the transaction is locked, the write joins it
+	query := regexp.QuoteMeta("SELECT " + estateColumns + " FROM estate WHERE id = $1 AND deleted_at IS NULL FOR UPDATE")
+	rows := sqlmock.NewRows([]string{"id", "name", "code", "owner", "region", "planting_year", "tags", "length", "width", "origin_lat", "origin_lon", "bearing", "plot_size", "boundary", "metadata", "deleted_at"}).AddRow("aaa", "", nil, "", "", nil, "{}", 2, 2, nil, nil, nil, nil, nil, nil, nil)
+	mock.ExpectBegin()
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnRows(rows)
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE estate SET")).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	err = r.WithTx(context.Background(), func(tx RepositoryInterface) error {
+		estate, err := tx.GetEstateByID(context.Background(), "aaa")
+		if err != nil {
+			return err
+		}
+		// nested calls join the transaction
+		return tx.WithTx(context.Background(), func(tx RepositoryInterface) error {
+			return tx.UpdateEstate(context.Background(), estate)
+		})
+	})
+	if err != nil {
+		t.Errorf("Repository.WithTx() error = %v", err)
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+	if err := r.WithTx(context.Background(), func(tx RepositoryInterface) error { return errors.New("usecase") }); err == nil {
+		t.Errorf("Repository.WithTx() expected error")
+	}
+
+	mock.ExpectBegin().WillReturnError(errors.New("begin"))
+	if err := r.WithTx(context.Background(), func(tx RepositoryInterface) error { return nil }); err == nil {
+		t.Errorf("Repository.WithTx() expected error when the transaction does not start")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Repository.WithTx() %v", err)
+	}
+}
+
+func TestRepository_QueryTimeout(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	r := &Repository{Db: db, QueryTimeout: 10 * time.Millisecond}
+
+	query := regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1")
+	mock.ExpectQuery(query).WithArgs("aaa").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"x"}))
+	var canceled *CanceledError
+	if _, err := r.GetTree(context.Background(), "aaa"); !errors.As(err, &canceled) || !canceled.Timeout() {
+		t.Errorf("Repository.GetTree() error = %v, want a timeout", err)
+	}
+
+	// a request gone away stops the call
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := r.CreateTree(ctx, "aaa", m.Tree{X: 1, Y: 1, Height: 1}); !errors.As(err, &canceled) || canceled.Timeout() {
+		t.Errorf("Repository.CreateTree() error = %v, want canceled", err)
+	}
+	if err := r.WithTx(ctx, func(tx RepositoryInterface) error { return nil }); !errors.As(err, &canceled) {
+		t.Errorf("Repository.WithTx() error = %v, want canceled", err)
+	}
+
+	// a stream is only bounded until it opens, however slow its callback
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnRows(sqlmock.NewRows([]string{"x", "y", "height", "block_id", "variety", "planted_at", "status", "deleted_at"}).
+		AddRow(1, 1, 1, nil, "", nil, m.TreeHealthy, nil).AddRow(2, 1, 2, nil, "", nil, m.TreeHealthy, nil))
+	count := 0
+	err = r.EachTree(context.Background(), "aaa", func(tree m.Tree) error {
+		time.Sleep(20 * time.Millisecond)
+		count++
+		return nil
+	})
+	if err != nil || count != 2 {
+		t.Errorf("Repository.EachTree() read %d trees, error = %v, want 2", count, err)
+	}
+	mock.ExpectQuery(query).WithArgs("aaa").WillDelayFor(time.Second).WillReturnRows(sqlmock.NewRows([]string{"x"}))
+	if err := r.EachTree(context.Background(), "aaa", func(m.Tree) error { return nil }); !errors.As(err, &canceled) || !canceled.Timeout() {
+		t.Errorf("Repository.EachTree() error = %v, want a timeout", err)
+	}
+
+	// other errors are left alone
+	mock.ExpectQuery(query).WithArgs("aaa").WillReturnError(errors.New("tree"))
+	if _, err := r.GetTree(context.Background(), "aaa"); err == nil || errors.As(err, &canceled) {
+		t.Errorf("Repository.GetTree() error = %v, want tree", err)
+	}
+}
+
+func TestRepository_Replicas(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	replica, replicaMock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer replica.Close()
+	r := &Repository{Db: db, Replicas: []*sql.DB{replica}, written: newRecentWrites(time.Hour)}
+	ctx := context.Background()
+	trees := regexp.QuoteMeta("SELECT x,y,height,block_id,variety,planted_at,status,deleted_at FROM tree WHERE estate_id = $1")
+	estates := regexp.QuoteMeta("SELECT id, length, width FROM estate")
+
+	// reads go to the replica until their estate is written
+	replicaMock.ExpectQuery(trees).WithArgs("aaa").WillReturnRows(sqlmock.NewRows([]string{"x"}))
+	replicaMock.ExpectQuery(estates).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	r.GetTree(ctx, "aaa")
+	r.ListEstates(ctx)
+
+	// the writer reads its writes on the primary, other estates stay on the replica
+	mock.ExpectQuery(regexp.QuoteMeta("INSERT INTO tree")).WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("t1"))
+	mock.ExpectQuery(trees).WithArgs("aaa").WillReturnRows(sqlmock.NewRows([]string{"x"}))
+	mock.ExpectQuery(estates).WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	replicaMock.ExpectQuery(trees).WithArgs("bbb").WillReturnRows(sqlmock.NewRows([]string{"x"}))
+	r.CreateTree(ctx, "aaa", m.Tree{X: 1, Y: 1, Height: 1})
+	r.GetTree(ctx, "aaa")
+	r.ListEstates(ctx)
+	r.GetTree(ctx, "bbb")
+
+	// a transaction reads from the primary
+	mock.ExpectBegin()
+	mock.ExpectQuery(trees).WithArgs("ccc").WillReturnRows(sqlmock.NewRows([]string{"x"}))
+	mock.ExpectCommit()
+	r.WithTx(ctx, func(tx RepositoryInterface) error {
+		_, err := tx.GetTree(ctx, "ccc")
+		return err
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Repository primary %v", err)
+	}
+	if err := replicaMock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Repository replica %v", err)
+	}
+}
+
+func TestRecentWrites(t *testing.T) {
+	if newRecentWrites(0).recent(nil) {
+		t.Errorf("recentWrites.recent() without a window = true")
+	}
+
+	w := newRecentWrites(20 * time.Millisecond)
+	w.wrote("aaa")
+	if !w.recent([]string{"bbb", "aaa"}) || w.recent([]string{"bbb"}) || !w.recent(nil) {
+		t.Errorf("recentWrites.recent() after a write to aaa is wrong")
+	}
+	// a write to unknown estates keeps every estate on the primary
+	w.wrote("")
+	if !w.recent([]string{"bbb"}) {
+		t.Errorf("recentWrites.recent() after a write to unknown estates = false")
+	}
+
+	time.Sleep(30 * time.Millisecond)
+	w.wrote("bbb")
+	if w.recent([]string{"aaa"}) || len(w.estates) != 1 {
+		t.Errorf("recentWrites kept aaa past the window: %v", w.estates)
+	}
+}
@@ -6,6 +6,8 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"time"
 
 	m "github.com/SawitProRecruitment/UserService/types"
 )
@@ -13,7 +15,63 @@ import (
 //go:generate mockgen --build_flags=--mod=mod -destination=mock/interfaces.mock.gen.go -package=repository . RepositoryInterface
 type RepositoryInterface interface {
 	GetEstateByID(ctx context.Context, id string) (estate m.Estate, err error)
-	CreateEstate(ctx context.Context, length int, width int) (id string, err error)
+	CreateEstate(ctx context.Context, estate m.Estate) (id string, err error)
+	UpdateEstate(ctx context.Context, estate m.Estate) (err error)
+	DeleteEstate(ctx context.Context, id string) (deleted bool, err error)
+	RestoreEstate(ctx context.Context, id string) (restored bool, err error)
 	CreateTree(ctx context.Context, estateID string, tree m.Tree) (id string, err error)
 	GetTree(ctx context.Context, estateID string) (tree []m.Tree, err error)
+	// EachTree calls fn with the trees of the estate one at a time, ordered
+	// by row then column, without loading them all. It stops at the first
+	// error of fn or of the database and returns it. fn must not use the
+	// repository, whose connection may be busy with the trees. The query
+	// timeout bounds reading the trees, not the time fn takes over them.
+	EachTree(ctx context.Context, estateID string, fn func(tree m.Tree) error) (err error)
+	GetTreeAt(ctx context.Context, estateID string, x int, y int) (tree m.Tree, found bool, err error)
+	DeleteTree(ctx context.Context, estateID string, x int, y int) (deleted bool, err error)
+	RestoreTree(ctx context.Context, estateID string, x int, y int) (restored bool, err error)
+
+	ListEstates(ctx context.Context) (estates []m.Estate, err error)
+	SearchEstates(ctx context.Context, search m.EstateSearch) (estates []m.Estate, err error)
+	// EachTreeOfEstates streams the trees of several estates like EachTree,
+	// in a single query, ordered by estate ID then row then column.
+	EachTreeOfEstates(ctx context.Context, estateIDs []string, fn func(estateID string, tree m.Tree) error) (err error)
+
+	CreateDivision(ctx context.Context, division m.Division) (id string, err error)
+	GetDivisions(ctx context.Context, estateID string) (divisions []m.Division, err error)
+	CreateBlock(ctx context.Context, block m.Block) (id string, err error)
+	GetBlocks(ctx context.Context, estateID string) (blocks []m.Block, err error)
+	AssignTreesToBlock(ctx context.Context, block m.Block) (err error)
+
+	PurgeDeleted(ctx context.Context, before time.Time) (purged m.PurgeResult, err error)
+	GetAuditLog(ctx context.Context, estateID string) (entries []m.AuditEntry, err error)
+	CreateObservation(ctx context.Context, observation m.Observation) (id string, err error)
+	GetObservations(ctx context.Context, estateID string, filter m.ObservationFilter) (observations []m.Observation, err error)
+	CreateHarvest(ctx context.Context, harvest m.Harvest) (id string, err error)
+	GetHarvests(ctx context.Context, estateID string, from *time.Time, to *time.Time) (harvests []m.Harvest, err error)
+	GetHarvestAt(ctx context.Context, estateID string, x int, y int, day time.Time) (harvest m.Harvest, found bool, err error)
+	CreateHarvestRound(ctx context.Context, round m.HarvestRound) (id string, err error)
+	GetHarvestRounds(ctx context.Context, estateID string) (rounds []m.HarvestRound, err error)
+	CompleteHarvestRound(ctx context.Context, estateID string, roundID string) (completed bool, err error)
+	CreateFertilizerProgram(ctx context.Context, program m.FertilizerProgram) (id string, err error)
+	GetFertilizerPrograms(ctx context.Context, estateID string) (programs []m.FertilizerProgram, err error)
+	CreateFertilizerApplication(ctx context.Context, application m.FertilizerApplication) (id string, err error)
+	GetFertilizerApplications(ctx context.Context, estateID string) (applications []m.FertilizerApplication, err error)
+
+	// WithTx runs fn with a repository whose calls are applied together when
+	// fn returns no error, and not at all otherwise.
+	WithTx(ctx context.Context, fn func(repo RepositoryInterface) error) error
+}
+
+// Migrator is implemented by the repositories whose schema is migrated.
+type Migrator interface {
+	Migrate(ctx context.Context) (applied int, err error)
+	MigrateDown(ctx context.Context, steps int) (reverted int, err error)
+}
+
+// Pool is implemented by the repositories backed by a pool of database
+// connections.
+type Pool interface {
+	Ping(ctx context.Context) error
+	Stats() sql.DBStats
 }
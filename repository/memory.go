@@ -0,0 +1,827 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+	"github.com/google/uuid"
+)
+
+// MemoryRepository keeps everything in memory, for running the server
+// without a database. It behaves like the Postgres repository, keys and
+// unique constraints included, but loses its data when the process exits.
+// It is safe for concurrent use.
+type MemoryRepository struct {
+	mu sync.RWMutex
+	memoryData
+	// undo reverts the changes made in the transaction of WithTx, last
+	// first. It is nil outside of a transaction.
+	undo []func(d *memoryData)
+}
+
+type memoryData struct {
+	estates      map[string]*m.Estate
+	trees        []*memoryTree
+	divisions    []m.Division
+	blocks       []m.Block
+	auditLog     []m.AuditEntry
+	observations []m.Observation
+	harvests     []m.Harvest
+	rounds       []*m.HarvestRound
+	programs     []m.FertilizerProgram
+	applications []m.FertilizerApplication
+}
+
+type memoryTree struct {
+	id       string
+	estateID string
+	tree     m.Tree
+}
+
+var (
+	errUnknownEstate  = errors.New("memory: estate is not exist")
+	errUnknownProgram = errors.New("memory: fertilizer program is not exist")
+	errDuplicateCode  = errors.New("memory: estate code is already used")
+	errDuplicateTree  = errors.New("memory: plot already has a tree")
+	errDuplicateCrop  = errors.New("memory: tree is already harvested on this day")
+)
+
+func NewMemoryRepository() *MemoryRepository {
+	return &MemoryRepository{memoryData: memoryData{estates: map[string]*m.Estate{}}}
+}
+
+// changed records how to revert a change made in a transaction.
+func (r *MemoryRepository) changed(undo func(d *memoryData)) {
+	if r.undo != nil {
+		r.undo = append(r.undo, undo)
+	}
+}
+
+// appended records that the change only appends to the collections, which
+// is reverted by cutting them back to their current length.
+func (r *MemoryRepository) appended() {
+	n := r.lengths()
+	r.changed(func(d *memoryData) { d.cut(n) })
+}
+
+// memoryLengths is the length of every appendable collection of the data.
+type memoryLengths struct {
+	trees, divisions, blocks, auditLog, observations, harvests, rounds, programs, applications int
+}
+
+func (d *memoryData) lengths() memoryLengths {
+	return memoryLengths{len(d.trees), len(d.divisions), len(d.blocks), len(d.auditLog), len(d.observations), len(d.harvests), len(d.rounds), len(d.programs), len(d.applications)}
+}
+
+func (d *memoryData) cut(n memoryLengths) {
+	d.trees, d.divisions, d.blocks, d.auditLog = d.trees[:n.trees], d.divisions[:n.divisions], d.blocks[:n.blocks], d.auditLog[:n.auditLog]
+	d.observations, d.harvests, d.rounds = d.observations[:n.observations], d.harvests[:n.harvests], d.rounds[:n.rounds]
+	d.programs, d.applications = d.programs[:n.programs], d.applications[:n.applications]
+}
+
+// touchEstate records the estate before it is changed in a transaction.
+func (r *MemoryRepository) touchEstate(stored *m.Estate) {
+	if r.undo != nil {
+		old := copyEstate(stored)
+		r.changed(func(*memoryData) { *stored = old })
+	}
+}
+
+// touchTree records the tree before it is changed in a transaction.
+func (r *MemoryRepository) touchTree(stored *memoryTree) {
+	if r.undo != nil {
+		old := copyTree(stored.tree)
+		r.changed(func(*memoryData) { stored.tree = old })
+	}
+}
+
+// WithTx runs fn with a repository on the same data, whose changes are
+// reverted from an undo log when fn returns an error. Other calls wait for fn
+// to return.
+func (r *MemoryRepository) WithTx(ctx context.Context, fn func(repo RepositoryInterface) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	tx := &MemoryRepository{memoryData: r.memoryData, undo: []func(d *memoryData){}}
+	if err := fn(tx); err != nil {
+		for i := len(tx.undo) - 1; i >= 0; i-- {
+			tx.undo[i](&tx.memoryData)
+		}
+		r.memoryData = tx.memoryData
+		return err
+	}
+	r.memoryData = tx.memoryData
+	// a transaction within a transaction is reverted with it
+	if r.undo != nil {
+		r.undo = append(r.undo, tx.undo...)
+	}
+	return nil
+}
+
+func includeDeleted(ctx context.Context) bool {
+	include, _ := ctx.Value(includeDeletedKey{}).(bool)
+	return include
+}
+
+// copyTime returns a copy of the time so callers can not change the stored
+// one.
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	copied := *t
+	return &copied
+}
+
+func copyEstate(estate *m.Estate) m.Estate {
+	copied := *estate
+	copied.Tags = slices.Clone(estate.Tags)
+	copied.Metadata = maps.Clone(estate.Metadata)
+	copied.Boundary = slices.Clone(estate.Boundary)
+	copied.DeletedAt = copyTime(estate.DeletedAt)
+	if estate.Geo != nil {
+		geo := *estate.Geo
+		copied.Geo = &geo
+	}
+	return copied
+}
+
+func copyTree(tree m.Tree) m.Tree {
+	tree.PlantedAt = copyTime(tree.PlantedAt)
+	tree.DeletedAt = copyTime(tree.DeletedAt)
+	return tree
+}
+
+// codeTaken tells if a live estate other than id uses the code.
+func (r *MemoryRepository) codeTaken(code string, id string) bool {
+	if code == "" {
+		return false
+	}
+	for _, estate := range r.estates {
+		if estate.ID != id && estate.Code == code && estate.DeletedAt == nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *MemoryRepository) audit(estateID string, entity string, action string, x int, y int) {
+	r.auditLog = append(r.auditLog, m.AuditEntry{EstateID: estateID, Entity: entity, Action: action, X: x, Y: y, At: time.Now()})
+}
+
+func (r *MemoryRepository) GetEstateByID(ctx context.Context, id string) (estate m.Estate, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	stored := r.estates[id]
+	if stored == nil || stored.DeletedAt != nil && !includeDeleted(ctx) {
+		return m.Estate{}, sql.ErrNoRows
+	}
+	return copyEstate(stored), nil
+}
+
+func (r *MemoryRepository) CreateEstate(ctx context.Context, estate m.Estate) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.codeTaken(estate.Code, "") {
+		return "", errDuplicateCode
+	}
+	estate.ID = uuid.NewString()
+	estate.Divisions = nil
+	estate.DeletedAt = nil
+	if len(estate.Tags) == 0 {
+		estate.Tags = nil
+	}
+	stored := copyEstate(&estate)
+	r.estates[estate.ID] = &stored
+	r.changed(func(d *memoryData) { delete(d.estates, estate.ID) })
+	return estate.ID, nil
+}
+
+// UpdateEstate stores the descriptive fields, metadata and size of the
+// estate.
+func (r *MemoryRepository) UpdateEstate(ctx context.Context, estate m.Estate) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.estates[estate.ID]
+	if stored == nil {
+		return nil
+	}
+	if r.codeTaken(estate.Code, estate.ID) && stored.DeletedAt == nil {
+		return errDuplicateCode
+	}
+	r.touchEstate(stored)
+	stored.Name, stored.Code, stored.Owner, stored.Region = estate.Name, estate.Code, estate.Owner, estate.Region
+	stored.PlantingYear = estate.PlantingYear
+	stored.Tags = slices.Clone(estate.Tags)
+	if len(stored.Tags) == 0 {
+		stored.Tags = nil
+	}
+	stored.Metadata = maps.Clone(estate.Metadata)
+	stored.Length, stored.Width = estate.Length, estate.Width
+	return nil
+}
+
+// DeleteEstate soft-deletes the estate and records it in the audit log. It
+// reports whether the estate was found.
+func (r *MemoryRepository) DeleteEstate(ctx context.Context, id string) (deleted bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.estates[id]
+	if stored == nil || stored.DeletedAt != nil {
+		return false, nil
+	}
+	r.appended()
+	r.touchEstate(stored)
+	now := time.Now()
+	stored.DeletedAt = &now
+	r.audit(id, "estate", "delete", 0, 0)
+	return true, nil
+}
+
+// RestoreEstate undoes the soft-delete of the estate and records it in the
+// audit log. It reports whether a deleted estate was found.
+func (r *MemoryRepository) RestoreEstate(ctx context.Context, id string) (restored bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.estates[id]
+	if stored == nil || stored.DeletedAt == nil {
+		return false, nil
+	}
+	if r.codeTaken(stored.Code, id) {
+		return false, errDuplicateCode
+	}
+	r.appended()
+	r.touchEstate(stored)
+	stored.DeletedAt = nil
+	r.audit(id, "estate", "restore", 0, 0)
+	return true, nil
+}
+
+func (r *MemoryRepository) CreateTree(ctx context.Context, estateID string, tree m.Tree) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.estates[estateID] == nil {
+		return "", errUnknownEstate
+	}
+	for _, t := range r.trees {
+		if t.estateID == estateID && t.tree.X == tree.X && t.tree.Y == tree.Y && t.tree.DeletedAt == nil {
+			return "", errDuplicateTree
+		}
+	}
+	tree.Location = nil
+	tree.DeletedAt = nil
+	id = uuid.NewString()
+	r.appended()
+	r.trees = append(r.trees, &memoryTree{id: id, estateID: estateID, tree: copyTree(tree)})
+	return id, nil
+}
+
+// estateTrees returns the trees of the estate ordered by row then column,
+// soft-deleted ones only when the context asks for them.
+func (r *MemoryRepository) estateTrees(ctx context.Context, estateID string) (trees []m.Tree) {
+	include := includeDeleted(ctx)
+	for _, t := range r.trees {
+		if t.estateID == estateID && (include || t.tree.DeletedAt == nil) {
+			trees = append(trees, copyTree(t.tree))
+		}
+	}
+	slices.SortStableFunc(trees, func(a, b m.Tree) int {
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return trees
+}
+
+func (r *MemoryRepository) GetTree(ctx context.Context, estateID string) (trees []m.Tree, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.estateTrees(ctx, estateID), nil
+}
+
+// EachTree calls fn with a copy of every tree of the estate, taken before the
+// first call.
+func (r *MemoryRepository) EachTree(ctx context.Context, estateID string, fn func(tree m.Tree) error) (err error) {
+	r.mu.RLock()
+	trees := r.estateTrees(ctx, estateID)
+	r.mu.RUnlock()
+
+	for _, tree := range trees {
+		if err := fn(tree); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachTreeOfEstates calls fn with a copy of every tree of the estates,
+// ordered by estate ID, taken before the first call.
+func (r *MemoryRepository) EachTreeOfEstates(ctx context.Context, estateIDs []string, fn func(estateID string, tree m.Tree) error) (err error) {
+	estateIDs = slices.Clone(estateIDs)
+	slices.Sort(estateIDs)
+	estateIDs = slices.Compact(estateIDs)
+
+	r.mu.RLock()
+	trees := make([][]m.Tree, len(estateIDs))
+	for i, estateID := range estateIDs {
+		trees[i] = r.estateTrees(ctx, estateID)
+	}
+	r.mu.RUnlock()
+
+	for i, estateID := range estateIDs {
+		for _, tree := range trees[i] {
+			if err := fn(estateID, tree); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// GetTreeAt returns the tree of plot (x, y), the live one before those
+// deleted. found is false when the plot has none.
+func (r *MemoryRepository) GetTreeAt(ctx context.Context, estateID string, x int, y int) (tree m.Tree, found bool, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	include := includeDeleted(ctx)
+	for _, t := range r.trees {
+		if t.estateID != estateID || t.tree.X != x || t.tree.Y != y || (!include && t.tree.DeletedAt != nil) {
+			continue
+		}
+		if !found || tree.DeletedAt != nil && (t.tree.DeletedAt == nil || t.tree.DeletedAt.After(*tree.DeletedAt)) {
+			tree, found = copyTree(t.tree), true
+		}
+	}
+	return tree, found, nil
+}
+
+// DeleteTree soft-deletes the trees of plot (x, y) and records it in the
+// audit log. It reports whether a tree was found.
+func (r *MemoryRepository) DeleteTree(ctx context.Context, estateID string, x int, y int) (deleted bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.appended()
+	now := time.Now()
+	for _, t := range r.trees {
+		if t.estateID == estateID && t.tree.X == x && t.tree.Y == y && t.tree.DeletedAt == nil {
+			r.touchTree(t)
+			t.tree.DeletedAt = &now
+			deleted = true
+		}
+	}
+	if deleted {
+		r.audit(estateID, "tree", "delete", x, y)
+	}
+	return deleted, nil
+}
+
+// RestoreTree restores the trees of plot (x, y) deleted last and records it
+// in the audit log. It reports whether a deleted tree was found.
+func (r *MemoryRepository) RestoreTree(ctx context.Context, estateID string, x int, y int) (restored bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var last *time.Time
+	for _, t := range r.trees {
+		if t.estateID == estateID && t.tree.X == x && t.tree.Y == y && t.tree.DeletedAt != nil &&
+			(last == nil || t.tree.DeletedAt.After(*last)) {
+			last = t.tree.DeletedAt
+		}
+	}
+	if last == nil {
+		return false, nil
+	}
+	r.appended()
+	at := *last
+	for _, t := range r.trees {
+		if t.estateID == estateID && t.tree.X == x && t.tree.Y == y && t.tree.DeletedAt != nil && t.tree.DeletedAt.Equal(at) {
+			r.touchTree(t)
+			t.tree.DeletedAt = nil
+		}
+	}
+	r.audit(estateID, "tree", "restore", x, y)
+	return true, nil
+}
+
+// ListEstates returns the id and size of the estates, ordered by id.
+func (r *MemoryRepository) ListEstates(ctx context.Context) (estates []m.Estate, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	include := includeDeleted(ctx)
+	for _, estate := range r.estates {
+		if include || estate.DeletedAt == nil {
+			estates = append(estates, m.Estate{ID: estate.ID, Length: estate.Length, Width: estate.Width})
+		}
+	}
+	slices.SortFunc(estates, func(a, b m.Estate) int { return strings.Compare(a.ID, b.ID) })
+	return estates, nil
+}
+
+// SearchEstates lists the estates whose name or code contains the query and
+// that carry the tag, ordered by name. Empty criteria match every estate.
+func (r *MemoryRepository) SearchEstates(ctx context.Context, search m.EstateSearch) (estates []m.Estate, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	query := strings.ToLower(search.Query)
+	include := includeDeleted(ctx)
+	for _, estate := range r.estates {
+		if !include && estate.DeletedAt != nil {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(estate.Name), query) && !strings.Contains(strings.ToLower(estate.Code), query) {
+			continue
+		}
+		if search.Tag != "" && !slices.Contains(estate.Tags, search.Tag) {
+			continue
+		}
+		estates = append(estates, copyEstate(estate))
+	}
+	slices.SortFunc(estates, func(a, b m.Estate) int {
+		if a.Name != b.Name {
+			return strings.Compare(a.Name, b.Name)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return estates, nil
+}
+
+func (r *MemoryRepository) CreateDivision(ctx context.Context, division m.Division) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.estates[division.EstateID] == nil {
+		return "", errUnknownEstate
+	}
+	division.ID = uuid.NewString()
+	division.Blocks = nil
+	r.appended()
+	r.divisions = append(r.divisions, division)
+	return division.ID, nil
+}
+
+func (r *MemoryRepository) GetDivisions(ctx context.Context, estateID string) (divisions []m.Division, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, division := range r.divisions {
+		if division.EstateID == estateID {
+			divisions = append(divisions, division)
+		}
+	}
+	slices.SortStableFunc(divisions, func(a, b m.Division) int { return strings.Compare(a.Name, b.Name) })
+	return divisions, nil
+}
+
+func (r *MemoryRepository) CreateBlock(ctx context.Context, block m.Block) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.estates[block.EstateID] == nil {
+		return "", errUnknownEstate
+	}
+	if !slices.ContainsFunc(r.divisions, func(d m.Division) bool { return d.ID == block.DivisionID }) {
+		return "", errors.New("memory: division is not exist")
+	}
+	block.ID = uuid.NewString()
+	r.appended()
+	r.blocks = append(r.blocks, block)
+	return block.ID, nil
+}
+
+func (r *MemoryRepository) GetBlocks(ctx context.Context, estateID string) (blocks []m.Block, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, block := range r.blocks {
+		if block.EstateID == estateID {
+			blocks = append(blocks, block)
+		}
+	}
+	slices.SortStableFunc(blocks, func(a, b m.Block) int { return strings.Compare(a.Name, b.Name) })
+	return blocks, nil
+}
+
+// AssignTreesToBlock moves the trees already planted inside the extent of the
+// block into it.
+func (r *MemoryRepository) AssignTreesToBlock(ctx context.Context, block m.Block) (err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, t := range r.trees {
+		if t.estateID == block.EstateID &&
+			t.tree.X >= block.Extent.XMin && t.tree.X <= block.Extent.XMax &&
+			t.tree.Y >= block.Extent.YMin && t.tree.Y <= block.Extent.YMax {
+			r.touchTree(t)
+			t.tree.BlockID = block.ID
+		}
+	}
+	return nil
+}
+
+// PurgeDeleted hard-deletes the trees and estates soft-deleted before the
+// given time, with everything recorded in the purged estates, and records
+// them in the audit log.
+func (r *MemoryRepository) PurgeDeleted(ctx context.Context, before time.Time) (purged m.PurgeResult, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// the purge deletes across the collections in place, so a transaction
+	// keeps a copy of them to revert to
+	if r.undo != nil {
+		old := r.memoryData
+		old.estates = maps.Clone(r.estates)
+		old.trees = slices.Clone(r.trees)
+		old.divisions = slices.Clone(r.divisions)
+		old.blocks = slices.Clone(r.blocks)
+		old.observations = slices.Clone(r.observations)
+		old.harvests = slices.Clone(r.harvests)
+		old.rounds = slices.Clone(r.rounds)
+		old.programs = slices.Clone(r.programs)
+		old.applications = slices.Clone(r.applications)
+		r.changed(func(d *memoryData) { *d = old })
+	}
+
+	r.trees = slices.DeleteFunc(r.trees, func(t *memoryTree) bool {
+		if t.tree.DeletedAt == nil || !t.tree.DeletedAt.Before(before) {
+			return false
+		}
+		r.audit(t.estateID, "tree", "purge", t.tree.X, t.tree.Y)
+		purged.Trees++
+		return true
+	})
+
+	gone := map[string]bool{}
+	for id, estate := range r.estates {
+		if estate.DeletedAt != nil && estate.DeletedAt.Before(before) {
+			gone[id] = true
+			delete(r.estates, id)
+			r.audit(id, "estate", "purge", 0, 0)
+		}
+	}
+	purged.Estates = len(gone)
+	if len(gone) == 0 {
+		return purged, nil
+	}
+
+	// cascade to everything the purged estates own
+	r.trees = slices.DeleteFunc(r.trees, func(t *memoryTree) bool { return gone[t.estateID] })
+	r.divisions = slices.DeleteFunc(r.divisions, func(d m.Division) bool { return gone[d.EstateID] })
+	r.blocks = slices.DeleteFunc(r.blocks, func(b m.Block) bool { return gone[b.EstateID] })
+	r.observations = slices.DeleteFunc(r.observations, func(o m.Observation) bool { return gone[o.EstateID] })
+	r.harvests = slices.DeleteFunc(r.harvests, func(h m.Harvest) bool { return gone[h.EstateID] })
+	r.rounds = slices.DeleteFunc(r.rounds, func(h *m.HarvestRound) bool { return gone[h.EstateID] })
+	programs := map[string]bool{}
+	r.programs = slices.DeleteFunc(r.programs, func(p m.FertilizerProgram) bool {
+		programs[p.ID] = gone[p.EstateID]
+		return gone[p.EstateID]
+	})
+	r.applications = slices.DeleteFunc(r.applications, func(a m.FertilizerApplication) bool { return programs[a.ProgramID] })
+	return purged, nil
+}
+
+func (r *MemoryRepository) GetAuditLog(ctx context.Context, estateID string) (entries []m.AuditEntry, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, entry := range r.auditLog {
+		if entry.EstateID == estateID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (r *MemoryRepository) CreateObservation(ctx context.Context, observation m.Observation) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.estates[observation.EstateID] == nil {
+		return "", errUnknownEstate
+	}
+	observation.ID = uuid.NewString()
+	r.appended()
+	r.observations = append(r.observations, observation)
+	return observation.ID, nil
+}
+
+// GetObservations returns the observations of the estate matching the filter,
+// oldest first.
+func (r *MemoryRepository) GetObservations(ctx context.Context, estateID string, filter m.ObservationFilter) (observations []m.Observation, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, o := range r.observations {
+		if o.EstateID != estateID ||
+			filter.Type != "" && o.Type != filter.Type ||
+			filter.X != 0 && o.X != filter.X ||
+			filter.Y != 0 && o.Y != filter.Y ||
+			filter.Since != nil && o.ObservedAt.Before(*filter.Since) {
+			continue
+		}
+		observations = append(observations, o)
+	}
+	slices.SortFunc(observations, func(a, b m.Observation) int {
+		if c := a.ObservedAt.Compare(b.ObservedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return observations, nil
+}
+
+func (r *MemoryRepository) CreateHarvest(ctx context.Context, harvest m.Harvest) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.estates[harvest.EstateID] == nil {
+		return "", errUnknownEstate
+	}
+	for _, h := range r.harvests {
+		if h.EstateID == harvest.EstateID && h.X == harvest.X && h.Y == harvest.Y && h.HarvestedAt.Equal(harvest.HarvestedAt) {
+			return "", errDuplicateCrop
+		}
+	}
+	harvest.ID = uuid.NewString()
+	r.appended()
+	r.harvests = append(r.harvests, harvest)
+	return harvest.ID, nil
+}
+
+// GetHarvests returns the harvests of the estate between from and to, both
+// included when set, oldest first.
+func (r *MemoryRepository) GetHarvests(ctx context.Context, estateID string, from *time.Time, to *time.Time) (harvests []m.Harvest, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, h := range r.harvests {
+		if h.EstateID != estateID ||
+			from != nil && h.HarvestedAt.Before(*from) ||
+			to != nil && h.HarvestedAt.After(*to) {
+			continue
+		}
+		harvests = append(harvests, h)
+	}
+	slices.SortStableFunc(harvests, func(a, b m.Harvest) int {
+		if c := a.HarvestedAt.Compare(b.HarvestedAt); c != 0 {
+			return c
+		}
+		if a.Y != b.Y {
+			return a.Y - b.Y
+		}
+		return a.X - b.X
+	})
+	return harvests, nil
+}
+
+// GetHarvestAt returns the harvest of plot (x, y) on the day. found is false
+// when the plot was not harvested that day.
+func (r *MemoryRepository) GetHarvestAt(ctx context.Context, estateID string, x int, y int, day time.Time) (harvest m.Harvest, found bool, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, h := range r.harvests {
+		if h.EstateID == estateID && h.X == x && h.Y == y && h.HarvestedAt.Equal(day) {
+			return h, true, nil
+		}
+	}
+	return m.Harvest{}, false, nil
+}
+
+func (r *MemoryRepository) CreateHarvestRound(ctx context.Context, round m.HarvestRound) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.estates[round.EstateID] == nil {
+		return "", errUnknownEstate
+	}
+	round.ID = uuid.NewString()
+	round.CompletedAt = nil
+	r.appended()
+	r.rounds = append(r.rounds, &round)
+	return round.ID, nil
+}
+
+// GetHarvestRounds returns the harvest rounds of the estate, earliest first.
+func (r *MemoryRepository) GetHarvestRounds(ctx context.Context, estateID string) (rounds []m.HarvestRound, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, round := range r.rounds {
+		if round.EstateID == estateID {
+			copied := *round
+			copied.CompletedAt = copyTime(round.CompletedAt)
+			rounds = append(rounds, copied)
+		}
+	}
+	slices.SortFunc(rounds, func(a, b m.HarvestRound) int {
+		if c := a.ScheduledOn.Compare(b.ScheduledOn); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return rounds, nil
+}
+
+// CompleteHarvestRound marks the round as completed now. It reports whether
+// an uncompleted round was found.
+func (r *MemoryRepository) CompleteHarvestRound(ctx context.Context, estateID string, roundID string) (completed bool, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, round := range r.rounds {
+		if round.ID == roundID && round.EstateID == estateID && round.CompletedAt == nil {
+			r.changed(func(*memoryData) { round.CompletedAt = nil })
+			now := time.Now()
+			round.CompletedAt = &now
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryRepository) CreateFertilizerProgram(ctx context.Context, program m.FertilizerProgram) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.estates[program.EstateID] == nil {
+		return "", errUnknownEstate
+	}
+	program.ID = uuid.NewString()
+	r.appended()
+	r.programs = append(r.programs, program)
+	return program.ID, nil
+}
+
+func (r *MemoryRepository) GetFertilizerPrograms(ctx context.Context, estateID string) (programs []m.FertilizerProgram, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, program := range r.programs {
+		if program.EstateID == estateID {
+			programs = append(programs, program)
+		}
+	}
+	slices.SortFunc(programs, func(a, b m.FertilizerProgram) int {
+		if a.Product != b.Product {
+			return strings.Compare(a.Product, b.Product)
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return programs, nil
+}
+
+func (r *MemoryRepository) CreateFertilizerApplication(ctx context.Context, application m.FertilizerApplication) (id string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !slices.ContainsFunc(r.programs, func(p m.FertilizerProgram) bool { return p.ID == application.ProgramID }) {
+		return "", errUnknownProgram
+	}
+	application.ID = uuid.NewString()
+	r.appended()
+	r.applications = append(r.applications, application)
+	return application.ID, nil
+}
+
+// GetFertilizerApplications returns the applications of every program of the
+// estate, oldest first.
+func (r *MemoryRepository) GetFertilizerApplications(ctx context.Context, estateID string) (applications []m.FertilizerApplication, err error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	programs := map[string]bool{}
+	for _, program := range r.programs {
+		programs[program.ID] = program.EstateID == estateID
+	}
+	for _, application := range r.applications {
+		if programs[application.ProgramID] {
+			applications = append(applications, application)
+		}
+	}
+	slices.SortFunc(applications, func(a, b m.FertilizerApplication) int {
+		if c := a.AppliedOn.Compare(b.AppliedOn); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	return applications, nil
+}
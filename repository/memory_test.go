@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+func TestMemoryRepository_Copies(t *testing.T) {
+	r := NewMemoryRepository()
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Tags: []string{"north"}, Length: 10, Width: 5, Metadata: map[string]string{"soil": "peat"}})
+
+	// the stored estate is not shared with the caller
+	got, _ := r.GetEstateByID(ctx, id)
+	got.Tags[0], got.Metadata["soil"] = "south", "clay"
+	if again, _ := r.GetEstateByID(ctx, id); again.Tags[0] != "north" || again.Metadata["soil"] != "peat" {
+		t.Errorf("MemoryRepository.GetEstateByID() shares the stored estate")
+	}
+}
+
+func TestMemoryRepository_Concurrent(t *testing.T) {
+	r := NewMemoryRepository()
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 10, Width: 10})
+
+	// every plot twice at once: one tree per plot gets in
+	var wg sync.WaitGroup
+	for i := 0; i < 200; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.CreateTree(ctx, id, m.Tree{X: i%10 + 1, Y: i/10%10 + 1, Height: 5, Status: m.TreeHealthy})
+			r.GetTree(ctx, id)
+		}(i)
+	}
+	wg.Wait()
+
+	if trees, _ := r.GetTree(ctx, id); len(trees) != 100 {
+		t.Errorf("MemoryRepository.CreateTree() stored %d trees, want 100", len(trees))
+	}
+}
+
+func TestMemoryRepository_Rollback(t *testing.T) {
+	r := NewMemoryRepository()
+	ctx := WithDeleted(context.Background())
+	id, _ := r.CreateEstate(ctx, m.Estate{Name: "north", Length: 5, Width: 5})
+	gone, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	r.CreateTree(ctx, id, m.Tree{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy})
+	r.CreateTree(ctx, id, m.Tree{X: 2, Y: 1, Height: 5, Status: m.TreeHealthy})
+	r.DeleteTree(ctx, id, 2, 1)
+	r.DeleteEstate(ctx, gone)
+	roundID, _ := r.CreateHarvestRound(ctx, m.HarvestRound{EstateID: id, Teams: 1})
+
+	snapshot := func() []any {
+		estate, _ := r.GetEstateByID(ctx, id)
+		trees, _ := r.GetTree(ctx, id)
+		rounds, _ := r.GetHarvestRounds(ctx, id)
+		audit, _ := r.GetAuditLog(ctx, id)
+		_, goneErr := r.GetEstateByID(ctx, gone)
+		return []any{estate, trees, rounds, len(audit), goneErr}
+	}
+	before := snapshot()
+
+	// every kind of change made in a failed transaction is reverted, those
+	// of a nested one included
+	failed := errors.New("failed")
+	err := r.WithTx(ctx, func(tx RepositoryInterface) error {
+		tx.UpdateEstate(ctx, m.Estate{ID: id, Name: "south", Length: 9, Width: 9})
+		tx.CreateTree(ctx, id, m.Tree{X: 3, Y: 3, Height: 5, Status: m.TreeHealthy})
+		tx.DeleteTree(ctx, id, 1, 1)
+		tx.RestoreTree(ctx, id, 2, 1)
+		tx.AssignTreesToBlock(ctx, m.Block{ID: "bbb", EstateID: id, Extent: m.Extent{XMin: 1, XMax: 5, YMin: 1, YMax: 5}})
+		tx.CompleteHarvestRound(ctx, id, roundID)
+		tx.WithTx(ctx, func(nested RepositoryInterface) error {
+			_, err := nested.PurgeDeleted(ctx, time.Now().Add(time.Hour))
+			return err
+		})
+		return failed
+	})
+	if !errors.Is(err, failed) {
+		t.Errorf("MemoryRepository.WithTx() error = %v, want %v", err, failed)
+	}
+	if after := snapshot(); !reflect.DeepEqual(after, before) {
+		t.Errorf("MemoryRepository.WithTx() left %+v, want %+v", after, before)
+	}
+}
@@ -0,0 +1,250 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+//go:embed migrations/postgres/*.sql migrations/sqlite/*.sql
+var migrationFiles embed.FS
+
+// migrationLock is the key of the advisory lock held while migrating, so
+// replicas starting together apply every migration exactly once.
+const migrationLock = 7_261_996
+
+// migrator applies the migrations of one SQL dialect, read from a directory
+// of migrationFiles.
+type migrator struct {
+	db  *sql.DB
+	dir string
+	// lock and unlock, when set, are run around the migrations.
+	lock   string
+	unlock string
+	// baseline, when set, tells if the schema was created before the
+	// migrations existed.
+	baseline string
+	// timestamp is the column type of the times.
+	timestamp string
+}
+
+func postgresMigrator(db *sql.DB) *migrator {
+	return &migrator{
+		db:        db,
+		dir:       "migrations/postgres",
+		lock:      fmt.Sprintf("SELECT pg_advisory_lock(%d)", migrationLock),
+		unlock:    fmt.Sprintf("SELECT pg_advisory_unlock(%d)", migrationLock),
+		baseline:  `SELECT to_regclass('schema_migrations') IS NULL AND to_regclass('estate') IS NOT NULL`,
+		timestamp: "TIMESTAMPTZ",
+	}
+}
+
+var migrationName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change read from a pair of files named
+// <version>_<name>.up.sql and <version>_<name>.down.sql.
+type Migration struct {
+	Version int
+	Name    string
+	Up      string
+	Down    string
+}
+
+// loadMigrations reads the migrations of fsys in version order.
+func loadMigrations(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*Migration{}
+	for _, entry := range entries {
+		match := migrationName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("migration %s: bad file name", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		migration := byVersion[version]
+		if migration == nil {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %d: named both %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := []Migration{}
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %d: missing up file", migration.Version)
+		}
+		migrations = append(migrations, *migration)
+	}
+	slices.SortFunc(migrations, func(a, b Migration) int { return a.Version - b.Version })
+	return migrations, nil
+}
+
+// Migrate applies the embedded migrations not applied yet and returns how
+// many it applied.
+func (r *Repository) Migrate(ctx context.Context) (applied int, err error) {
+	return postgresMigrator(r.Db).up(ctx)
+}
+
+// MigrateDown reverts the last steps applied migrations and returns how many
+// it reverted.
+func (r *Repository) MigrateDown(ctx context.Context, steps int) (reverted int, err error) {
+	return postgresMigrator(r.Db).down(ctx, steps)
+}
+
+func (mg *migrator) migrations() ([]Migration, error) {
+	fsys, err := fs.Sub(migrationFiles, mg.dir)
+	if err != nil {
+		return nil, err
+	}
+	return loadMigrations(fsys)
+}
+
+func (mg *migrator) up(ctx context.Context) (applied int, err error) {
+	migrations, err := mg.migrations()
+	if err != nil {
+		return 0, err
+	}
+	return mg.migrateUp(ctx, migrations)
+}
+
+func (mg *migrator) down(ctx context.Context, steps int) (reverted int, err error) {
+	migrations, err := mg.migrations()
+	if err != nil {
+		return 0, err
+	}
+	return mg.migrateDown(ctx, migrations, steps)
+}
+
+func (mg *migrator) migrateUp(ctx context.Context, migrations []Migration) (applied int, err error) {
+	err = mg.withLock(ctx, func(conn *sql.Conn, done map[int]bool) error {
+		for _, migration := range migrations {
+			if done[migration.Version] {
+				continue
+			}
+			err := runMigration(ctx, conn, migration.Up, `INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`, migration.Version, migration.Name)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			applied++
+		}
+		return nil
+	})
+	return applied, err
+}
+
+func (mg *migrator) migrateDown(ctx context.Context, migrations []Migration, steps int) (reverted int, err error) {
+	err = mg.withLock(ctx, func(conn *sql.Conn, done map[int]bool) error {
+		for i := len(migrations) - 1; i >= 0 && reverted < steps; i-- {
+			migration := migrations[i]
+			if !done[migration.Version] {
+				continue
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %d_%s: missing down file", migration.Version, migration.Name)
+			}
+			err := runMigration(ctx, conn, migration.Down, `DELETE FROM schema_migrations WHERE version = $1`, migration.Version)
+			if err != nil {
+				return fmt.Errorf("migration %d_%s: %w", migration.Version, migration.Name, err)
+			}
+			reverted++
+		}
+		return nil
+	})
+	return reverted, err
+}
+
+// withLock runs fn on a connection holding the migration lock, with the
+// versions already applied. A database created before migrations existed is
+// taken to be at the first version, the original init script; the later
+// Postgres migrations only create what a newer init script has not.
+func (mg *migrator) withLock(ctx context.Context, fn func(conn *sql.Conn, done map[int]bool) error) error {
+	conn, err := mg.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if mg.lock != "" {
+		if _, err := conn.ExecContext(ctx, mg.lock); err != nil {
+			return err
+		}
+		defer conn.ExecContext(context.Background(), mg.unlock)
+	}
+
+	var baseline bool
+	if mg.baseline != "" {
+		if err := conn.QueryRowContext(ctx, mg.baseline).Scan(&baseline); err != nil {
+			return err
+		}
+	}
+	_, err = conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version INT PRIMARY KEY,
+		name TEXT NOT NULL,
+		applied_at `+mg.timestamp+` NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`)
+	if err != nil {
+		return err
+	}
+	if baseline {
+		if _, err := conn.ExecContext(ctx, `INSERT INTO schema_migrations (version, name) VALUES (1, 'init')`); err != nil {
+			return err
+		}
+	}
+
+	rows, err := conn.QueryContext(ctx, `SELECT version FROM schema_migrations`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	done := map[int]bool{}
+	for rows.Next() {
+		var version int
+		if err := rows.Scan(&version); err != nil {
+			return err
+		}
+		done[version] = true
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	rows.Close()
+
+	return fn(conn, done)
+}
+
+// runMigration runs the script and records it in one transaction.
+func runMigration(ctx context.Context, conn *sql.Conn, script string, record string, args ...any) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, record, args...); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,147 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	got, err := loadMigrations(fstest.MapFS{
+		"0002_tree_index.up.sql":   {Data: []byte("CREATE INDEX")},
+		"0002_tree_index.down.sql": {Data: []byte("DROP INDEX")},
+		"0001_init.up.sql":         {Data: []byte("CREATE TABLE")},
+	})
+	want := []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE"},
+		{Version: 2, Name: "tree_index", Up: "CREATE INDEX", Down: "DROP INDEX"},
+	}
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("loadMigrations() = %+v, %v, want %+v", got, err, want)
+	}
+
+	for name, fsys := range map[string]fstest.MapFS{
+		"bad name":     {"init.sql": {Data: []byte("x")}},
+		"missing up":   {"0001_init.down.sql": {Data: []byte("x")}},
+		"renamed pair": {"0001_init.up.sql": {Data: []byte("x")}, "0001_start.down.sql": {Data: []byte("x")}},
+	} {
+		if _, err := loadMigrations(fsys); err == nil {
+			t.Errorf("loadMigrations() expected error for %s", name)
+		}
+	}
+
+	// the embedded migrations of both dialects start at the initial schema
+	// and stay version for version
+	postgres, err := postgresMigrator(nil).migrations()
+	if err != nil || len(postgres) == 0 || postgres[0].Version != 1 || postgres[0].Down == "" {
+		t.Errorf("postgres migrations = %d migrations, %v", len(postgres), err)
+	}
+	sqlite, err := sqliteMigrator(nil).migrations()
+	if err != nil || len(sqlite) != len(postgres) {
+		t.Errorf("sqlite migrations = %d migrations, %v, want %d", len(sqlite), err, len(postgres))
+	}
+	for i := range sqlite {
+		if i < len(postgres) && (sqlite[i].Version != postgres[i].Version || sqlite[i].Name != postgres[i].Name) {
+			t.Errorf("sqlite migration %d_%s, want %d_%s", sqlite[i].Version, sqlite[i].Name, postgres[i].Version, postgres[i].Name)
+		}
+	}
+}
+
+// expectMigrationLock expects the lock to be taken and the applied versions
+// to be read.
+func expectMigrationLock(mock sqlmock.Sqlmock, baseline bool, versions ...int) {
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_lock(7261996)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT to_regclass('schema_migrations') IS NULL`)).WillReturnRows(sqlmock.NewRows([]string{"baseline"}).AddRow(baseline))
+	mock.ExpectExec(regexp.QuoteMeta(`CREATE TABLE IF NOT EXISTS schema_migrations`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	if baseline {
+		mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES (1, 'init')`)).WillReturnResult(sqlmock.NewResult(0, 1))
+	}
+	rows := sqlmock.NewRows([]string{"version"})
+	for _, version := range versions {
+		rows.AddRow(version)
+	}
+	mock.ExpectQuery(regexp.QuoteMeta(`SELECT version FROM schema_migrations`)).WillReturnRows(rows)
+}
+
+func TestMigrator_migrateUp(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mg := postgresMigrator(db)
+	migrations := []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE estate"},
+		{Version: 2, Name: "tree_index", Up: "CREATE INDEX tree_idx"},
+		{Version: 3, Name: "tree_check", Up: "ALTER TABLE tree"},
+	}
+
+	// a database from before migrations is at the first version
+	expectMigrationLock(mock, true, 1)
+	mock.ExpectBegin()
+	mock.ExpectExec("CREATE INDEX tree_idx").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`INSERT INTO schema_migrations (version, name) VALUES ($1, $2)`)).WithArgs(2, "tree_index").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectBegin()
+	mock.ExpectExec("ALTER TABLE tree").WillReturnError(errors.New("db"))
+	mock.ExpectRollback()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock(7261996)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	applied, err := mg.migrateUp(context.Background(), migrations)
+	if applied != 1 || err == nil {
+		t.Errorf("migrator.migrateUp() = %d, %v, want 1 and error", applied, err)
+	}
+
+	expectMigrationLock(mock, false, 1, 2, 3)
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock(7261996)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	if applied, err := mg.migrateUp(context.Background(), migrations); applied != 0 || err != nil {
+		t.Errorf("migrator.migrateUp() = %d, %v, want nothing to apply", applied, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMigrator_migrateDown(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer db.Close()
+	mg := postgresMigrator(db)
+	migrations := []Migration{
+		{Version: 1, Name: "init", Up: "CREATE TABLE estate"},
+		{Version: 2, Name: "tree_index", Up: "CREATE INDEX tree_idx", Down: "DROP INDEX tree_idx"},
+		{Version: 3, Name: "tree_check", Up: "ALTER TABLE tree", Down: "ALTER TABLE tree DROP"},
+	}
+
+	// version 3 was never applied
+	expectMigrationLock(mock, false, 1, 2)
+	mock.ExpectBegin()
+	mock.ExpectExec("DROP INDEX tree_idx").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta(`DELETE FROM schema_migrations WHERE version = $1`)).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock(7261996)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+	reverted, err := mg.migrateDown(context.Background(), migrations, 1)
+	if reverted != 1 || err != nil {
+		t.Errorf("migrator.migrateDown() = %d, %v, want 1", reverted, err)
+	}
+
+	// the initial schema has no down file here
+	expectMigrationLock(mock, false, 1)
+	mock.ExpectExec(regexp.QuoteMeta(`SELECT pg_advisory_unlock(7261996)`)).WillReturnResult(sqlmock.NewResult(0, 0))
+	if reverted, err := mg.migrateDown(context.Background(), migrations, 1); reverted != 0 || err == nil {
+		t.Errorf("migrator.migrateDown() = %d, %v, want error", reverted, err)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS tree;
+DROP TABLE IF EXISTS estate;
@@ -4,6 +4,12 @@
 -- 2. How you choose the data types and keys.
 -- 3. How you name the fields.
 -- In this assignment we will use PostgreSQL as the database.
+--
+-- Once applied a migration is never edited: schema changes go in a new
+-- numbered pair of up and down files next to this one. Databases created
+-- from the init script before the migrations are recorded at this version,
+-- whichever later tables and columns the script had by then, so the later
+-- migrations only create what is missing.
 
 CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
 
@@ -18,4 +24,4 @@ CREATE TABLE tree (
 	x INT NOT NULL,
 	y INT NOT NULL,
 	height INT NOT NULL
-);
\ No newline at end of file
+);
@@ -0,0 +1,5 @@
+ALTER TABLE estate
+	DROP COLUMN IF EXISTS origin_lat,
+	DROP COLUMN IF EXISTS origin_lon,
+	DROP COLUMN IF EXISTS bearing,
+	DROP COLUMN IF EXISTS plot_size;
@@ -0,0 +1,6 @@
+-- optional geographic anchor, the south-west corner of plot (1,1)
+ALTER TABLE estate
+	ADD COLUMN IF NOT EXISTS origin_lat DOUBLE PRECISION,
+	ADD COLUMN IF NOT EXISTS origin_lon DOUBLE PRECISION,
+	ADD COLUMN IF NOT EXISTS bearing DOUBLE PRECISION,
+	ADD COLUMN IF NOT EXISTS plot_size DOUBLE PRECISION;
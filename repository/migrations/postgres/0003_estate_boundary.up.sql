@@ -0,0 +1,2 @@
+-- optional polygon of the plantable area as [[x, y], ...] plot coordinates
+ALTER TABLE estate ADD COLUMN IF NOT EXISTS boundary JSONB;
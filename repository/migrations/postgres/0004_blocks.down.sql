@@ -0,0 +1,3 @@
+ALTER TABLE tree DROP COLUMN IF EXISTS block_id;
+DROP TABLE IF EXISTS block;
+DROP TABLE IF EXISTS division;
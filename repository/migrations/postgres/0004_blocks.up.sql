@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS division (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	estate_id UUID NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	supervisor TEXT NOT NULL,
+	x_min INT NOT NULL,
+	y_min INT NOT NULL,
+	x_max INT NOT NULL,
+	y_max INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS division_estate_id_idx ON division (estate_id);
+
+CREATE TABLE IF NOT EXISTS block (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	estate_id UUID NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	division_id UUID NOT NULL REFERENCES division (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	supervisor TEXT NOT NULL,
+	x_min INT NOT NULL,
+	y_min INT NOT NULL,
+	x_max INT NOT NULL,
+	y_max INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS block_estate_id_idx ON block (estate_id);
+
+ALTER TABLE tree ADD COLUMN IF NOT EXISTS block_id UUID REFERENCES block (id) ON DELETE SET NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE tree DROP CONSTRAINT IF EXISTS tree_estate_id_fkey;
+ALTER TABLE estate
+	DROP COLUMN IF EXISTS name,
+	DROP COLUMN IF EXISTS metadata;
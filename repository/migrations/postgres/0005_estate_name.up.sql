@@ -0,0 +1,10 @@
+ALTER TABLE estate
+	ADD COLUMN IF NOT EXISTS name TEXT NOT NULL DEFAULT '',
+	-- free-form string properties as a JSON object
+	ADD COLUMN IF NOT EXISTS metadata JSONB;
+
+-- trees go with their estate; trees of an estate that no longer exists could
+-- not be reached and are removed so the key can be added
+DELETE FROM tree WHERE estate_id NOT IN (SELECT id FROM estate);
+ALTER TABLE tree DROP CONSTRAINT IF EXISTS tree_estate_id_fkey;
+ALTER TABLE tree ADD CONSTRAINT tree_estate_id_fkey FOREIGN KEY (estate_id) REFERENCES estate (id) ON DELETE CASCADE;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS audit_log;
+ALTER TABLE tree DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE estate DROP COLUMN IF EXISTS deleted_at;
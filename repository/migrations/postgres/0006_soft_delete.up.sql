@@ -0,0 +1,17 @@
+-- set when soft-deleted, purged after the retention window
+ALTER TABLE estate ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+ALTER TABLE tree ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMPTZ;
+
+-- deletes, restores and purges of estates and trees; x and y are only set
+-- for trees. There is no foreign key so the trail survives a purge.
+CREATE TABLE IF NOT EXISTS audit_log (
+	id BIGSERIAL PRIMARY KEY,
+	estate_id UUID NOT NULL,
+	entity TEXT NOT NULL,
+	action TEXT NOT NULL,
+	x INT,
+	y INT,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_estate_id_idx ON audit_log (estate_id);
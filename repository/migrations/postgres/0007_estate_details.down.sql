@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS estate_tags_idx;
+DROP INDEX IF EXISTS estate_code_idx;
+ALTER TABLE estate
+	DROP COLUMN IF EXISTS code,
+	DROP COLUMN IF EXISTS owner,
+	DROP COLUMN IF EXISTS region,
+	DROP COLUMN IF EXISTS planting_year,
+	DROP COLUMN IF EXISTS tags;
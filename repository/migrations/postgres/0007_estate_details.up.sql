@@ -0,0 +1,10 @@
+ALTER TABLE estate
+	-- short reference used by the field teams, unique among live estates
+	ADD COLUMN IF NOT EXISTS code TEXT,
+	ADD COLUMN IF NOT EXISTS owner TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS region TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS planting_year INT,
+	ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE UNIQUE INDEX IF NOT EXISTS estate_code_idx ON estate (code) WHERE deleted_at IS NULL;
+CREATE INDEX IF NOT EXISTS estate_tags_idx ON estate USING GIN (tags);
@@ -0,0 +1,4 @@
+ALTER TABLE tree
+	DROP COLUMN IF EXISTS variety,
+	DROP COLUMN IF EXISTS planted_at,
+	DROP COLUMN IF EXISTS status;
@@ -0,0 +1,5 @@
+ALTER TABLE tree
+	-- clone variety, e.g. DxP
+	ADD COLUMN IF NOT EXISTS variety TEXT NOT NULL DEFAULT '',
+	ADD COLUMN IF NOT EXISTS planted_at DATE,
+	ADD COLUMN IF NOT EXISTS status TEXT NOT NULL DEFAULT 'healthy' CHECK (status IN ('healthy', 'diseased', 'dead', 'replanted'));
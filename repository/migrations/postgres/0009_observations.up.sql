@@ -0,0 +1,13 @@
+-- pest and disease sightings, e.g. ganoderma, on the tree at x, y
+CREATE TABLE IF NOT EXISTS observation (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	estate_id UUID NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	x INT NOT NULL,
+	y INT NOT NULL,
+	type TEXT NOT NULL,
+	severity INT NOT NULL CHECK (severity BETWEEN 1 AND 5),
+	observed_at TIMESTAMPTZ NOT NULL,
+	notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX IF NOT EXISTS observation_estate_id_idx ON observation (estate_id, observed_at);
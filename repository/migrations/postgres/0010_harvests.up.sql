@@ -0,0 +1,12 @@
+-- fresh fruit bunches cut from the tree at x, y in a harvest round
+CREATE TABLE IF NOT EXISTS harvest (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	estate_id UUID NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	x INT NOT NULL,
+	y INT NOT NULL,
+	harvested_at DATE NOT NULL,
+	bunches INT NOT NULL CHECK (bunches >= 0),
+	-- kilograms
+	weight DOUBLE PRECISION NOT NULL CHECK (weight >= 0),
+	UNIQUE (estate_id, harvested_at, x, y)
+);
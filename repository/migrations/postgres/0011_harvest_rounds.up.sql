@@ -0,0 +1,11 @@
+-- harvest of an estate, or of one of its blocks, split between teams
+CREATE TABLE IF NOT EXISTS harvest_round (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	estate_id UUID NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	block_id UUID REFERENCES block (id) ON DELETE CASCADE,
+	teams INT NOT NULL CHECK (teams > 0),
+	scheduled_on DATE NOT NULL,
+	completed_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS harvest_round_estate_id_idx ON harvest_round (estate_id, scheduled_on);
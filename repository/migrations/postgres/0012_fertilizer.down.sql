@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS fertilizer_application;
+DROP TABLE IF EXISTS fertilizer_program;
@@ -0,0 +1,23 @@
+-- dose in kilograms per tree, interval in days
+CREATE TABLE IF NOT EXISTS fertilizer_program (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	estate_id UUID NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	block_id UUID REFERENCES block (id) ON DELETE CASCADE,
+	product TEXT NOT NULL,
+	dose DOUBLE PRECISION NOT NULL CHECK (dose > 0),
+	interval_days INT NOT NULL CHECK (interval_days > 0),
+	start_on DATE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS fertilizer_program_estate_id_idx ON fertilizer_program (estate_id);
+
+-- quantity in kilograms
+CREATE TABLE IF NOT EXISTS fertilizer_application (
+	id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+	program_id UUID NOT NULL REFERENCES fertilizer_program (id) ON DELETE CASCADE,
+	applied_on DATE NOT NULL,
+	trees INT NOT NULL CHECK (trees >= 0),
+	quantity DOUBLE PRECISION NOT NULL CHECK (quantity >= 0)
+);
+
+CREATE INDEX IF NOT EXISTS fertilizer_application_program_id_idx ON fertilizer_application (program_id, applied_on);
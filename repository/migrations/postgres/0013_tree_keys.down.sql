@@ -0,0 +1,5 @@
+-- the duplicated trees removed by the up migration are not restored
+DROP INDEX IF EXISTS tree_plot_idx;
+ALTER TABLE tree DROP CONSTRAINT IF EXISTS tree_height_check;
+ALTER TABLE tree DROP CONSTRAINT IF EXISTS tree_plot_check;
+ALTER TABLE tree DROP COLUMN IF EXISTS id;
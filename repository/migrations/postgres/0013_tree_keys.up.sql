@@ -0,0 +1,22 @@
+-- keep one live tree per plot: the tallest, then the one planted last. Trees
+-- still tied match in every column, so it does not matter which copy stays.
+DELETE FROM tree WHERE ctid IN (
+	SELECT ctid FROM (
+		SELECT ctid, row_number() OVER (
+			PARTITION BY estate_id, y, x
+			ORDER BY height DESC, planted_at DESC NULLS LAST, status, variety, block_id
+		) AS rank
+		FROM tree WHERE deleted_at IS NULL
+	) ranked WHERE rank > 1
+);
+
+ALTER TABLE tree ADD COLUMN id UUID PRIMARY KEY DEFAULT uuid_generate_v4();
+
+-- the same ranges as the usecase validation; the upper bounds of x and y
+-- depend on the estate and are left to the usecase
+ALTER TABLE tree ADD CONSTRAINT tree_plot_check CHECK (x >= 1 AND y >= 1);
+ALTER TABLE tree ADD CONSTRAINT tree_height_check CHECK (height BETWEEN 1 AND 30);
+
+-- deleted trees stay until purged, so only live trees are unique per plot;
+-- row by row, it is also the order the drone flies over the estate
+CREATE UNIQUE INDEX tree_plot_idx ON tree (estate_id, y, x) WHERE deleted_at IS NULL;
@@ -0,0 +1,17 @@
+-- The schema of migrations/postgres in the SQLite dialect, version for
+-- version. IDs are UUIDs generated by the application, times are UTC text,
+-- and tags, metadata and boundary are JSON text. SQLite can not add keys or
+-- drop a column used by one, so those migrations rebuild the table.
+
+CREATE TABLE estate (
+	id TEXT PRIMARY KEY,
+	length INTEGER NOT NULL,
+	width INTEGER NOT NULL
+);
+
+CREATE TABLE tree (
+	estate_id TEXT NOT NULL,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	height INTEGER NOT NULL
+);
@@ -0,0 +1,4 @@
+ALTER TABLE estate DROP COLUMN origin_lat;
+ALTER TABLE estate DROP COLUMN origin_lon;
+ALTER TABLE estate DROP COLUMN bearing;
+ALTER TABLE estate DROP COLUMN plot_size;
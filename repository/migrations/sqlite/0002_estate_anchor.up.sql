@@ -0,0 +1,5 @@
+-- optional geographic anchor, the south-west corner of plot (1,1)
+ALTER TABLE estate ADD COLUMN origin_lat REAL;
+ALTER TABLE estate ADD COLUMN origin_lon REAL;
+ALTER TABLE estate ADD COLUMN bearing REAL;
+ALTER TABLE estate ADD COLUMN plot_size REAL;
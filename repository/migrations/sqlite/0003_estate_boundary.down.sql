@@ -0,0 +1 @@
+ALTER TABLE estate DROP COLUMN boundary;
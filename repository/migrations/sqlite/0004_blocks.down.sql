@@ -0,0 +1,17 @@
+-- block_id is a foreign key, which SQLite can not drop, so the table is
+-- rebuilt without it
+CREATE TABLE tree_unblocked (
+	estate_id TEXT NOT NULL,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	height INTEGER NOT NULL
+);
+
+INSERT INTO tree_unblocked (estate_id, x, y, height)
+SELECT estate_id, x, y, height FROM tree;
+
+DROP TABLE tree;
+ALTER TABLE tree_unblocked RENAME TO tree;
+
+DROP TABLE block;
+DROP TABLE division;
@@ -0,0 +1,28 @@
+CREATE TABLE division (
+	id TEXT PRIMARY KEY,
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	supervisor TEXT NOT NULL,
+	x_min INTEGER NOT NULL,
+	y_min INTEGER NOT NULL,
+	x_max INTEGER NOT NULL,
+	y_max INTEGER NOT NULL
+);
+
+CREATE INDEX division_estate_id_idx ON division (estate_id);
+
+CREATE TABLE block (
+	id TEXT PRIMARY KEY,
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	division_id TEXT NOT NULL REFERENCES division (id) ON DELETE CASCADE,
+	name TEXT NOT NULL,
+	supervisor TEXT NOT NULL,
+	x_min INTEGER NOT NULL,
+	y_min INTEGER NOT NULL,
+	x_max INTEGER NOT NULL,
+	y_max INTEGER NOT NULL
+);
+
+CREATE INDEX block_estate_id_idx ON block (estate_id);
+
+ALTER TABLE tree ADD COLUMN block_id TEXT REFERENCES block (id) ON DELETE SET NULL;
@@ -0,0 +1,16 @@
+CREATE TABLE tree_unowned (
+	estate_id TEXT NOT NULL,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	block_id TEXT REFERENCES block (id) ON DELETE SET NULL
+);
+
+INSERT INTO tree_unowned (estate_id, x, y, height, block_id)
+SELECT estate_id, x, y, height, block_id FROM tree;
+
+DROP TABLE tree;
+ALTER TABLE tree_unowned RENAME TO tree;
+
+ALTER TABLE estate DROP COLUMN name;
+ALTER TABLE estate DROP COLUMN metadata;
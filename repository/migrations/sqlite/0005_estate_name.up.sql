@@ -0,0 +1,21 @@
+ALTER TABLE estate ADD COLUMN name TEXT NOT NULL DEFAULT '';
+-- free-form string properties as a JSON object
+ALTER TABLE estate ADD COLUMN metadata TEXT;
+
+-- trees go with their estate; trees of an estate that no longer exists could
+-- not be reached and are removed so the key can be added
+DELETE FROM tree WHERE estate_id NOT IN (SELECT id FROM estate);
+
+CREATE TABLE tree_owned (
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	block_id TEXT REFERENCES block (id) ON DELETE SET NULL
+);
+
+INSERT INTO tree_owned (estate_id, x, y, height, block_id)
+SELECT estate_id, x, y, height, block_id FROM tree;
+
+DROP TABLE tree;
+ALTER TABLE tree_owned RENAME TO tree;
@@ -0,0 +1,3 @@
+DROP TABLE audit_log;
+ALTER TABLE tree DROP COLUMN deleted_at;
+ALTER TABLE estate DROP COLUMN deleted_at;
@@ -0,0 +1,17 @@
+-- set when soft-deleted, purged after the retention window
+ALTER TABLE estate ADD COLUMN deleted_at TIMESTAMP;
+ALTER TABLE tree ADD COLUMN deleted_at TIMESTAMP;
+
+-- deletes, restores and purges of estates and trees; x and y are only set
+-- for trees. There is no foreign key so the trail survives a purge.
+CREATE TABLE audit_log (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	estate_id TEXT NOT NULL,
+	entity TEXT NOT NULL,
+	action TEXT NOT NULL,
+	x INTEGER,
+	y INTEGER,
+	created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX audit_log_estate_id_idx ON audit_log (estate_id);
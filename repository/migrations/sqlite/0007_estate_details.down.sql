@@ -0,0 +1,6 @@
+DROP INDEX estate_code_idx;
+ALTER TABLE estate DROP COLUMN code;
+ALTER TABLE estate DROP COLUMN owner;
+ALTER TABLE estate DROP COLUMN region;
+ALTER TABLE estate DROP COLUMN planting_year;
+ALTER TABLE estate DROP COLUMN tags;
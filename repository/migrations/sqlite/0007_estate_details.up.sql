@@ -0,0 +1,9 @@
+-- short reference used by the field teams, unique among live estates
+ALTER TABLE estate ADD COLUMN code TEXT;
+ALTER TABLE estate ADD COLUMN owner TEXT NOT NULL DEFAULT '';
+ALTER TABLE estate ADD COLUMN region TEXT NOT NULL DEFAULT '';
+ALTER TABLE estate ADD COLUMN planting_year INTEGER;
+-- JSON array of strings
+ALTER TABLE estate ADD COLUMN tags TEXT NOT NULL DEFAULT '[]';
+
+CREATE UNIQUE INDEX estate_code_idx ON estate (code) WHERE deleted_at IS NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE tree DROP COLUMN variety;
+ALTER TABLE tree DROP COLUMN planted_at;
+ALTER TABLE tree DROP COLUMN status;
@@ -0,0 +1,4 @@
+-- clone variety, e.g. DxP
+ALTER TABLE tree ADD COLUMN variety TEXT NOT NULL DEFAULT '';
+ALTER TABLE tree ADD COLUMN planted_at DATE;
+ALTER TABLE tree ADD COLUMN status TEXT NOT NULL DEFAULT 'healthy' CHECK (status IN ('healthy', 'diseased', 'dead', 'replanted'));
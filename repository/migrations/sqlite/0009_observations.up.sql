@@ -0,0 +1,13 @@
+-- pest and disease sightings, e.g. ganoderma, on the tree at x, y
+CREATE TABLE observation (
+	id TEXT PRIMARY KEY,
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	type TEXT NOT NULL,
+	severity INTEGER NOT NULL CHECK (severity BETWEEN 1 AND 5),
+	observed_at TIMESTAMP NOT NULL,
+	notes TEXT NOT NULL DEFAULT ''
+);
+
+CREATE INDEX observation_estate_id_idx ON observation (estate_id, observed_at);
@@ -0,0 +1,12 @@
+-- fresh fruit bunches cut from the tree at x, y in a harvest round
+CREATE TABLE harvest (
+	id TEXT PRIMARY KEY,
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	harvested_at DATE NOT NULL,
+	bunches INTEGER NOT NULL CHECK (bunches >= 0),
+	-- kilograms
+	weight REAL NOT NULL CHECK (weight >= 0),
+	UNIQUE (estate_id, harvested_at, x, y)
+);
@@ -0,0 +1,11 @@
+-- harvest of an estate, or of one of its blocks, split between teams
+CREATE TABLE harvest_round (
+	id TEXT PRIMARY KEY,
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	block_id TEXT REFERENCES block (id) ON DELETE CASCADE,
+	teams INTEGER NOT NULL CHECK (teams > 0),
+	scheduled_on DATE NOT NULL,
+	completed_at TIMESTAMP
+);
+
+CREATE INDEX harvest_round_estate_id_idx ON harvest_round (estate_id, scheduled_on);
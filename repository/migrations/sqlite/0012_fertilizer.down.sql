@@ -0,0 +1,2 @@
+DROP TABLE fertilizer_application;
+DROP TABLE fertilizer_program;
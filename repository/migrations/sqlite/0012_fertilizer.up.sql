@@ -0,0 +1,23 @@
+-- dose in kilograms per tree, interval in days
+CREATE TABLE fertilizer_program (
+	id TEXT PRIMARY KEY,
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	block_id TEXT REFERENCES block (id) ON DELETE CASCADE,
+	product TEXT NOT NULL,
+	dose REAL NOT NULL CHECK (dose > 0),
+	interval_days INTEGER NOT NULL CHECK (interval_days > 0),
+	start_on DATE NOT NULL
+);
+
+CREATE INDEX fertilizer_program_estate_id_idx ON fertilizer_program (estate_id);
+
+-- quantity in kilograms
+CREATE TABLE fertilizer_application (
+	id TEXT PRIMARY KEY,
+	program_id TEXT NOT NULL REFERENCES fertilizer_program (id) ON DELETE CASCADE,
+	applied_on DATE NOT NULL,
+	trees INTEGER NOT NULL CHECK (trees >= 0),
+	quantity REAL NOT NULL CHECK (quantity >= 0)
+);
+
+CREATE INDEX fertilizer_application_program_id_idx ON fertilizer_application (program_id, applied_on);
@@ -0,0 +1,18 @@
+-- the duplicated trees removed by the up migration are not restored
+CREATE TABLE tree_unkeyed (
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	block_id TEXT REFERENCES block (id) ON DELETE SET NULL,
+	variety TEXT NOT NULL DEFAULT '',
+	planted_at DATE,
+	status TEXT NOT NULL DEFAULT 'healthy' CHECK (status IN ('healthy', 'diseased', 'dead', 'replanted')),
+	deleted_at TIMESTAMP
+);
+
+INSERT INTO tree_unkeyed (estate_id, x, y, height, block_id, variety, planted_at, status, deleted_at)
+SELECT estate_id, x, y, height, block_id, variety, planted_at, status, deleted_at FROM tree;
+
+DROP TABLE tree;
+ALTER TABLE tree_unkeyed RENAME TO tree;
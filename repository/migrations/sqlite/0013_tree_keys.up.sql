@@ -0,0 +1,44 @@
+-- keep one live tree per plot: the tallest, then the one planted last. Trees
+-- still tied match in every column, so it does not matter which copy stays.
+DELETE FROM tree WHERE rowid IN (
+	SELECT rowid FROM (
+		SELECT rowid, row_number() OVER (
+			PARTITION BY estate_id, y, x
+			ORDER BY height DESC, planted_at DESC NULLS LAST, status, variety, block_id
+		) AS rank
+		FROM tree WHERE deleted_at IS NULL
+	) WHERE rank > 1
+);
+
+-- SQLite can not add a primary key or a check to a table, so the table is
+-- rebuilt with them
+CREATE TABLE tree_keyed (
+	id TEXT PRIMARY KEY,
+	estate_id TEXT NOT NULL REFERENCES estate (id) ON DELETE CASCADE,
+	x INTEGER NOT NULL,
+	y INTEGER NOT NULL,
+	height INTEGER NOT NULL,
+	block_id TEXT REFERENCES block (id) ON DELETE SET NULL,
+	variety TEXT NOT NULL DEFAULT '',
+	planted_at DATE,
+	status TEXT NOT NULL DEFAULT 'healthy' CHECK (status IN ('healthy', 'diseased', 'dead', 'replanted')),
+	deleted_at TIMESTAMP,
+	-- the same ranges as the usecase validation; the upper bounds of x and y
+	-- depend on the estate and are left to the usecase
+	CONSTRAINT tree_plot_check CHECK (x >= 1 AND y >= 1),
+	CONSTRAINT tree_height_check CHECK (height BETWEEN 1 AND 30)
+);
+
+-- random version 4 UUIDs for the existing trees
+INSERT INTO tree_keyed (id, estate_id, x, y, height, block_id, variety, planted_at, status, deleted_at)
+SELECT lower(hex(randomblob(4)) || '-' || hex(randomblob(2)) || '-4' || substr(hex(randomblob(2)), 2) || '-' ||
+		substr('89ab', 1 + (abs(random()) % 4), 1) || substr(hex(randomblob(2)), 2) || '-' || hex(randomblob(6))),
+	estate_id, x, y, height, block_id, variety, planted_at, status, deleted_at
+FROM tree;
+
+DROP TABLE tree;
+ALTER TABLE tree_keyed RENAME TO tree;
+
+-- deleted trees stay until purged, so only live trees are unique per plot;
+-- row by row, it is also the order the drone flies over the estate
+CREATE UNIQUE INDEX tree_plot_idx ON tree (estate_id, y, x) WHERE deleted_at IS NULL;
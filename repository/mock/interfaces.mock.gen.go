@@ -12,7 +12,9 @@ package repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
+	repository "github.com/SawitProRecruitment/UserService/repository"
 	types "github.com/SawitProRecruitment/UserService/types"
 	gomock "go.uber.org/mock/gomock"
 )
@@ -40,19 +42,153 @@ func (m *MockRepositoryInterface) EXPECT() *MockRepositoryInterfaceMockRecorder
 	return m.recorder
 }
 
+// AssignTreesToBlock mocks base method.
+func (m *MockRepositoryInterface) AssignTreesToBlock(arg0 context.Context, arg1 types.Block) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssignTreesToBlock", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssignTreesToBlock indicates an expected call of AssignTreesToBlock.
+func (mr *MockRepositoryInterfaceMockRecorder) AssignTreesToBlock(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssignTreesToBlock", reflect.TypeOf((*MockRepositoryInterface)(nil).AssignTreesToBlock), arg0, arg1)
+}
+
+// CompleteHarvestRound mocks base method.
+func (m *MockRepositoryInterface) CompleteHarvestRound(arg0 context.Context, arg1, arg2 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteHarvestRound", arg0, arg1, arg2)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CompleteHarvestRound indicates an expected call of CompleteHarvestRound.
+func (mr *MockRepositoryInterfaceMockRecorder) CompleteHarvestRound(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteHarvestRound", reflect.TypeOf((*MockRepositoryInterface)(nil).CompleteHarvestRound), arg0, arg1, arg2)
+}
+
+// CreateBlock mocks base method.
+func (m *MockRepositoryInterface) CreateBlock(arg0 context.Context, arg1 types.Block) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlock", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBlock indicates an expected call of CreateBlock.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateBlock(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlock", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateBlock), arg0, arg1)
+}
+
+// CreateDivision mocks base method.
+func (m *MockRepositoryInterface) CreateDivision(arg0 context.Context, arg1 types.Division) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDivision", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDivision indicates an expected call of CreateDivision.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateDivision(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDivision", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateDivision), arg0, arg1)
+}
+
 // CreateEstate mocks base method.
-func (m *MockRepositoryInterface) CreateEstate(arg0 context.Context, arg1, arg2 int) (string, error) {
+func (m *MockRepositoryInterface) CreateEstate(arg0 context.Context, arg1 types.Estate) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEstate", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "CreateEstate", arg0, arg1)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateEstate indicates an expected call of CreateEstate.
-func (mr *MockRepositoryInterfaceMockRecorder) CreateEstate(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockRepositoryInterfaceMockRecorder) CreateEstate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEstate", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateEstate), arg0, arg1)
+}
+
+// CreateFertilizerApplication mocks base method.
+func (m *MockRepositoryInterface) CreateFertilizerApplication(arg0 context.Context, arg1 types.FertilizerApplication) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFertilizerApplication", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFertilizerApplication indicates an expected call of CreateFertilizerApplication.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateFertilizerApplication(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFertilizerApplication", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateFertilizerApplication), arg0, arg1)
+}
+
+// CreateFertilizerProgram mocks base method.
+func (m *MockRepositoryInterface) CreateFertilizerProgram(arg0 context.Context, arg1 types.FertilizerProgram) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFertilizerProgram", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFertilizerProgram indicates an expected call of CreateFertilizerProgram.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateFertilizerProgram(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFertilizerProgram", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateFertilizerProgram), arg0, arg1)
+}
+
+// CreateHarvest mocks base method.
+func (m *MockRepositoryInterface) CreateHarvest(arg0 context.Context, arg1 types.Harvest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHarvest", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHarvest indicates an expected call of CreateHarvest.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateHarvest(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEstate", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateEstate), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHarvest", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateHarvest), arg0, arg1)
+}
+
+// CreateHarvestRound mocks base method.
+func (m *MockRepositoryInterface) CreateHarvestRound(arg0 context.Context, arg1 types.HarvestRound) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHarvestRound", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHarvestRound indicates an expected call of CreateHarvestRound.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateHarvestRound(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHarvestRound", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateHarvestRound), arg0, arg1)
+}
+
+// CreateObservation mocks base method.
+func (m *MockRepositoryInterface) CreateObservation(arg0 context.Context, arg1 types.Observation) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateObservation", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateObservation indicates an expected call of CreateObservation.
+func (mr *MockRepositoryInterfaceMockRecorder) CreateObservation(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateObservation", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateObservation), arg0, arg1)
 }
 
 // CreateTree mocks base method.
@@ -70,6 +206,109 @@ func (mr *MockRepositoryInterfaceMockRecorder) CreateTree(arg0, arg1, arg2 any)
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTree", reflect.TypeOf((*MockRepositoryInterface)(nil).CreateTree), arg0, arg1, arg2)
 }
 
+// DeleteEstate mocks base method.
+func (m *MockRepositoryInterface) DeleteEstate(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEstate", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteEstate indicates an expected call of DeleteEstate.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteEstate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEstate", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteEstate), arg0, arg1)
+}
+
+// DeleteTree mocks base method.
+func (m *MockRepositoryInterface) DeleteTree(arg0 context.Context, arg1 string, arg2, arg3 int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTree", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTree indicates an expected call of DeleteTree.
+func (mr *MockRepositoryInterfaceMockRecorder) DeleteTree(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTree", reflect.TypeOf((*MockRepositoryInterface)(nil).DeleteTree), arg0, arg1, arg2, arg3)
+}
+
+// EachTree mocks base method.
+func (m *MockRepositoryInterface) EachTree(arg0 context.Context, arg1 string, arg2 func(types.Tree) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachTree", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachTree indicates an expected call of EachTree.
+func (mr *MockRepositoryInterfaceMockRecorder) EachTree(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachTree", reflect.TypeOf((*MockRepositoryInterface)(nil).EachTree), arg0, arg1, arg2)
+}
+
+// EachTreeOfEstates mocks base method.
+func (m *MockRepositoryInterface) EachTreeOfEstates(arg0 context.Context, arg1 []string, arg2 func(string, types.Tree) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachTreeOfEstates", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachTreeOfEstates indicates an expected call of EachTreeOfEstates.
+func (mr *MockRepositoryInterfaceMockRecorder) EachTreeOfEstates(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachTreeOfEstates", reflect.TypeOf((*MockRepositoryInterface)(nil).EachTreeOfEstates), arg0, arg1, arg2)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockRepositoryInterface) GetAuditLog(arg0 context.Context, arg1 string) ([]types.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", arg0, arg1)
+	ret0, _ := ret[0].([]types.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockRepositoryInterfaceMockRecorder) GetAuditLog(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockRepositoryInterface)(nil).GetAuditLog), arg0, arg1)
+}
+
+// GetBlocks mocks base method.
+func (m *MockRepositoryInterface) GetBlocks(arg0 context.Context, arg1 string) ([]types.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocks", arg0, arg1)
+	ret0, _ := ret[0].([]types.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocks indicates an expected call of GetBlocks.
+func (mr *MockRepositoryInterfaceMockRecorder) GetBlocks(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocks", reflect.TypeOf((*MockRepositoryInterface)(nil).GetBlocks), arg0, arg1)
+}
+
+// GetDivisions mocks base method.
+func (m *MockRepositoryInterface) GetDivisions(arg0 context.Context, arg1 string) ([]types.Division, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDivisions", arg0, arg1)
+	ret0, _ := ret[0].([]types.Division)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDivisions indicates an expected call of GetDivisions.
+func (mr *MockRepositoryInterfaceMockRecorder) GetDivisions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDivisions", reflect.TypeOf((*MockRepositoryInterface)(nil).GetDivisions), arg0, arg1)
+}
+
 // GetEstateByID mocks base method.
 func (m *MockRepositoryInterface) GetEstateByID(arg0 context.Context, arg1 string) (types.Estate, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +324,97 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetEstateByID(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateByID", reflect.TypeOf((*MockRepositoryInterface)(nil).GetEstateByID), arg0, arg1)
 }
 
+// GetFertilizerApplications mocks base method.
+func (m *MockRepositoryInterface) GetFertilizerApplications(arg0 context.Context, arg1 string) ([]types.FertilizerApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFertilizerApplications", arg0, arg1)
+	ret0, _ := ret[0].([]types.FertilizerApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFertilizerApplications indicates an expected call of GetFertilizerApplications.
+func (mr *MockRepositoryInterfaceMockRecorder) GetFertilizerApplications(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFertilizerApplications", reflect.TypeOf((*MockRepositoryInterface)(nil).GetFertilizerApplications), arg0, arg1)
+}
+
+// GetFertilizerPrograms mocks base method.
+func (m *MockRepositoryInterface) GetFertilizerPrograms(arg0 context.Context, arg1 string) ([]types.FertilizerProgram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFertilizerPrograms", arg0, arg1)
+	ret0, _ := ret[0].([]types.FertilizerProgram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFertilizerPrograms indicates an expected call of GetFertilizerPrograms.
+func (mr *MockRepositoryInterfaceMockRecorder) GetFertilizerPrograms(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFertilizerPrograms", reflect.TypeOf((*MockRepositoryInterface)(nil).GetFertilizerPrograms), arg0, arg1)
+}
+
+// GetHarvestAt mocks base method.
+func (m *MockRepositoryInterface) GetHarvestAt(arg0 context.Context, arg1 string, arg2, arg3 int, arg4 time.Time) (types.Harvest, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHarvestAt", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].(types.Harvest)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetHarvestAt indicates an expected call of GetHarvestAt.
+func (mr *MockRepositoryInterfaceMockRecorder) GetHarvestAt(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHarvestAt", reflect.TypeOf((*MockRepositoryInterface)(nil).GetHarvestAt), arg0, arg1, arg2, arg3, arg4)
+}
+
+// GetHarvestRounds mocks base method.
+func (m *MockRepositoryInterface) GetHarvestRounds(arg0 context.Context, arg1 string) ([]types.HarvestRound, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHarvestRounds", arg0, arg1)
+	ret0, _ := ret[0].([]types.HarvestRound)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHarvestRounds indicates an expected call of GetHarvestRounds.
+func (mr *MockRepositoryInterfaceMockRecorder) GetHarvestRounds(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHarvestRounds", reflect.TypeOf((*MockRepositoryInterface)(nil).GetHarvestRounds), arg0, arg1)
+}
+
+// GetHarvests mocks base method.
+func (m *MockRepositoryInterface) GetHarvests(arg0 context.Context, arg1 string, arg2, arg3 *time.Time) ([]types.Harvest, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHarvests", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]types.Harvest)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHarvests indicates an expected call of GetHarvests.
+func (mr *MockRepositoryInterfaceMockRecorder) GetHarvests(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHarvests", reflect.TypeOf((*MockRepositoryInterface)(nil).GetHarvests), arg0, arg1, arg2, arg3)
+}
+
+// GetObservations mocks base method.
+func (m *MockRepositoryInterface) GetObservations(arg0 context.Context, arg1 string, arg2 types.ObservationFilter) ([]types.Observation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObservations", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.Observation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObservations indicates an expected call of GetObservations.
+func (mr *MockRepositoryInterfaceMockRecorder) GetObservations(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObservations", reflect.TypeOf((*MockRepositoryInterface)(nil).GetObservations), arg0, arg1, arg2)
+}
+
 // GetTree mocks base method.
 func (m *MockRepositoryInterface) GetTree(arg0 context.Context, arg1 string) ([]types.Tree, error) {
 	m.ctrl.T.Helper()
@@ -99,3 +429,122 @@ func (mr *MockRepositoryInterfaceMockRecorder) GetTree(arg0, arg1 any) *gomock.C
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTree", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTree), arg0, arg1)
 }
+
+// GetTreeAt mocks base method.
+func (m *MockRepositoryInterface) GetTreeAt(arg0 context.Context, arg1 string, arg2, arg3 int) (types.Tree, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreeAt", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(types.Tree)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// GetTreeAt indicates an expected call of GetTreeAt.
+func (mr *MockRepositoryInterfaceMockRecorder) GetTreeAt(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreeAt", reflect.TypeOf((*MockRepositoryInterface)(nil).GetTreeAt), arg0, arg1, arg2, arg3)
+}
+
+// ListEstates mocks base method.
+func (m *MockRepositoryInterface) ListEstates(arg0 context.Context) ([]types.Estate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEstates", arg0)
+	ret0, _ := ret[0].([]types.Estate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEstates indicates an expected call of ListEstates.
+func (mr *MockRepositoryInterfaceMockRecorder) ListEstates(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEstates", reflect.TypeOf((*MockRepositoryInterface)(nil).ListEstates), arg0)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockRepositoryInterface) PurgeDeleted(arg0 context.Context, arg1 time.Time) (types.PurgeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(types.PurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockRepositoryInterfaceMockRecorder) PurgeDeleted(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockRepositoryInterface)(nil).PurgeDeleted), arg0, arg1)
+}
+
+// RestoreEstate mocks base method.
+func (m *MockRepositoryInterface) RestoreEstate(arg0 context.Context, arg1 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEstate", arg0, arg1)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreEstate indicates an expected call of RestoreEstate.
+func (mr *MockRepositoryInterfaceMockRecorder) RestoreEstate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEstate", reflect.TypeOf((*MockRepositoryInterface)(nil).RestoreEstate), arg0, arg1)
+}
+
+// RestoreTree mocks base method.
+func (m *MockRepositoryInterface) RestoreTree(arg0 context.Context, arg1 string, arg2, arg3 int) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTree", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreTree indicates an expected call of RestoreTree.
+func (mr *MockRepositoryInterfaceMockRecorder) RestoreTree(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTree", reflect.TypeOf((*MockRepositoryInterface)(nil).RestoreTree), arg0, arg1, arg2, arg3)
+}
+
+// SearchEstates mocks base method.
+func (m *MockRepositoryInterface) SearchEstates(arg0 context.Context, arg1 types.EstateSearch) ([]types.Estate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEstates", arg0, arg1)
+	ret0, _ := ret[0].([]types.Estate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEstates indicates an expected call of SearchEstates.
+func (mr *MockRepositoryInterfaceMockRecorder) SearchEstates(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEstates", reflect.TypeOf((*MockRepositoryInterface)(nil).SearchEstates), arg0, arg1)
+}
+
+// UpdateEstate mocks base method.
+func (m *MockRepositoryInterface) UpdateEstate(arg0 context.Context, arg1 types.Estate) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEstate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateEstate indicates an expected call of UpdateEstate.
+func (mr *MockRepositoryInterfaceMockRecorder) UpdateEstate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEstate", reflect.TypeOf((*MockRepositoryInterface)(nil).UpdateEstate), arg0, arg1)
+}
+
+// WithTx mocks base method.
+func (m *MockRepositoryInterface) WithTx(arg0 context.Context, arg1 func(repository.RepositoryInterface) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithTx", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithTx indicates an expected call of WithTx.
+func (mr *MockRepositoryInterfaceMockRecorder) WithTx(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepositoryInterface)(nil).WithTx), arg0, arg1)
+}
@@ -2,25 +2,331 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
 
 	_ "github.com/lib/pq"
 )
 
 type Repository struct {
+	// Db is the primary database, which takes every write.
 	Db *sql.DB
+	// Replicas, when set, serve the reads done outside of a transaction,
+	// each read going to one of them at random.
+	Replicas []*sql.DB
+	// QueryTimeout bounds every call, none when zero.
+	QueryTimeout time.Duration
+	// tx is the transaction of the unit of work started by WithTx.
+	tx *sql.Tx
+	// written keeps the reads of the estates written lately on Db, nil when
+	// the reads go to the replicas right away.
+	written *recentWrites
+}
+
+// dbtx runs queries on the database or in a transaction.
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// conn returns the transaction of the unit of work, if any, or the database.
+func (r *Repository) conn() dbtx {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.Db
+}
+
+// reader returns where to read the estates, every estate when none is given:
+// a replica, unless the repository is in a transaction or the estates were
+// written too lately for the replicas to have caught up.
+func (r *Repository) reader(estateIDs ...string) dbtx {
+	if r.tx != nil || len(r.Replicas) == 0 || r.written.recent(estateIDs) {
+		return r.conn()
+	}
+	return r.Replicas[rand.Intn(len(r.Replicas))]
+}
+
+// wrote records a write to the estate, or to estates not known when empty.
+func (r *Repository) wrote(estateID string) {
+	r.written.wrote(estateID)
+}
+
+// recentWrites remembers the estates written lately, so that the reads that
+// follow a write see it whatever the lag of the replicas. Only the writes of
+// this process are known.
+type recentWrites struct {
+	window time.Duration
+
+	mu sync.Mutex
+	// estates holds the time of the last write to each estate.
+	estates map[string]time.Time
+	// last is the time of the last write, unknown of the last one whose
+	// estates are not known, pruned of the last pruning of estates.
+	last, unknown, pruned time.Time
+}
+
+func newRecentWrites(window time.Duration) *recentWrites {
+	if window <= 0 {
+		return nil
+	}
+	return &recentWrites{window: window, estates: map[string]time.Time{}}
+}
+
+func (w *recentWrites) wrote(estateID string) {
+	if w == nil {
+		return
+	}
+	now := time.Now()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if now.Sub(w.pruned) > w.window {
+		for id, at := range w.estates {
+			if now.Sub(at) > w.window {
+				delete(w.estates, id)
+			}
+		}
+		w.pruned = now
+	}
+	w.last = now
+	if estateID == "" {
+		w.unknown = now
+	} else {
+		w.estates[estateID] = now
+	}
+}
+
+// recent tells if one of the estates, any estate when none is given, was
+// written within the window.
+func (w *recentWrites) recent(estateIDs []string) bool {
+	if w == nil {
+		return false
+	}
+	since := time.Now().Add(-w.window)
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if len(estateIDs) == 0 || w.unknown.After(since) {
+		return w.last.After(since)
+	}
+	for _, id := range estateIDs {
+		if w.estates[id].After(since) {
+			return true
+		}
+	}
+	return false
+}
+
+// beginTx runs fn in a transaction of db, committed when fn returns no error.
+func beginTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// WithTx runs fn with a repository whose calls share one transaction,
+// committed when fn returns no error and rolled back otherwise. Estates read
+// with GetEstateByID in the transaction stay locked until it ends. Nested
+// calls join the transaction already running.
+func (r *Repository) WithTx(ctx context.Context, fn func(repo RepositoryInterface) error) (err error) {
+	defer canceled(ctx, &err)
+
+	if r.tx != nil {
+		return fn(r)
+	}
+	return beginTx(ctx, r.Db, func(tx *sql.Tx) error {
+		return fn(&Repository{Db: r.Db, Replicas: r.Replicas, QueryTimeout: r.QueryTimeout, tx: tx, written: r.written})
+	})
+}
+
+// CanceledError reports a repository call stopped because its context ended:
+// canceled, usually by the client going away, or past its deadline.
+type CanceledError struct {
+	// Err is context.Canceled or context.DeadlineExceeded.
+	Err error
+}
+
+func (e *CanceledError) Error() string {
+	return "repository: " + e.Err.Error()
+}
+
+func (e *CanceledError) Unwrap() error {
+	return e.Err
+}
+
+// Timeout tells if the call ran out of time rather than being canceled.
+func (e *CanceledError) Timeout() bool {
+	return errors.Is(e.Err, context.DeadlineExceeded)
+}
+
+// canceled reports the error of a call whose context ended as a
+// *CanceledError, whatever the driver made of it.
+func canceled(ctx context.Context, err *error) {
+	var already *CanceledError
+	if *err != nil && ctx.Err() != nil && !errors.As(*err, &already) {
+		*err = &CanceledError{Err: context.Cause(ctx)}
+	}
+}
+
+// bound gives a call the timeout, when set. The returned func releases the
+// context and reports an error caused by its end as a *CanceledError.
+func bound(ctx context.Context, timeout time.Duration, err *error) (context.Context, func()) {
+	cancel := context.CancelFunc(func() {})
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {
+		canceled(ctx, err)
+		cancel()
+	}
+}
+
+// boundOpen gives a stream the timeout until opened is called, so that it
+// bounds opening the query but not reading the rows, which lasts as long as
+// the caller takes over them. done releases the context like bound.
+func boundOpen(ctx context.Context, timeout time.Duration, err *error) (context.Context, func(), func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	stop := func() bool { return false }
+	if timeout > 0 {
+		stop = time.AfterFunc(timeout, func() { cancel(context.DeadlineExceeded) }).Stop
+	}
+	opened := func() { stop() }
+	return ctx, opened, func() {
+		stop()
+		canceled(ctx, err)
+		cancel(nil)
+	}
 }
 
 type NewRepositoryOptions struct {
 	Dsn string
+	// ReplicaDsns are read replicas of the Postgres database of Dsn, which
+	// serve the reads done outside of a transaction.
+	ReplicaDsns []string
+	// ReadYourWrites keeps the reads of an estate on the primary for this
+	// long after the estate is created or changed, so that they see the write
+	// whatever the lag of the replicas. Zero reads from the replicas right
+	// away.
+	ReadYourWrites time.Duration
+	// QueryTimeout bounds every database call, none when zero.
+	QueryTimeout time.Duration
+
+	// The connection pool of each Postgres database; zero keeps the
+	// database/sql default. SQLite always uses a single connection.
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+}
+
+// NewRepository opens the repository of the DSN: an empty in-memory store for
+// memory://, a SQLite file for sqlite://, a Postgres database otherwise. Only
+// Postgres takes replicas. It does not connect; see WaitReady.
+func NewRepository(opts NewRepositoryOptions) (RepositoryInterface, error) {
+	if len(opts.ReplicaDsns) > 0 && (strings.HasPrefix(opts.Dsn, "memory://") || strings.HasPrefix(opts.Dsn, "sqlite://")) {
+		return nil, errors.New("read replicas need a Postgres database")
+	}
+	if strings.HasPrefix(opts.Dsn, "memory://") {
+		return NewMemoryRepository(), nil
+	}
+	if path, ok := strings.CutPrefix(opts.Dsn, "sqlite://"); ok {
+		repo, err := NewSQLiteRepository(path)
+		if err != nil {
+			return nil, err
+		}
+		repo.QueryTimeout = opts.QueryTimeout
+		return repo, nil
+	}
+
+	db, err := openPostgres(opts.Dsn, opts)
+	if err != nil {
+		return nil, err
+	}
+	repo := &Repository{
+		Db:           db,
+		QueryTimeout: opts.QueryTimeout,
+		written:      newRecentWrites(opts.ReadYourWrites),
+	}
+	for _, dsn := range opts.ReplicaDsns {
+		replica, err := openPostgres(dsn, opts)
+		if err != nil {
+			return nil, err
+		}
+		repo.Replicas = append(repo.Replicas, replica)
+	}
+	return repo, nil
 }
 
-func NewRepository(opts NewRepositoryOptions) *Repository {
-	db, err := sql.Open("postgres", opts.Dsn)
+// openPostgres opens the Postgres database of the DSN with the pool of the
+// options.
+func openPostgres(dsn string, opts NewRepositoryOptions) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
-		panic(err)
+		return nil, err
+	}
+	if opts.MaxOpenConns > 0 {
+		db.SetMaxOpenConns(opts.MaxOpenConns)
+	}
+	if opts.MaxIdleConns > 0 {
+		db.SetMaxIdleConns(opts.MaxIdleConns)
 	}
-	return &Repository{
-		Db: db,
+	if opts.ConnMaxLifetime > 0 {
+		db.SetConnMaxLifetime(opts.ConnMaxLifetime)
+	}
+	if opts.ConnMaxIdleTime > 0 {
+		db.SetConnMaxIdleTime(opts.ConnMaxIdleTime)
+	}
+	return db, nil
+}
+
+// Ping checks that the primary and every replica answer.
+func (r *Repository) Ping(ctx context.Context) error {
+	if err := r.Db.PingContext(ctx); err != nil {
+		return err
+	}
+	for i, replica := range r.Replicas {
+		if err := replica.PingContext(ctx); err != nil {
+			return fmt.Errorf("replica %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// Stats returns the statistics of the connection pool of the primary.
+func (r *Repository) Stats() sql.DBStats {
+	return r.Db.Stats()
+}
+
+// WaitReady pings the database until it answers, so the app does not fail
+// when it starts before the database. It waits backoff after the first
+// failure, twice as long after each next one up to maxBackoff, and gives up
+// with the last error when ctx ends.
+func WaitReady(ctx context.Context, pool Pool, backoff time.Duration, maxBackoff time.Duration) error {
+	for {
+		err := pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database is not ready: %w", err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, maxBackoff)
 	}
 }
@@ -3,13 +3,69 @@
 package repository
 
 import (
+	"context"
+	"database/sql"
+	"errors"
 	"testing"
+	"time"
 
 	_ "github.com/lib/pq"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestNewRepository(t *testing.T) {
-	instance := NewRepository(NewRepositoryOptions{Dsn: "aaaa"})
-	assert.NotNil(t, instance)
+	instance, err := NewRepository(NewRepositoryOptions{Dsn: "aaaa", MaxOpenConns: 7})
+	assert.NoError(t, err)
+	assert.IsType(t, &Repository{}, instance)
+	assert.Equal(t, 7, instance.(Pool).Stats().MaxOpenConnections)
+
+	instance, err = NewRepository(NewRepositoryOptions{Dsn: "aaaa", ReplicaDsns: []string{"bbbb", "cccc"}, MaxOpenConns: 7})
+	assert.NoError(t, err)
+	assert.Len(t, instance.(*Repository).Replicas, 2)
+	assert.Equal(t, 7, instance.(*Repository).Replicas[1].Stats().MaxOpenConnections)
+
+	instance, err = NewRepository(NewRepositoryOptions{Dsn: "memory://"})
+	assert.NoError(t, err)
+	assert.IsType(t, &MemoryRepository{}, instance)
+
+	// SQLite keeps its single connection
+	instance, err = NewRepository(NewRepositoryOptions{Dsn: "sqlite://:memory:", MaxOpenConns: 7})
+	assert.NoError(t, err)
+	assert.IsType(t, &SQLiteRepository{}, instance)
+	assert.Equal(t, 1, instance.(Pool).Stats().MaxOpenConnections)
+
+	_, err = NewRepository(NewRepositoryOptions{Dsn: "sqlite://:memory:", ReplicaDsns: []string{"bbbb"}})
+	assert.Error(t, err)
+}
+
+// flakyPool fails its first pings.
+type flakyPool struct {
+	failures int
+	pings    int
+}
+
+func (p *flakyPool) Ping(ctx context.Context) error {
+	p.pings++
+	if p.pings <= p.failures {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+func (p *flakyPool) Stats() sql.DBStats {
+	return sql.DBStats{}
+}
+
+func TestWaitReady(t *testing.T) {
+	pool := &flakyPool{failures: 3}
+	if err := WaitReady(context.Background(), pool, time.Millisecond, 2*time.Millisecond); err != nil || pool.pings != 4 {
+		t.Errorf("WaitReady() = %v after %d pings, want nil after 4", err, pool.pings)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	pool = &flakyPool{failures: 1000}
+	if err := WaitReady(ctx, pool, time.Millisecond, 5*time.Millisecond); err == nil || pool.pings < 2 {
+		t.Errorf("WaitReady() = %v after %d pings, want an error after retries", err, pool.pings)
+	}
 }
@@ -0,0 +1,897 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+	"github.com/google/uuid"
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteRepository stores everything in a SQLite database file, for the
+// estates running the service on a local box with poor connectivity. Its
+// schema comes from migrations/sqlite, the SQLite version of the Postgres
+// one.
+type SQLiteRepository struct {
+	Db *sql.DB
+	// QueryTimeout bounds every call, none when zero.
+	QueryTimeout time.Duration
+	// tx is the transaction of the unit of work started by WithTx.
+	tx *sql.Tx
+}
+
+// NewSQLiteRepository opens the SQLite database at path, created on first
+// use. The path may carry the query parameters of the driver.
+func NewSQLiteRepository(path string) (*SQLiteRepository, error) {
+	dsn := "file:" + path
+	if strings.Contains(path, "?") {
+		dsn += "&"
+	} else {
+		dsn += "?"
+	}
+	dsn += "_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite has a single writer; one connection, never closed, also keeps
+	// a :memory: database alive
+	db.SetMaxOpenConns(1)
+	return &SQLiteRepository{
+		Db: db,
+	}, nil
+}
+
+// Ping checks that the database file can be used.
+func (r *SQLiteRepository) Ping(ctx context.Context) error {
+	return r.Db.PingContext(ctx)
+}
+
+// Stats returns the statistics of the connection pool.
+func (r *SQLiteRepository) Stats() sql.DBStats {
+	return r.Db.Stats()
+}
+
+func sqliteMigrator(db *sql.DB) *migrator {
+	return &migrator{db: db, dir: "migrations/sqlite", timestamp: "TIMESTAMP"}
+}
+
+// Migrate applies the embedded migrations not applied yet and returns how
+// many it applied. A single process uses the database, so no lock is taken.
+func (r *SQLiteRepository) Migrate(ctx context.Context) (applied int, err error) {
+	return sqliteMigrator(r.Db).up(ctx)
+}
+
+// MigrateDown reverts the last steps applied migrations and returns how many
+// it reverted.
+func (r *SQLiteRepository) MigrateDown(ctx context.Context, steps int) (reverted int, err error) {
+	return sqliteMigrator(r.Db).down(ctx, steps)
+}
+
+// sqliteTime stores times as UTC text with a fixed number of decimals, so
+// they compare in order as text.
+func sqliteTime(t time.Time) string {
+	return t.UTC().Format("2006-01-02T15:04:05.000000000Z")
+}
+
+// sqliteDate stores the day of t, like a Postgres DATE column.
+func sqliteDate(t time.Time) string {
+	return sqliteTime(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+func sqliteNullDate(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return sqliteDate(*t)
+}
+
+func sqliteNullTime(t *time.Time) any {
+	if t == nil {
+		return nil
+	}
+	return sqliteTime(*t)
+}
+
+// encodeTags stores the tags as a JSON array.
+func encodeTags(tags []string) (string, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	encoded, err := json.Marshal(tags)
+	return string(encoded), err
+}
+
+func scanSQLiteEstate(row rowScanner) (estate m.Estate, err error) {
+	var lat, lon, bearing, plotSize sql.NullFloat64
+	var boundary, metadata sql.NullString
+	var code sql.NullString
+	var plantingYear sql.NullInt64
+	var tags string
+	var deletedAt sql.NullTime
+	err = row.Scan(&estate.ID, &estate.Name, &code, &estate.Owner, &estate.Region, &plantingYear, &tags,
+		&estate.Length, &estate.Width, &lat, &lon, &bearing, &plotSize, &boundary, &metadata, &deletedAt)
+	if err != nil {
+		return
+	}
+	estate.Code = code.String
+	estate.PlantingYear = int(plantingYear.Int64)
+	if err = json.Unmarshal([]byte(tags), &estate.Tags); err != nil {
+		return m.Estate{}, err
+	}
+	if len(estate.Tags) == 0 {
+		estate.Tags = nil
+	}
+	estate.DeletedAt = nullTime(deletedAt)
+	if lat.Valid && lon.Valid && bearing.Valid && plotSize.Valid {
+		estate.Geo = &m.GeoAnchor{OriginLat: lat.Float64, OriginLon: lon.Float64, Bearing: bearing.Float64, PlotSize: plotSize.Float64}
+	}
+	if boundary.Valid {
+		if estate.Boundary, err = decodeBoundary([]byte(boundary.String)); err != nil {
+			return m.Estate{}, err
+		}
+	}
+	if metadata.Valid {
+		if estate.Metadata, err = decodeMetadata([]byte(metadata.String)); err != nil {
+			return m.Estate{}, err
+		}
+	}
+	return
+}
+
+func (r *SQLiteRepository) conn() dbtx {
+	if r.tx != nil {
+		return r.tx
+	}
+	return r.Db
+}
+
+// inTx runs fn in the transaction of the unit of work, or in a transaction of
+// its own.
+func (r *SQLiteRepository) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	if r.tx != nil {
+		return fn(r.tx)
+	}
+	return beginTx(ctx, r.Db, fn)
+}
+
+// WithTx runs fn with a repository whose calls share one transaction,
+// committed when fn returns no error and rolled back otherwise. The database
+// has a single connection, so other calls wait for the transaction to end.
+// Nested calls join the transaction already running.
+func (r *SQLiteRepository) WithTx(ctx context.Context, fn func(repo RepositoryInterface) error) (err error) {
+	defer canceled(ctx, &err)
+
+	if r.tx != nil {
+		return fn(r)
+	}
+	return beginTx(ctx, r.Db, func(tx *sql.Tx) error {
+		return fn(&SQLiteRepository{Db: r.Db, QueryTimeout: r.QueryTimeout, tx: tx})
+	})
+}
+
+func sqliteAudit(ctx context.Context, tx *sql.Tx, estateID string, entity string, action string, x sql.NullInt64, y sql.NullInt64) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO audit_log (estate_id, entity, action, x, y, created_at) VALUES($1, $2, $3, $4, $5, $6)`,
+		estateID, entity, action, x, y, sqliteTime(time.Now()))
+	return err
+}
+
+func plot(x int, y int) (sql.NullInt64, sql.NullInt64) {
+	return sql.NullInt64{Int64: int64(x), Valid: true}, sql.NullInt64{Int64: int64(y), Valid: true}
+}
+
+func (r *SQLiteRepository) GetEstateByID(ctx context.Context, id string) (estate m.Estate, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	return scanSQLiteEstate(r.conn().QueryRowContext(ctx, "SELECT "+estateColumns+" FROM estate WHERE id = $1"+notDeleted(ctx), id))
+}
+
+func (r *SQLiteRepository) CreateEstate(ctx context.Context, estate m.Estate) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	var lat, lon, bearing, plotSize sql.NullFloat64
+	if estate.Geo != nil {
+		lat = sql.NullFloat64{Float64: estate.Geo.OriginLat, Valid: true}
+		lon = sql.NullFloat64{Float64: estate.Geo.OriginLon, Valid: true}
+		bearing = sql.NullFloat64{Float64: estate.Geo.Bearing, Valid: true}
+		plotSize = sql.NullFloat64{Float64: estate.Geo.PlotSize, Valid: true}
+	}
+	boundary, err := encodeBoundary(estate.Boundary)
+	if err != nil {
+		return
+	}
+	metadata, err := encodeMetadata(estate.Metadata)
+	if err != nil {
+		return
+	}
+	tags, err := encodeTags(estate.Tags)
+	if err != nil {
+		return
+	}
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO estate (id, name, code, owner, region, planting_year, tags, length, width, origin_lat, origin_lon, bearing, plot_size, boundary, metadata)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)`,
+		id, estate.Name, nullString(estate.Code), estate.Owner, estate.Region, nullInt(estate.PlantingYear), tags,
+		estate.Length, estate.Width, lat, lon, bearing, plotSize, boundary, metadata)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// UpdateEstate stores the descriptive fields, metadata and size of the
+// estate.
+func (r *SQLiteRepository) UpdateEstate(ctx context.Context, estate m.Estate) (err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	metadata, err := encodeMetadata(estate.Metadata)
+	if err != nil {
+		return
+	}
+	tags, err := encodeTags(estate.Tags)
+	if err != nil {
+		return
+	}
+	_, err = r.conn().ExecContext(ctx, `UPDATE estate SET name = $2, code = $3, owner = $4, region = $5, planting_year = $6, tags = $7,
+		metadata = $8, length = $9, width = $10 WHERE id = $1`,
+		estate.ID, estate.Name, nullString(estate.Code), estate.Owner, estate.Region, nullInt(estate.PlantingYear), tags,
+		metadata, estate.Length, estate.Width)
+	return
+}
+
+// DeleteEstate soft-deletes the estate and records it in the audit log. It
+// reports whether the estate was found.
+func (r *SQLiteRepository) DeleteEstate(ctx context.Context, id string) (deleted bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	err = r.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE estate SET deleted_at = $1 WHERE id = $2 AND deleted_at IS NULL", sqliteTime(time.Now()), id)
+		if deleted, err = affected(result, err); err != nil || !deleted {
+			return err
+		}
+		return sqliteAudit(ctx, tx, id, "estate", "delete", sql.NullInt64{}, sql.NullInt64{})
+	})
+	return deleted, err
+}
+
+// RestoreEstate undoes the soft-delete of the estate and records it in the
+// audit log. It reports whether a deleted estate was found.
+func (r *SQLiteRepository) RestoreEstate(ctx context.Context, id string) (restored bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	err = r.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE estate SET deleted_at = NULL WHERE id = $1 AND deleted_at IS NOT NULL", id)
+		if restored, err = affected(result, err); err != nil || !restored {
+			return err
+		}
+		return sqliteAudit(ctx, tx, id, "estate", "restore", sql.NullInt64{}, sql.NullInt64{})
+	})
+	return restored, err
+}
+
+func (r *SQLiteRepository) CreateTree(ctx context.Context, estateID string, tree m.Tree) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO tree (id, estate_id, x, y, height, block_id, variety, planted_at, status)
+		VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		id, estateID, tree.X, tree.Y, tree.Height, nullString(tree.BlockID), tree.Variety, sqliteNullDate(tree.PlantedAt), tree.Status)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLiteRepository) GetTree(ctx context.Context, estateID string) (trees []m.Tree, err error) {
+	err = r.EachTree(ctx, estateID, func(tree m.Tree) error {
+		trees = append(trees, tree)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+// EachTree streams the trees of the estate, ordered by row then column, a
+// page at a time like EachTreeOfEstates.
+func (r *SQLiteRepository) EachTree(ctx context.Context, estateID string, fn func(tree m.Tree) error) (err error) {
+	return r.EachTreeOfEstates(ctx, []string{estateID}, func(_ string, tree m.Tree) error {
+		return fn(tree)
+	})
+}
+
+// GetTreeAt returns the tree of plot (x, y), the live one before those
+// deleted. found is false when the plot has none.
+func (r *SQLiteRepository) GetTreeAt(ctx context.Context, estateID string, x int, y int) (tree m.Tree, found bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, "SELECT "+treeColumns+" FROM tree WHERE estate_id = $1 AND x = $2 AND y = $3"+notDeleted(ctx)+treeAtOrder, estateID, x, y)
+	if err != nil {
+		return
+	}
+	err = eachTree(rows, func(t m.Tree) error {
+		tree, found = t, true
+		return nil
+	})
+	return
+}
+
+// DeleteTree soft-deletes the trees of plot (x, y) and records it in the
+// audit log. It reports whether a tree was found.
+func (r *SQLiteRepository) DeleteTree(ctx context.Context, estateID string, x int, y int) (deleted bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	err = r.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, "UPDATE tree SET deleted_at = $1 WHERE estate_id = $2 AND x = $3 AND y = $4 AND deleted_at IS NULL",
+			sqliteTime(time.Now()), estateID, x, y)
+		if deleted, err = affected(result, err); err != nil || !deleted {
+			return err
+		}
+		px, py := plot(x, y)
+		return sqliteAudit(ctx, tx, estateID, "tree", "delete", px, py)
+	})
+	return deleted, err
+}
+
+// RestoreTree restores the trees of plot (x, y) deleted last and records it
+// in the audit log. It reports whether a deleted tree was found.
+func (r *SQLiteRepository) RestoreTree(ctx context.Context, estateID string, x int, y int) (restored bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	err = r.inTx(ctx, func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, `UPDATE tree SET deleted_at = NULL WHERE estate_id = $1 AND x = $2 AND y = $3
+			AND deleted_at = (SELECT max(deleted_at) FROM tree WHERE estate_id = $1 AND x = $2 AND y = $3)`, estateID, x, y)
+		if restored, err = affected(result, err); err != nil || !restored {
+			return err
+		}
+		px, py := plot(x, y)
+		return sqliteAudit(ctx, tx, estateID, "tree", "restore", px, py)
+	})
+	return restored, err
+}
+
+// sqliteTreePage is how many trees the SQLite streams read at a time.
+var sqliteTreePage = 1000
+
+// treeKey is the last tree of a page of a SQLite stream, which the next page
+// starts after.
+type treeKey struct {
+	estateID string
+	y        int
+	x        int
+	id       string
+}
+
+// pagedTree is a tree of a page of a SQLite stream.
+type pagedTree struct {
+	key  treeKey
+	tree m.Tree
+}
+
+// EachTreeOfEstates streams the trees of the estates, ordered by estate ID
+// then row then column. The trees are read a page at a time, each under the
+// timeout, and fn runs between the pages, so that a slow fn neither runs out
+// of time nor holds the single connection from other requests.
+func (r *SQLiteRepository) EachTreeOfEstates(ctx context.Context, estateIDs []string, fn func(estateID string, tree m.Tree) error) (err error) {
+	ids, err := encodeTags(estateIDs)
+	if err != nil {
+		return
+	}
+	var after *treeKey
+	for {
+		page, err := r.treePage(ctx, ids, after)
+		if err != nil {
+			return err
+		}
+		for _, t := range page {
+			if err := fn(t.key.estateID, t.tree); err != nil {
+				return err
+			}
+		}
+		if len(page) < sqliteTreePage {
+			return nil
+		}
+		after = &page[len(page)-1].key
+	}
+}
+
+// treePage reads the page of trees of the estates, encoded by encodeTags,
+// that comes after the given tree, or the first page.
+func (r *SQLiteRepository) treePage(ctx context.Context, ids string, after *treeKey) (page []pagedTree, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	query := "SELECT estate_id,id," + treeColumns + " FROM tree WHERE estate_id IN (SELECT value FROM json_each($1))" + notDeleted(ctx)
+	args := []any{ids}
+	if after != nil {
+		query += " AND (estate_id, y, x, id) > ($2, $3, $4, $5)"
+		args = append(args, after.estateID, after.y, after.x, after.id)
+	}
+	rows, err := r.conn().QueryContext(ctx, query+" ORDER BY estate_id, y, x, id LIMIT "+strconv.Itoa(sqliteTreePage), args...)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t pagedTree
+		t.tree, err = scanTree(rows, &t.key.estateID, &t.key.id)
+		if err != nil {
+			return nil, err
+		}
+		t.key.y, t.key.x = t.tree.Y, t.tree.X
+		page = append(page, t)
+	}
+	return page, rows.Err()
+}
+
+func (r *SQLiteRepository) ListEstates(ctx context.Context) (estates []m.Estate, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	query := "SELECT id, length, width FROM estate ORDER BY id"
+	if notDeleted(ctx) != "" {
+		query = "SELECT id, length, width FROM estate WHERE deleted_at IS NULL ORDER BY id"
+	}
+	rows, err := r.conn().QueryContext(ctx, query)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var estate m.Estate
+		err = rows.Scan(&estate.ID, &estate.Length, &estate.Width)
+		if err != nil {
+			return nil, err
+		}
+		estates = append(estates, estate)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return estates, nil
+}
+
+// SearchEstates lists the estates whose name or code contains the query and
+// that carry the tag, ordered by name. Empty criteria match every estate.
+func (r *SQLiteRepository) SearchEstates(ctx context.Context, search m.EstateSearch) (estates []m.Estate, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	query := "SELECT " + estateColumns + ` FROM estate
+		WHERE ($1 = '' OR name LIKE '%' || $1 || '%' OR code LIKE '%' || $1 || '%')
+		AND ($2 = '' OR EXISTS (SELECT 1 FROM json_each(estate.tags) WHERE value = $2))` + notDeleted(ctx) + " ORDER BY name, id"
+	rows, err := r.conn().QueryContext(ctx, query, search.Query, search.Tag)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var estate m.Estate
+		estate, err = scanSQLiteEstate(rows)
+		if err != nil {
+			return nil, err
+		}
+		estates = append(estates, estate)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return estates, nil
+}
+
+func (r *SQLiteRepository) CreateDivision(ctx context.Context, division m.Division) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO division (id, estate_id, name, supervisor, x_min, y_min, x_max, y_max) VALUES($1, $2, $3, $4, $5, $6, $7, $8)`,
+		id, division.EstateID, division.Name, division.Supervisor,
+		division.Extent.XMin, division.Extent.YMin, division.Extent.XMax, division.Extent.YMax)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLiteRepository) GetDivisions(ctx context.Context, estateID string) (divisions []m.Division, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, "SELECT id, estate_id, name, supervisor, x_min, y_min, x_max, y_max FROM division WHERE estate_id = $1 ORDER BY name", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var division m.Division
+		err = rows.Scan(&division.ID, &division.EstateID, &division.Name, &division.Supervisor,
+			&division.Extent.XMin, &division.Extent.YMin, &division.Extent.XMax, &division.Extent.YMax)
+		if err != nil {
+			return nil, err
+		}
+		divisions = append(divisions, division)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return divisions, nil
+}
+
+func (r *SQLiteRepository) CreateBlock(ctx context.Context, block m.Block) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO block (id, estate_id, division_id, name, supervisor, x_min, y_min, x_max, y_max) VALUES($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		id, block.EstateID, block.DivisionID, block.Name, block.Supervisor,
+		block.Extent.XMin, block.Extent.YMin, block.Extent.XMax, block.Extent.YMax)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLiteRepository) GetBlocks(ctx context.Context, estateID string) (blocks []m.Block, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, "SELECT id, estate_id, division_id, name, supervisor, x_min, y_min, x_max, y_max FROM block WHERE estate_id = $1 ORDER BY name", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var block m.Block
+		err = rows.Scan(&block.ID, &block.EstateID, &block.DivisionID, &block.Name, &block.Supervisor,
+			&block.Extent.XMin, &block.Extent.YMin, &block.Extent.XMax, &block.Extent.YMax)
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return blocks, nil
+}
+
+// AssignTreesToBlock moves the trees already planted inside the extent of the
+// block into it.
+func (r *SQLiteRepository) AssignTreesToBlock(ctx context.Context, block m.Block) (err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	_, err = r.conn().ExecContext(ctx, `UPDATE tree SET block_id = $1 WHERE estate_id = $2 AND x BETWEEN $3 AND $4 AND y BETWEEN $5 AND $6`,
+		block.ID, block.EstateID, block.Extent.XMin, block.Extent.XMax, block.Extent.YMin, block.Extent.YMax)
+	return
+}
+
+// PurgeDeleted hard-deletes the trees and estates soft-deleted before the
+// given time and records them in the audit log.
+func (r *SQLiteRepository) PurgeDeleted(ctx context.Context, before time.Time) (purged m.PurgeResult, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	err = r.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, `INSERT INTO audit_log (estate_id, entity, action, x, y, created_at)
+			SELECT estate_id, 'tree', 'purge', x, y, $1 FROM tree WHERE deleted_at < $2`, sqliteTime(time.Now()), sqliteTime(before))
+		if err != nil {
+			return err
+		}
+		result, err := tx.ExecContext(ctx, "DELETE FROM tree WHERE deleted_at < $1", sqliteTime(before))
+		if err != nil {
+			return err
+		}
+		trees, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, `INSERT INTO audit_log (estate_id, entity, action, created_at)
+			SELECT id, 'estate', 'purge', $1 FROM estate WHERE deleted_at < $2`, sqliteTime(time.Now()), sqliteTime(before))
+		if err != nil {
+			return err
+		}
+		result, err = tx.ExecContext(ctx, "DELETE FROM estate WHERE deleted_at < $1", sqliteTime(before))
+		if err != nil {
+			return err
+		}
+		estates, err := result.RowsAffected()
+		if err != nil {
+			return err
+		}
+		purged = m.PurgeResult{Estates: int(estates), Trees: int(trees)}
+		return nil
+	})
+	return purged, err
+}
+
+func (r *SQLiteRepository) GetAuditLog(ctx context.Context, estateID string) (entries []m.AuditEntry, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, "SELECT estate_id, entity, action, x, y, created_at FROM audit_log WHERE estate_id = $1 ORDER BY id", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry m.AuditEntry
+		var x, y sql.NullInt64
+		err = rows.Scan(&entry.EstateID, &entry.Entity, &entry.Action, &x, &y, &entry.At)
+		if err != nil {
+			return nil, err
+		}
+		entry.X, entry.Y = int(x.Int64), int(y.Int64)
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *SQLiteRepository) CreateObservation(ctx context.Context, observation m.Observation) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO observation (id, estate_id, x, y, type, severity, observed_at, notes) VALUES($1, $2, $3, $4, $5, $6, $7, $8)`,
+		id, observation.EstateID, observation.X, observation.Y, observation.Type, observation.Severity, sqliteTime(observation.ObservedAt), observation.Notes)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// GetObservations returns the observations of the estate matching the filter,
+// oldest first.
+func (r *SQLiteRepository) GetObservations(ctx context.Context, estateID string, filter m.ObservationFilter) (observations []m.Observation, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, `SELECT id, estate_id, x, y, type, severity, observed_at, notes FROM observation
+		WHERE estate_id = $1 AND ($2 = '' OR type = $2) AND ($3 = 0 OR x = $3) AND ($4 = 0 OR y = $4)
+		AND ($5 IS NULL OR observed_at >= $5) ORDER BY observed_at, id`,
+		estateID, filter.Type, filter.X, filter.Y, sqliteNullTime(filter.Since))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var observation m.Observation
+		err = rows.Scan(&observation.ID, &observation.EstateID, &observation.X, &observation.Y,
+			&observation.Type, &observation.Severity, &observation.ObservedAt, &observation.Notes)
+		if err != nil {
+			return nil, err
+		}
+		observations = append(observations, observation)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return observations, nil
+}
+
+func (r *SQLiteRepository) CreateHarvest(ctx context.Context, harvest m.Harvest) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO harvest (id, estate_id, x, y, harvested_at, bunches, weight) VALUES($1, $2, $3, $4, $5, $6, $7)`,
+		id, harvest.EstateID, harvest.X, harvest.Y, sqliteDate(harvest.HarvestedAt), harvest.Bunches, harvest.Weight)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// GetHarvests returns the harvests of the estate between from and to, both
+// included when set, oldest first.
+func (r *SQLiteRepository) GetHarvests(ctx context.Context, estateID string, from *time.Time, to *time.Time) (harvests []m.Harvest, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, `SELECT id, estate_id, x, y, harvested_at, bunches, weight FROM harvest
+		WHERE estate_id = $1 AND ($2 IS NULL OR harvested_at >= $2) AND ($3 IS NULL OR harvested_at <= $3)
+		ORDER BY harvested_at, y, x`, estateID, sqliteNullDate(from), sqliteNullDate(to))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var harvest m.Harvest
+		err = rows.Scan(&harvest.ID, &harvest.EstateID, &harvest.X, &harvest.Y, &harvest.HarvestedAt, &harvest.Bunches, &harvest.Weight)
+		if err != nil {
+			return nil, err
+		}
+		harvests = append(harvests, harvest)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return harvests, nil
+}
+
+// GetHarvestAt returns the harvest of plot (x, y) on the day. found is false
+// when the plot was not harvested that day.
+func (r *SQLiteRepository) GetHarvestAt(ctx context.Context, estateID string, x int, y int, day time.Time) (harvest m.Harvest, found bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, `SELECT id, estate_id, x, y, harvested_at, bunches, weight FROM harvest
+		WHERE estate_id = $1 AND x = $2 AND y = $3 AND harvested_at = $4`, estateID, x, y, sqliteDate(day))
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	if rows.Next() {
+		err = rows.Scan(&harvest.ID, &harvest.EstateID, &harvest.X, &harvest.Y, &harvest.HarvestedAt, &harvest.Bunches, &harvest.Weight)
+		if err != nil {
+			return m.Harvest{}, false, err
+		}
+		found = true
+	}
+	if err = rows.Err(); err != nil {
+		return m.Harvest{}, false, err
+	}
+	return harvest, found, nil
+}
+
+func (r *SQLiteRepository) CreateHarvestRound(ctx context.Context, round m.HarvestRound) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO harvest_round (id, estate_id, block_id, teams, scheduled_on) VALUES($1, $2, $3, $4, $5)`,
+		id, round.EstateID, nullString(round.BlockID), round.Teams, sqliteDate(round.ScheduledOn))
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// GetHarvestRounds returns the harvest rounds of the estate, earliest first.
+func (r *SQLiteRepository) GetHarvestRounds(ctx context.Context, estateID string) (rounds []m.HarvestRound, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, `SELECT id, estate_id, block_id, teams, scheduled_on, completed_at FROM harvest_round
+		WHERE estate_id = $1 ORDER BY scheduled_on, id`, estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var round m.HarvestRound
+		var blockID sql.NullString
+		var completedAt sql.NullTime
+		err = rows.Scan(&round.ID, &round.EstateID, &blockID, &round.Teams, &round.ScheduledOn, &completedAt)
+		if err != nil {
+			return nil, err
+		}
+		round.BlockID = blockID.String
+		round.CompletedAt = nullTime(completedAt)
+		rounds = append(rounds, round)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// CompleteHarvestRound marks the round as completed now. It reports whether
+// an uncompleted round was found.
+func (r *SQLiteRepository) CompleteHarvestRound(ctx context.Context, estateID string, roundID string) (completed bool, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	result, err := r.conn().ExecContext(ctx, "UPDATE harvest_round SET completed_at = $1 WHERE id = $2 AND estate_id = $3 AND completed_at IS NULL",
+		sqliteTime(time.Now()), roundID, estateID)
+	return affected(result, err)
+}
+
+func (r *SQLiteRepository) CreateFertilizerProgram(ctx context.Context, program m.FertilizerProgram) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO fertilizer_program (id, estate_id, block_id, product, dose, interval_days, start_on) VALUES($1, $2, $3, $4, $5, $6, $7)`,
+		id, program.EstateID, nullString(program.BlockID), program.Product, program.Dose, program.Interval, sqliteDate(program.StartOn))
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *SQLiteRepository) GetFertilizerPrograms(ctx context.Context, estateID string) (programs []m.FertilizerProgram, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, "SELECT id, estate_id, block_id, product, dose, interval_days, start_on FROM fertilizer_program WHERE estate_id = $1 ORDER BY product, id", estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var program m.FertilizerProgram
+		var blockID sql.NullString
+		err = rows.Scan(&program.ID, &program.EstateID, &blockID, &program.Product, &program.Dose, &program.Interval, &program.StartOn)
+		if err != nil {
+			return nil, err
+		}
+		program.BlockID = blockID.String
+		programs = append(programs, program)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return programs, nil
+}
+
+func (r *SQLiteRepository) CreateFertilizerApplication(ctx context.Context, application m.FertilizerApplication) (id string, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	id = uuid.NewString()
+	_, err = r.conn().ExecContext(ctx, `INSERT INTO fertilizer_application (id, program_id, applied_on, trees, quantity) VALUES($1, $2, $3, $4, $5)`,
+		id, application.ProgramID, sqliteDate(application.AppliedOn), application.Trees, application.Quantity)
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// GetFertilizerApplications returns the applications of every program of the
+// estate, oldest first.
+func (r *SQLiteRepository) GetFertilizerApplications(ctx context.Context, estateID string) (applications []m.FertilizerApplication, err error) {
+	ctx, done := bound(ctx, r.QueryTimeout, &err)
+	defer done()
+
+	rows, err := r.conn().QueryContext(ctx, `SELECT a.id, a.program_id, a.applied_on, a.trees, a.quantity FROM fertilizer_application a
+		JOIN fertilizer_program p ON p.id = a.program_id WHERE p.estate_id = $1 ORDER BY a.applied_on, a.id`, estateID)
+	if err != nil {
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var application m.FertilizerApplication
+		err = rows.Scan(&application.ID, &application.ProgramID, &application.AppliedOn, &application.Trees, &application.Quantity)
+		if err != nil {
+			return nil, err
+		}
+		applications = append(applications, application)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+func newTestSQLite(t *testing.T) *SQLiteRepository {
+	t.Helper()
+	r, err := NewSQLiteRepository(filepath.Join(t.TempDir(), "estate.db"))
+	if err != nil {
+		t.Fatalf("NewSQLiteRepository() error = %v", err)
+	}
+	t.Cleanup(func() { r.Db.Close() })
+	if _, err := r.Migrate(context.Background()); err != nil {
+		t.Fatalf("SQLiteRepository.Migrate() error = %v", err)
+	}
+	return r
+}
+
+func TestSQLiteRepository_Migrate(t *testing.T) {
+	r := newTestSQLite(t)
+	ctx := context.Background()
+
+	if applied, err := r.Migrate(ctx); applied != 0 || err != nil {
+		t.Errorf("SQLiteRepository.Migrate() again = %d, %v", applied, err)
+	}
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	r.CreateTree(ctx, id, m.Tree{X: 1, Y: 1, Height: 5, Status: m.TreeHealthy})
+
+	// the trees keep their plots through the rebuilds of the table, and of
+	// the live trees sharing a plot the tallest is kept
+	if reverted, err := r.MigrateDown(ctx, 1); reverted != 1 || err != nil {
+		t.Fatalf("SQLiteRepository.MigrateDown() = %d, %v", reverted, err)
+	}
+	for _, height := range []int{3, 7, 5} {
+		if _, err := r.Db.ExecContext(ctx, `INSERT INTO tree (estate_id, x, y, height) VALUES (?, 2, 1, ?)`, id, height); err != nil {
+			t.Fatalf("inserting a duplicated tree: %v", err)
+		}
+	}
+	if applied, err := r.Migrate(ctx); applied != 1 || err != nil {
+		t.Fatalf("SQLiteRepository.Migrate() = %d, %v", applied, err)
+	}
+	if trees, err := r.GetTree(ctx, id); err != nil || len(trees) != 2 || trees[1].Height != 7 {
+		t.Errorf("SQLiteRepository.GetTree() after migrations = %+v, %v", trees, err)
+	}
+
+	// every migration reverts, down to an empty database, and applies again
+	migrations, _ := sqliteMigrator(nil).migrations()
+	if reverted, err := r.MigrateDown(ctx, len(migrations)+1); reverted != len(migrations) || err != nil {
+		t.Errorf("SQLiteRepository.MigrateDown() = %d, %v, want %d", reverted, err, len(migrations))
+	}
+	if applied, err := r.Migrate(ctx); applied != len(migrations) || err != nil {
+		t.Errorf("SQLiteRepository.Migrate() = %d, %v, want %d", applied, err, len(migrations))
+	}
+}
+
+func TestSQLiteRepository_Records(t *testing.T) {
+	r := newTestSQLite(t)
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	// times compare in order whatever their zone and fraction
+	r.CreateObservation(ctx, m.Observation{EstateID: id, X: 1, Y: 1, Type: "rat", Severity: 1, ObservedAt: day.Add(90 * time.Minute).In(time.FixedZone("WIB", 7*3600))})
+	r.CreateObservation(ctx, m.Observation{EstateID: id, X: 2, Y: 1, Type: "ganoderma", Severity: 3, ObservedAt: day.Add(time.Hour + time.Millisecond)})
+	since := day.Add(time.Hour + time.Second)
+	if got, _ := r.GetObservations(ctx, id, m.ObservationFilter{}); len(got) != 2 || got[0].Type != "ganoderma" {
+		t.Errorf("SQLiteRepository.GetObservations() = %+v, want oldest first", got)
+	}
+	if got, _ := r.GetObservations(ctx, id, m.ObservationFilter{Since: &since}); len(got) != 1 || got[0].Type != "rat" {
+		t.Errorf("SQLiteRepository.GetObservations() since = %+v", got)
+	}
+
+	// harvests are kept by day
+	r.CreateHarvest(ctx, m.Harvest{EstateID: id, X: 1, Y: 1, HarvestedAt: day.Add(15 * time.Hour), Bunches: 1, Weight: 20})
+	if _, err := r.CreateHarvest(ctx, m.Harvest{EstateID: id, X: 1, Y: 1, HarvestedAt: day, Bunches: 1}); err == nil {
+		t.Errorf("SQLiteRepository.CreateHarvest() expected error for second harvest on the day")
+	}
+	if got, _ := r.GetHarvests(ctx, id, &day, &day); len(got) != 1 || !got[0].HarvestedAt.Equal(day) {
+		t.Errorf("SQLiteRepository.GetHarvests() on the day = %+v", got)
+	}
+}
+
+func TestSQLiteRepository_Canceled(t *testing.T) {
+	r := newTestSQLite(t)
+	id, _ := r.CreateEstate(context.Background(), m.Estate{Length: 5, Width: 5})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	var canceled *CanceledError
+	if _, err := r.GetTree(ctx, id); !errors.As(err, &canceled) || canceled.Timeout() {
+		t.Errorf("SQLiteRepository.GetTree() error = %v, want canceled", err)
+	}
+	if _, err := r.DeleteEstate(ctx, id); !errors.As(err, &canceled) {
+		t.Errorf("SQLiteRepository.DeleteEstate() error = %v, want canceled", err)
+	}
+	if _, err := r.GetEstateByID(context.Background(), id); err != nil {
+		t.Errorf("SQLiteRepository.DeleteEstate() deleted the estate: %v", err)
+	}
+}
+
+func TestSQLiteRepository_SlowStream(t *testing.T) {
+	r := newTestSQLite(t)
+	ctx := context.Background()
+	id, _ := r.CreateEstate(ctx, m.Estate{Length: 5, Width: 5})
+	for x := 1; x <= 3; x++ {
+		if _, err := r.CreateTree(ctx, id, m.Tree{X: x, Y: 1, Height: x, Status: m.TreeHealthy}); err != nil {
+			t.Fatalf("SQLiteRepository.CreateTree() error = %v", err)
+		}
+	}
+	defer func(page int) { sqliteTreePage = page }(sqliteTreePage)
+	sqliteTreePage = 2
+	r.QueryTimeout = 10 * time.Millisecond
+
+	// a callback slower than the timeout reads every page, and the
+	// connection is free for other calls between the pages
+	got := []int{}
+	err := r.EachTree(ctx, id, func(tree m.Tree) error {
+		time.Sleep(20 * time.Millisecond)
+		if _, err := r.GetEstateByID(ctx, id); err != nil {
+			return err
+		}
+		got = append(got, tree.X)
+		return nil
+	})
+	if err != nil || !reflect.DeepEqual(got, []int{1, 2, 3}) {
+		t.Errorf("SQLiteRepository.EachTree() = %v, %v, want [1 2 3]", got, err)
+	}
+}
@@ -1,16 +1,144 @@
 // This file contains types that are used in this server (global)
 package types
 
+import "time"
+
+// Tree statuses.
+const (
+	TreeHealthy   = "healthy"
+	TreeDiseased  = "diseased"
+	TreeDead      = "dead"
+	TreeReplanted = "replanted"
+)
+
 type Tree struct {
-	X      int
-	Y      int
-	Height int
+	X        int
+	Y        int
+	Height   int
+	BlockID  string
+	Location *Coordinate
+	// Variety is the clone variety of the palm.
+	Variety string
+	// PlantedAt is the planting date, nil when unknown.
+	PlantedAt *time.Time
+	// Status is one of the Tree* statuses.
+	Status string
+	// DeletedAt is set on soft-deleted trees.
+	DeletedAt *time.Time
 }
 
 type Estate struct {
 	ID     string
-	Length int
-	Width  int
+	Name   string
+	Code   string
+	Owner  string
+	Region string
+	// PlantingYear is zero when unknown.
+	PlantingYear int
+	Tags         []string
+	Length       int
+	Width        int
+	Metadata     map[string]string
+	Geo          *GeoAnchor
+	// Boundary is the polygon, in plot coordinates, enclosing the plantable
+	// part of the estate. A nil boundary means the whole rectangle.
+	Boundary  []Point
+	Divisions []Division
+	// DeletedAt is set on soft-deleted estates.
+	DeletedAt *time.Time
+}
+
+// EstateUpdate holds the changes to apply to an estate. Nil fields are left
+// unchanged.
+type EstateUpdate struct {
+	Name         *string
+	Code         *string
+	Owner        *string
+	Region       *string
+	PlantingYear *int
+	Tags         []string
+	Metadata     map[string]string
+	Length       *int
+	Width        *int
+}
+
+// EstateSearch selects estates whose name or code contains Query and that
+// carry Tag. Empty fields match every estate.
+type EstateSearch struct {
+	Query string
+	Tag   string
+}
+
+// TreeFilter selects trees by their attributes. Empty fields match every
+// tree; the planting date bounds are inclusive.
+type TreeFilter struct {
+	Variety     string
+	Status      string
+	PlantedFrom *time.Time
+	PlantedTo   *time.Time
+}
+
+// Extent is a rectangle of plots, bounds included.
+type Extent struct {
+	XMin int
+	YMin int
+	XMax int
+	YMax int
+}
+
+// Division is a part of an estate managed by a supervisor, itself split into
+// blocks.
+type Division struct {
+	ID         string
+	EstateID   string
+	Name       string
+	Supervisor string
+	Extent     Extent
+	Blocks     []Block
+}
+
+// Block is the smallest managed unit of an estate. Trees planted inside its
+// extent belong to it.
+type Block struct {
+	ID         string
+	EstateID   string
+	DivisionID string
+	Name       string
+	Supervisor string
+	Extent     Extent
+}
+
+// AuditEntry records a delete, restore or purge. X and Y are only set when
+// the entry is about a tree.
+type AuditEntry struct {
+	EstateID string
+	Entity   string
+	Action   string
+	X        int
+	Y        int
+	At       time.Time
+}
+
+// PurgeResult counts the soft-deleted rows removed by a purge.
+type PurgeResult struct {
+	Estates int
+	Trees   int
+}
+
+// GeoAnchor places the estate grid on the earth. The origin is the
+// south-west corner of plot (1,1), the x axis points to Bearing degrees
+// clockwise from true north and the y axis is 90 degrees to its left.
+type GeoAnchor struct {
+	OriginLat float64
+	OriginLon float64
+	Bearing   float64
+	PlotSize  float64
+}
+
+// Coordinate is a WGS84 latitude and longitude in degrees.
+type Coordinate struct {
+	Lat float64
+	Lon float64
 }
 
 type Stats struct {
@@ -19,3 +147,269 @@ type Stats struct {
 	Min    int
 	Median int
 }
+
+type EstateReport struct {
+	EstateID      string
+	Length        int
+	Width         int
+	Count         int
+	Density       float64
+	Max           int
+	Min           int
+	Median        int
+	DroneDistance int
+}
+
+type EstateReportFilter struct {
+	SortBy     string
+	Order      string
+	MinCount   *int
+	MaxCount   *int
+	MinDensity *float64
+	MaxDensity *float64
+}
+
+// PlotRange is a run of consecutive plots on row Y, from XStart to XEnd
+// inclusive.
+type PlotRange struct {
+	Y      int
+	XStart int
+	XEnd   int
+	Length int
+}
+
+type DensityAnalysis struct {
+	Plots       int
+	Planted     int
+	Empty       int
+	Density     float64
+	EmptyRanges []PlotRange
+	LargestGaps []PlotRange
+}
+
+type Point struct {
+	X int
+	Y int
+}
+
+// EstateMap is the estate grid downsampled into Columns x Rows cells, each
+// covering Scale x Scale plots. Heights holds the average tree height of every
+// cell in row-major order, 0 when the cell has no tree. Outside marks, in the
+// same order, the cells without any plot inside the estate boundary; it is nil
+// when the estate has no boundary.
+type EstateMap struct {
+	Columns   int
+	Rows      int
+	Scale     int
+	Heights   []int
+	Outside   []bool
+	DronePath []Point
+}
+
+// Waypoint is a position of the drone above plot (X, Y) at the given
+// altitude.
+type Waypoint struct {
+	X        int
+	Y        int
+	Altitude int
+	Location *Coordinate
+}
+
+// Observation is a pest or disease sighting on the tree at X, Y. Severity
+// goes from 1 (first signs) to 5 (tree lost).
+type Observation struct {
+	ID         string
+	EstateID   string
+	X          int
+	Y          int
+	Type       string
+	Severity   int
+	ObservedAt time.Time
+	Notes      string
+}
+
+// ObservationFilter selects observations of a type, on the tree at X, Y and
+// observed at or after Since. Zero fields match every observation.
+type ObservationFilter struct {
+	Type  string
+	X     int
+	Y     int
+	Since *time.Time
+}
+
+// Harvest records the fresh fruit bunches cut from the tree at X, Y in the
+// harvest round of HarvestedAt. Weight is in kilograms.
+type Harvest struct {
+	ID          string
+	EstateID    string
+	X           int
+	Y           int
+	HarvestedAt time.Time
+	Bunches     int
+	Weight      float64
+}
+
+// Yield report periods.
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// YieldFilter selects the harvests of a block between From and To, both
+// included, and sets the length of the report periods. Empty fields select
+// every harvest of the estate, by month.
+type YieldFilter struct {
+	BlockID string
+	From    *time.Time
+	To      *time.Time
+	Period  string
+}
+
+// YieldPeriod is the yield of the period starting at Start.
+type YieldPeriod struct {
+	Start   time.Time
+	Bunches int
+	Weight  float64
+}
+
+// BlockYield is the yield of a block. BlockID is empty for the trees outside
+// every block.
+type BlockYield struct {
+	BlockID string
+	Bunches int
+	Weight  float64
+}
+
+// HeightYield is the yield of the trees from MinHeight to MaxHeight metres.
+type HeightYield struct {
+	MinHeight int
+	MaxHeight int
+	Trees     int
+	Bunches   int
+	Weight    float64
+}
+
+// YieldReport sums the harvests selected by a YieldFilter. Trees counts the
+// harvested trees and HeightCorrelation is the Pearson correlation between
+// their height and their harvested weight.
+type YieldReport struct {
+	Bunches           int
+	Weight            float64
+	Trees             int
+	Periods           []YieldPeriod
+	Blocks            []BlockYield
+	Heights           []HeightYield
+	HeightCorrelation float64
+}
+
+// HarvestRound is a harvest of the estate, or of one of its blocks when
+// BlockID is set, by Teams harvester teams on ScheduledOn. CompletedAt is set
+// once every team is done.
+type HarvestRound struct {
+	ID          string
+	EstateID    string
+	BlockID     string
+	Teams       int
+	ScheduledOn time.Time
+	CompletedAt *time.Time
+}
+
+// HarvestRoute is the part of a harvest round walked by team Team, numbered
+// from 1: its trees in walking order.
+type HarvestRoute struct {
+	Team  int
+	Trees []Point
+}
+
+// FertilizerProgram applies Dose kilograms of Product to every tree of a
+// block, or of the whole estate when BlockID is empty, every Interval days
+// from StartOn.
+type FertilizerProgram struct {
+	ID       string
+	EstateID string
+	BlockID  string
+	Product  string
+	Dose     float64
+	Interval int
+	StartOn  time.Time
+}
+
+// FertilizerApplication records Quantity kilograms of a program's product
+// applied to Trees trees on AppliedOn.
+type FertilizerApplication struct {
+	ID        string
+	ProgramID string
+	AppliedOn time.Time
+	Trees     int
+	Quantity  float64
+}
+
+// FertilizerDue is an upcoming application of a program. Overdue is set when
+// it should already have been applied.
+type FertilizerDue struct {
+	ProgramID string
+	BlockID   string
+	Product   string
+	DueOn     time.Time
+	Overdue   bool
+	Trees     int
+	Quantity  float64
+}
+
+// Material is the quantity of a product needed, in kilograms.
+type Material struct {
+	Product  string
+	Quantity float64
+}
+
+// FertilizerCalendar lists the applications due in a period, soonest first,
+// and the materials they need.
+type FertilizerCalendar struct {
+	Due       []FertilizerDue
+	Materials []Material
+}
+
+// ReplantingOptions tunes a replanting plan. Trees older than MaxAge years
+// are replanted, and at most MaxShare percent of the trees of the estate are
+// replanted a year from StartYear.
+type ReplantingOptions struct {
+	MaxAge    int
+	MaxShare  float64
+	StartYear int
+}
+
+// ReplantingCandidate is a tree to replant, dead or too old, in the year
+// Year of the plan. Age is in years, 0 when unknown.
+type ReplantingCandidate struct {
+	X       int
+	Y       int
+	BlockID string
+	Status  string
+	Age     int
+	Year    int
+}
+
+// BlockWorkload counts the trees of a block and those of them to replant.
+// BlockID is empty for the trees outside every block.
+type BlockWorkload struct {
+	BlockID    string
+	Trees      int
+	Candidates int
+}
+
+// ReplantingPhase is the number of trees replanted in Year.
+type ReplantingPhase struct {
+	Year  int
+	Trees int
+}
+
+// ReplantingPlan lists the trees to replant, most urgent first, the workload
+// of every block and the trees replanted every year, never more than
+// AnnualLimit.
+type ReplantingPlan struct {
+	Candidates  []ReplantingCandidate
+	Blocks      []BlockWorkload
+	Phases      []ReplantingPhase
+	AnnualLimit int
+}
@@ -0,0 +1,107 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// findEmptyRanges returns the empty plots inside the estate as run-length
+// encoded ranges, row by row, together with the number of plots inside the
+// estate and how many of them are planted. The trees are read one row at a
+// time.
+func findEmptyRanges(trees treeStream, estate m.Estate) (ranges []m.PlotRange, plots int, planted int, err error) {
+	ranges = []m.PlotRange{}
+	// emptyRow adds the ranges of row y, whose planted columns are xs
+	emptyRow := func(y int, xs []int) {
+		for _, span := range rowSpans(estate, y) {
+			plots += span[1] - span[0] + 1
+			start := span[0]
+			for _, x := range xs {
+				if x < start || x > span[1] {
+					continue
+				}
+				if x > start {
+					ranges = append(ranges, m.PlotRange{Y: y, XStart: start, XEnd: x - 1, Length: x - start})
+				}
+				start = x + 1
+			}
+			if start <= span[1] {
+				ranges = append(ranges, m.PlotRange{Y: y, XStart: start, XEnd: span[1], Length: span[1] - start + 1})
+			}
+		}
+	}
+	next := 1
+	emptyTo := func(y int, xs []int) {
+		for ; next <= estate.Width && next < y; next++ {
+			emptyRow(next, nil)
+		}
+		if next == y && y <= estate.Width {
+			emptyRow(y, xs)
+			next++
+		}
+	}
+
+	rowY, xs := 0, []int{}
+	err = trees(func(t m.Tree) error {
+		if t.Y != rowY {
+			emptyTo(rowY, xs)
+			rowY, xs = t.Y, []int{}
+		}
+		// plots with more than one tree follow each other in the stream
+		if len(xs) == 0 || xs[len(xs)-1] != t.X {
+			xs = append(xs, t.X)
+			planted++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	emptyTo(rowY, xs)
+	emptyTo(estate.Width+1, nil)
+	return ranges, plots, planted, nil
+}
+
+// largestGaps returns up to limit of the longest empty ranges, longest first.
+func largestGaps(ranges []m.PlotRange, limit int) []m.PlotRange {
+	gaps := slices.Clone(ranges)
+	slices.SortStableFunc(gaps, func(a, b m.PlotRange) int {
+		return cmp.Compare(b.Length, a.Length)
+	})
+	if len(gaps) > limit {
+		gaps = gaps[:limit]
+	}
+	return gaps
+}
+
+func (u *Usecase) AnalyzeDensity(ctx context.Context, estateID string, gapLimit int) (analysis m.DensityAnalysis, err error) {
+	if gapLimit < 0 {
+		return m.DensityAnalysis{}, errors.New("gap limit must not be negative")
+	}
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.DensityAnalysis{}, notExist("estate")
+	}
+
+	ranges, plots, planted, err := findEmptyRanges(u.repoStream(ctx, estateID), estate)
+	if err != nil {
+		return
+	}
+	return m.DensityAnalysis{
+		Plots:       plots,
+		Planted:     planted,
+		Empty:       plots - planted,
+		Density:     float64(planted) / float64(plots),
+		EmptyRanges: ranges,
+		LargestGaps: largestGaps(ranges, gapLimit),
+	}, nil
+}
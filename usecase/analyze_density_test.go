@@ -0,0 +1,228 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_findEmptyRanges(t *testing.T) {
+	type args struct {
+		trees  []m.Tree
+		estate m.Estate
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantRanges  []m.PlotRange
+		wantPlots   int
+		wantPlanted int
+	}{
+		{
+			name: "all good",
+			args: args{
+				trees: []m.Tree{
+					{X: 4, Y: 1, Height: 4},
+					{X: 2, Y: 1, Height: 5},
+					{X: 1, Y: 3, Height: 3},
+					{X: 5, Y: 3, Height: 3},
+				},
+				estate: m.Estate{Length: 5, Width: 3},
+			},
+			wantRanges: []m.PlotRange{
+				{Y: 1, XStart: 1, XEnd: 1, Length: 1},
+				{Y: 1, XStart: 3, XEnd: 3, Length: 1},
+				{Y: 1, XStart: 5, XEnd: 5, Length: 1},
+				{Y: 2, XStart: 1, XEnd: 5, Length: 5},
+				{Y: 3, XStart: 2, XEnd: 4, Length: 3},
+			},
+			wantPlots:   15,
+			wantPlanted: 4,
+		},
+		{
+			name: "duplicated plot counted once",
+			args: args{
+				trees: []m.Tree{
+					{X: 1, Y: 1, Height: 4},
+					{X: 1, Y: 1, Height: 5},
+					{X: 2, Y: 1, Height: 5},
+				},
+				estate: m.Estate{Length: 2, Width: 1},
+			},
+			wantRanges:  []m.PlotRange{},
+			wantPlots:   2,
+			wantPlanted: 2,
+		},
+		{
+			name: "plots outside boundary are not empty",
+			args: args{
+				trees:  []m.Tree{{X: 2, Y: 2, Height: 4}},
+				estate: m.Estate{Length: 3, Width: 3, Boundary: []m.Point{{X: 2, Y: 1}, {X: 3, Y: 2}, {X: 2, Y: 3}, {X: 1, Y: 2}}},
+			},
+			wantRanges: []m.PlotRange{
+				{Y: 1, XStart: 2, XEnd: 2, Length: 1},
+				{Y: 2, XStart: 1, XEnd: 1, Length: 1},
+				{Y: 2, XStart: 3, XEnd: 3, Length: 1},
+				{Y: 3, XStart: 2, XEnd: 2, Length: 1},
+			},
+			wantPlots:   5,
+			wantPlanted: 1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRanges, gotPlots, gotPlanted, _ := findEmptyRanges(sliceStream(tt.args.trees), tt.args.estate)
+			if !reflect.DeepEqual(gotRanges, tt.wantRanges) {
+				t.Errorf("findEmptyRanges() ranges = %v, want %v", gotRanges, tt.wantRanges)
+			}
+			if gotPlots != tt.wantPlots {
+				t.Errorf("findEmptyRanges() plots = %v, want %v", gotPlots, tt.wantPlots)
+			}
+			if gotPlanted != tt.wantPlanted {
+				t.Errorf("findEmptyRanges() planted = %v, want %v", gotPlanted, tt.wantPlanted)
+			}
+		})
+	}
+}
+
+func TestUsecase_AnalyzeDensity(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	type args struct {
+		ctx      context.Context
+		estateID string
+		gapLimit int
+	}
+	tests := []struct {
+		name         string
+		args         args
+		wantAnalysis m.DensityAnalysis
+		wantErr      bool
+		repo         repository.RepositoryInterface
+		mockCalls    []func() *gomock.Call
+	}{
+		{
+			name: "when all good, return no error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				gapLimit: 2,
+			},
+			wantAnalysis: m.DensityAnalysis{
+				Plots:   10,
+				Planted: 3,
+				Empty:   7,
+				Density: 0.3,
+				EmptyRanges: []m.PlotRange{
+					{Y: 1, XStart: 1, XEnd: 1, Length: 1},
+					{Y: 1, XStart: 5, XEnd: 5, Length: 1},
+					{Y: 2, XStart: 1, XEnd: 5, Length: 5},
+				},
+				LargestGaps: []m.PlotRange{
+					{Y: 2, XStart: 1, XEnd: 5, Length: 5},
+					{Y: 1, XStart: 1, XEnd: 1, Length: 1},
+				},
+			},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{
+						{X: 2, Y: 1, Height: 5},
+						{X: 3, Y: 1, Height: 3},
+						{X: 4, Y: 1, Height: 4}}, nil))
+				},
+			},
+		},
+		{
+			name: "when gap limit is negative, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				gapLimit: -1,
+			},
+			wantAnalysis: m.DensityAnalysis{},
+			wantErr:      true,
+			repo:         mockRepo,
+			mockCalls:    []func() *gomock.Call{},
+		},
+		{
+			name: "when estate not found, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantAnalysis: m.DensityAnalysis{},
+			wantErr:      true,
+			repo:         mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name: "when get estate give error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantAnalysis: m.DensityAnalysis{},
+			wantErr:      true,
+			repo:         mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, errors.New("estate"))
+				},
+			},
+		},
+		{
+			name: "when get tree give error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantAnalysis: m.DensityAnalysis{},
+			wantErr:      true,
+			repo:         mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 1}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{}, errors.New("tree")))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotAnalysis, err := u.AnalyzeDensity(tt.args.ctx, tt.args.estateID, tt.args.gapLimit)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.AnalyzeDensity() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotAnalysis, tt.wantAnalysis) {
+				t.Errorf("Usecase.AnalyzeDensity() = %v, want %v", gotAnalysis, tt.wantAnalysis)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+func validateExtent(extent m.Extent, within m.Extent) error {
+	if extent.XMin > extent.XMax || extent.YMin > extent.YMax {
+		return errors.New("extent is empty")
+	}
+	if !extentContains(within, extent) {
+		return errors.New("extent is outside its parent")
+	}
+	return nil
+}
+
+// extentContains reports whether inner lies entirely inside outer.
+func extentContains(outer m.Extent, inner m.Extent) bool {
+	return inner.XMin >= outer.XMin && inner.XMax <= outer.XMax &&
+		inner.YMin >= outer.YMin && inner.YMax <= outer.YMax
+}
+
+func extentsOverlap(a m.Extent, b m.Extent) bool {
+	return a.XMin <= b.XMax && b.XMin <= a.XMax && a.YMin <= b.YMax && b.YMin <= a.YMax
+}
+
+// blockAt returns the id of the block plot (x, y) belongs to, if any.
+func blockAt(blocks []m.Block, x int, y int) string {
+	for _, b := range blocks {
+		if extentContains(b.Extent, m.Extent{XMin: x, YMin: y, XMax: x, YMax: y}) {
+			return b.ID
+		}
+	}
+	return ""
+}
+
+// clipToBlock returns the part of the estate covered by the block as an estate
+// of its own, with plot (1,1) at the south-west corner of the block, together
+// with the trees of the block moved to the same coordinates. The other trees
+// are dropped as they stream by.
+func clipToBlock(estate m.Estate, trees treeStream, block m.Block) (m.Estate, treeStream) {
+	dx, dy := block.Extent.XMin-1, block.Extent.YMin-1
+	clipped := m.Estate{
+		ID:     estate.ID,
+		Length: block.Extent.XMax - dx,
+		Width:  block.Extent.YMax - dy,
+	}
+	if estate.Boundary != nil {
+		clipped.Boundary = []m.Point{}
+		for _, p := range estate.Boundary {
+			clipped.Boundary = append(clipped.Boundary, m.Point{X: p.X - dx, Y: p.Y - dy})
+		}
+	}
+
+	blockTrees := func(fn func(tree m.Tree) error) error {
+		return trees(func(t m.Tree) error {
+			if t.BlockID != block.ID {
+				return nil
+			}
+			t.X, t.Y = t.X-dx, t.Y-dy
+			return fn(t)
+		})
+	}
+	return clipped, blockTrees
+}
+
+// getBlock loads the estate and the block, checking the block belongs to the
+// estate. Its trees are streamed with clipToBlock.
+func (u *Usecase) getBlock(ctx context.Context, estateID string, blockID string) (estate m.Estate, block m.Block, err error) {
+	estate, err = u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.Estate{}, m.Block{}, notExist("estate")
+	}
+
+	blocks, err := u.Repo.GetBlocks(ctx, estateID)
+	if err != nil {
+		return
+	}
+	for _, b := range blocks {
+		if b.ID == blockID {
+			block = b
+		}
+	}
+	if block.ID == "" {
+		return m.Estate{}, m.Block{}, notExist("block")
+	}
+	return estate, block, nil
+}
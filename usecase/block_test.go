@@ -0,0 +1,98 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_clipToBlock(t *testing.T) {
+	estate := m.Estate{ID: "aaa", Length: 10, Width: 10, Boundary: []m.Point{{X: 1, Y: 1}, {X: 10, Y: 1}, {X: 1, Y: 10}}}
+	trees := []m.Tree{
+		{X: 1, Y: 1, Height: 3},
+		{X: 8, Y: 2, Height: 5, BlockID: "bbb"},
+		{X: 9, Y: 2, Height: 7, BlockID: "bbb"},
+	}
+	block := m.Block{ID: "bbb", Extent: m.Extent{XMin: 8, YMin: 2, XMax: 10, YMax: 3}}
+
+	gotEstate, stream := clipToBlock(estate, sliceStream(trees), block)
+	gotTrees := []m.Tree{}
+	stream(func(tree m.Tree) error {
+		gotTrees = append(gotTrees, tree)
+		return nil
+	})
+	wantEstate := m.Estate{ID: "aaa", Length: 3, Width: 2, Boundary: []m.Point{{X: -6, Y: 0}, {X: 3, Y: 0}, {X: -6, Y: 9}}}
+	wantTrees := []m.Tree{{X: 1, Y: 1, Height: 5, BlockID: "bbb"}, {X: 2, Y: 1, Height: 7, BlockID: "bbb"}}
+	if !reflect.DeepEqual(gotEstate, wantEstate) {
+		t.Errorf("clipToBlock() estate = %v, want %v", gotEstate, wantEstate)
+	}
+	if !reflect.DeepEqual(gotTrees, wantTrees) {
+		t.Errorf("clipToBlock() trees = %v, want %v", gotTrees, wantTrees)
+	}
+	// the boundary still cuts the corner of the block
+	if insideEstate(gotEstate, 3, 2) {
+		t.Errorf("clipToBlock() plot (10,3) should stay outside the boundary")
+	}
+}
+
+func TestUsecase_BlockPlans(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+	expect := func(blocks []m.Block) {
+		mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+		mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(blocks, nil)
+		if len(blocks) > 0 {
+			mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{
+				{X: 1, Y: 1, Height: 9},
+				{X: 5, Y: 3, Height: 4, BlockID: "bbb"},
+				{X: 6, Y: 3, Height: 2, BlockID: "bbb"},
+			}, nil))
+		}
+	}
+	blocks := []m.Block{{ID: "bbb", Extent: m.Extent{XMin: 5, YMin: 3, XMax: 6, YMax: 4}}}
+
+	expect(blocks)
+	stats, err := u.GetBlockStats(context.Background(), "aaa", "bbb", m.TreeFilter{})
+	if want := (m.Stats{Count: 2, Max: 4, Min: 2, Median: 3}); err != nil || stats != want {
+		t.Errorf("Usecase.GetBlockStats() = %v, %v, want %v", stats, err, want)
+	}
+
+	// 3 plots of 10 meters, up to 5, down to 3 and landing
+	expect(blocks)
+	distance, err := u.GetBlockDroneDistance(context.Background(), "aaa", "bbb")
+	if want := 30 + 5 + 2 + 3; err != nil || distance != want {
+		t.Errorf("Usecase.GetBlockDroneDistance() = %v, %v, want %v", distance, err, want)
+	}
+
+	expect(blocks)
+	waypoints, err := u.GetBlockDroneWaypoints(context.Background(), "aaa", "bbb", false)
+	wantWaypoints := []m.Waypoint{
+		{X: 5, Y: 3, Altitude: 0},
+		{X: 5, Y: 3, Altitude: 5},
+		{X: 6, Y: 3, Altitude: 5},
+		{X: 6, Y: 3, Altitude: 3},
+		{X: 5, Y: 4, Altitude: 3},
+		{X: 6, Y: 4, Altitude: 3},
+		{X: 6, Y: 4, Altitude: 0},
+	}
+	if err != nil || !reflect.DeepEqual(waypoints, wantWaypoints) {
+		t.Errorf("Usecase.GetBlockDroneWaypoints() = %v, %v, want %v", waypoints, err, wantWaypoints)
+	}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+	mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(blocks, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, errors.New("tree")))
+	if _, err := u.GetBlockDroneDistance(context.Background(), "aaa", "bbb"); err == nil {
+		t.Errorf("Usecase.GetBlockDroneDistance() expected error when streaming trees fails")
+	}
+
+	expect(nil)
+	if _, err := u.GetBlockStats(context.Background(), "aaa", "bbb", m.TreeFilter{}); err == nil {
+		t.Errorf("Usecase.GetBlockStats() expected error for unknown block")
+	}
+}
@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"errors"
+	"math"
+	"slices"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+const boundaryEpsilon = 1e-9
+
+func validateBoundary(boundary []m.Point, maxLength int, maxWidth int) error {
+	if boundary == nil {
+		return nil
+	}
+	if len(boundary) < 3 {
+		return errors.New("boundary needs at least 3 points")
+	}
+	for _, p := range boundary {
+		if p.X < 1 || p.X > maxLength || p.Y < 1 || p.Y > maxWidth {
+			return errors.New("boundary is outside estate")
+		}
+	}
+	return nil
+}
+
+// rowSpans returns the runs of plots of row y that are inside the estate, from
+// west to east. A plot is inside when its centre is inside or on the edge of
+// the boundary polygon; without a boundary the whole row is inside.
+func rowSpans(estate m.Estate, y int) [][2]int {
+	if y < 1 || y > estate.Width {
+		return nil
+	}
+	if estate.Boundary == nil {
+		return [][2]int{{1, estate.Length}}
+	}
+
+	row := float64(y)
+	intervals := [][2]float64{}
+	crossings := []float64{}
+	for i := range estate.Boundary {
+		a, b := estate.Boundary[i], estate.Boundary[(i+1)%len(estate.Boundary)]
+		ax, ay, bx, by := float64(a.X), float64(a.Y), float64(b.X), float64(b.Y)
+
+		if ay == by {
+			// horizontal edges lying on the row are part of the boundary
+			if ay == row {
+				intervals = append(intervals, [2]float64{math.Min(ax, bx), math.Max(ax, bx)})
+			}
+			continue
+		}
+		if row < math.Min(ay, by) || row > math.Max(ay, by) {
+			continue
+		}
+
+		x := ax + (row-ay)*(bx-ax)/(by-ay)
+		intervals = append(intervals, [2]float64{x, x})
+		// count each edge once at its lower end so vertices are not counted twice
+		if (ay <= row && row < by) || (by <= row && row < ay) {
+			crossings = append(crossings, x)
+		}
+	}
+
+	slices.Sort(crossings)
+	for i := 0; i+1 < len(crossings); i += 2 {
+		intervals = append(intervals, [2]float64{crossings[i], crossings[i+1]})
+	}
+
+	spans := [][2]int{}
+	for _, in := range intervals {
+		from := max(1, int(math.Ceil(in[0]-boundaryEpsilon)))
+		to := min(estate.Length, int(math.Floor(in[1]+boundaryEpsilon)))
+		if from <= to {
+			spans = append(spans, [2]int{from, to})
+		}
+	}
+	slices.SortFunc(spans, func(a, b [2]int) int { return a[0] - b[0] })
+
+	// merge overlapping and adjacent spans
+	merged := [][2]int{}
+	for _, s := range spans {
+		if len(merged) > 0 && s[0] <= merged[len(merged)-1][1]+1 {
+			merged[len(merged)-1][1] = max(merged[len(merged)-1][1], s[1])
+			continue
+		}
+		merged = append(merged, s)
+	}
+	return merged
+}
+
+// insideEstate reports whether plot (x, y) is inside the estate.
+func insideEstate(estate m.Estate, x int, y int) bool {
+	for _, s := range rowSpans(estate, y) {
+		if x >= s[0] && x <= s[1] {
+			return true
+		}
+	}
+	return false
+}
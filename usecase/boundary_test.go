@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"reflect"
+	"testing"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+func Test_rowSpans(t *testing.T) {
+	triangle := m.Estate{Length: 5, Width: 5, Boundary: []m.Point{{X: 1, Y: 1}, {X: 5, Y: 1}, {X: 3, Y: 5}}}
+	notch := m.Estate{Length: 5, Width: 3, Boundary: []m.Point{{X: 1, Y: 1}, {X: 5, Y: 1}, {X: 5, Y: 3}, {X: 4, Y: 3}, {X: 3, Y: 2}, {X: 2, Y: 3}, {X: 1, Y: 3}}}
+
+	tests := []struct {
+		name   string
+		estate m.Estate
+		y      int
+		want   [][2]int
+	}{
+		{
+			name:   "without boundary, the whole row",
+			estate: m.Estate{Length: 5, Width: 5},
+			y:      3,
+			want:   [][2]int{{1, 5}},
+		},
+		{
+			name:   "row outside estate",
+			estate: m.Estate{Length: 5, Width: 5},
+			y:      6,
+			want:   nil,
+		},
+		{
+			name:   "horizontal edge of the boundary",
+			estate: triangle,
+			y:      1,
+			want:   [][2]int{{1, 5}},
+		},
+		{
+			name:   "plots on the slanted edges",
+			estate: triangle,
+			y:      3,
+			want:   [][2]int{{2, 4}},
+		},
+		{
+			name:   "apex of the boundary",
+			estate: triangle,
+			y:      5,
+			want:   [][2]int{{3, 3}},
+		},
+		{
+			name:   "concave boundary splits the row",
+			estate: notch,
+			y:      3,
+			want:   [][2]int{{1, 2}, {4, 5}},
+		},
+		{
+			name:   "concave vertex touching the row",
+			estate: notch,
+			y:      2,
+			want:   [][2]int{{1, 5}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rowSpans(tt.estate, tt.y); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("rowSpans() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_insideEstate(t *testing.T) {
+	estate := m.Estate{Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 4, Y: 1}, {X: 1, Y: 4}}}
+	if !insideEstate(estate, 2, 3) {
+		t.Errorf("insideEstate() = false, want true")
+	}
+	if insideEstate(estate, 3, 3) {
+		t.Errorf("insideEstate() = true, want false")
+	}
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// CreateBlock creates a block inside a division and moves the trees already
+// planted inside its extent into it.
+func (u *Usecase) CreateBlock(ctx context.Context, block m.Block) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.createBlock(ctx, block)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) createBlock(ctx context.Context, block m.Block) (id string, err error) {
+	if block.Name == "" || block.Supervisor == "" {
+		return "", errors.New("block needs a name and a supervisor")
+	}
+
+	estate, err := u.Repo.GetEstateByID(ctx, block.EstateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return "", notExist("estate")
+	}
+
+	divisions, err := u.Repo.GetDivisions(ctx, block.EstateID)
+	if err != nil {
+		return
+	}
+	var division m.Division
+	for _, d := range divisions {
+		if d.ID == block.DivisionID {
+			division = d
+		}
+	}
+	if division.ID == "" {
+		return "", notExist("division")
+	}
+	if err = validateExtent(block.Extent, division.Extent); err != nil {
+		return "", err
+	}
+
+	blocks, err := u.Repo.GetBlocks(ctx, block.EstateID)
+	if err != nil {
+		return
+	}
+	for _, b := range blocks {
+		if extentsOverlap(b.Extent, block.Extent) {
+			return "", errors.New("block overlaps another block")
+		}
+	}
+
+	id, err = u.Repo.CreateBlock(ctx, block)
+	if err != nil {
+		return "", err
+	}
+	block.ID = id
+	if err = u.Repo.AssignTreesToBlock(ctx, block); err != nil {
+		return "", err
+	}
+	return id, nil
+}
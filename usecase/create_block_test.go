@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_CreateBlock(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	divisions := []m.Division{{ID: "ddd", EstateID: "aaa", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 10, YMax: 5}}}
+	block := m.Block{EstateID: "aaa", DivisionID: "ddd", Name: "A1", Supervisor: "siti", Extent: m.Extent{XMin: 6, YMin: 1, XMax: 10, YMax: 5}}
+	type args struct {
+		ctx   context.Context
+		block m.Block
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantId    string
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when all good, assign trees and return id",
+			args:    args{ctx: context.Background(), block: block},
+			wantId:  "bbb",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return(divisions, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "ccc", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 5, YMax: 5}}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateBlock(gomock.Any(), block).Return("bbb", nil)
+				},
+				func() *gomock.Call {
+					created := block
+					created.ID = "bbb"
+					return mockRepo.EXPECT().AssignTreesToBlock(gomock.Any(), created).Return(nil)
+				},
+			},
+		},
+		{
+			name:    "when division not found, return error",
+			args:    args{ctx: context.Background(), block: m.Block{EstateID: "aaa", DivisionID: "zzz", Name: "A1", Supervisor: "siti", Extent: block.Extent}},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return(divisions, nil)
+				},
+			},
+		},
+		{
+			name:    "when block outside its division, return error",
+			args:    args{ctx: context.Background(), block: m.Block{EstateID: "aaa", DivisionID: "ddd", Name: "A1", Supervisor: "siti", Extent: m.Extent{XMin: 6, YMin: 1, XMax: 10, YMax: 6}}},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return(divisions, nil)
+				},
+			},
+		},
+		{
+			name:    "when block overlaps another, return error",
+			args:    args{ctx: context.Background(), block: block},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return(divisions, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "ccc", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 6, YMax: 5}}}, nil)
+				},
+			},
+		},
+		{
+			name:    "when assigning trees return error, return error",
+			args:    args{ctx: context.Background(), block: block},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return(divisions, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateBlock(gomock.Any(), block).Return("bbb", nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().AssignTreesToBlock(gomock.Any(), gomock.Any()).Return(errors.New("assign"))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotId, err := u.CreateBlock(tt.args.ctx, tt.args.block)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateBlock() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("Usecase.CreateBlock() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
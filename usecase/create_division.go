@@ -0,0 +1,48 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+func (u *Usecase) CreateDivision(ctx context.Context, division m.Division) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.createDivision(ctx, division)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) createDivision(ctx context.Context, division m.Division) (id string, err error) {
+	if division.Name == "" || division.Supervisor == "" {
+		return "", errors.New("division needs a name and a supervisor")
+	}
+
+	estate, err := u.Repo.GetEstateByID(ctx, division.EstateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return "", notExist("estate")
+	}
+	if err = validateExtent(division.Extent, m.Extent{XMin: 1, YMin: 1, XMax: estate.Length, YMax: estate.Width}); err != nil {
+		return "", err
+	}
+
+	divisions, err := u.Repo.GetDivisions(ctx, division.EstateID)
+	if err != nil {
+		return
+	}
+	for _, d := range divisions {
+		if extentsOverlap(d.Extent, division.Extent) {
+			return "", errors.New("division overlaps another division")
+		}
+	}
+
+	return u.Repo.CreateDivision(ctx, division)
+}
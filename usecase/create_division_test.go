@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_CreateDivision(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	division := m.Division{EstateID: "aaa", Name: "north", Supervisor: "budi", Extent: m.Extent{XMin: 1, YMin: 6, XMax: 10, YMax: 10}}
+	type args struct {
+		ctx      context.Context
+		division m.Division
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantId    string
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when all good, return id and no error",
+			args:    args{ctx: context.Background(), division: division},
+			wantId:  "ddd",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return([]m.Division{{ID: "south", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 10, YMax: 5}}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateDivision(gomock.Any(), division).Return("ddd", nil)
+				},
+			},
+		},
+		{
+			name:    "when supervisor missing, return error",
+			args:    args{ctx: context.Background(), division: m.Division{EstateID: "aaa", Name: "north", Extent: division.Extent}},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+		},
+		{
+			name:    "when estate not found, return error",
+			args:    args{ctx: context.Background(), division: division},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name:    "when extent outside estate, return error",
+			args:    args{ctx: context.Background(), division: division},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 8}, nil)
+				},
+			},
+		},
+		{
+			name:    "when division overlaps another, return error",
+			args:    args{ctx: context.Background(), division: division},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return([]m.Division{{ID: "south", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 10, YMax: 6}}}, nil)
+				},
+			},
+		},
+		{
+			name:    "when get divisions return error, return error",
+			args:    args{ctx: context.Background(), division: division},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return(nil, errors.New("divisions"))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotId, err := u.CreateDivision(tt.args.ctx, tt.args.division)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateDivision() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("Usecase.CreateDivision() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
@@ -3,14 +3,70 @@ package usecase
 import (
 	"context"
 	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
 )
 
-func (u *Usecase) CreateEstate(ctx context.Context, length int, width int) (id string, err error) {
+func validateGeoAnchor(geo *m.GeoAnchor) error {
+	if geo == nil {
+		return nil
+	}
+	if geo.OriginLat < -90 || geo.OriginLat > 90 {
+		return errors.New("origin latitude is not in range")
+	}
+	if geo.OriginLon < -180 || geo.OriginLon > 180 {
+		return errors.New("origin longitude is not in range")
+	}
+	if geo.Bearing < 0 || geo.Bearing >= 360 {
+		return errors.New("bearing is not in range")
+	}
+	if geo.PlotSize <= 0 {
+		return errors.New("plot size must be positive")
+	}
+	return nil
+}
+
+func validateSize(length int, width int) error {
 	if length < 1 || length >= 50000 {
-		return "", errors.New("length limit exceeded")
+		return errors.New("length limit exceeded")
 	}
 	if width < 1 || width >= 50000 {
-		return "", errors.New("width limit exceeded")
+		return errors.New("width limit exceeded")
+	}
+	return nil
+}
+
+// validateDetails checks the descriptive fields of the estate.
+func validateDetails(estate m.Estate) error {
+	if estate.PlantingYear != 0 && (estate.PlantingYear < 1900 || estate.PlantingYear > time.Now().Year()) {
+		return errors.New("planting year is not in range")
+	}
+	for i, tag := range estate.Tags {
+		if strings.TrimSpace(tag) == "" {
+			return errors.New("tag cannot be empty")
+		}
+		if slices.Contains(estate.Tags[:i], tag) {
+			return errors.New("tag is duplicated")
+		}
+	}
+	return nil
+}
+
+func (u *Usecase) CreateEstate(ctx context.Context, estate m.Estate) (id string, err error) {
+	if err = validateSize(estate.Length, estate.Width); err != nil {
+		return "", err
+	}
+	if err = validateDetails(estate); err != nil {
+		return "", err
+	}
+	if err = validateGeoAnchor(estate.Geo); err != nil {
+		return "", err
+	}
+	if err = validateBoundary(estate.Boundary, estate.Length, estate.Width); err != nil {
+		return "", err
 	}
-	return u.Repo.CreateEstate(ctx, length, width)
+	return u.Repo.CreateEstate(ctx, estate)
 }
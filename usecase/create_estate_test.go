@@ -7,6 +7,7 @@ import (
 
 	"github.com/SawitProRecruitment/UserService/repository"
 	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
 	"go.uber.org/mock/gomock"
 )
 
@@ -14,8 +15,7 @@ func TestUsecase_CreateEstate(t *testing.T) {
 	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
 	type args struct {
 		ctx    context.Context
-		length int
-		width  int
+		estate m.Estate
 	}
 	tests := []struct {
 		name      string
@@ -30,14 +30,13 @@ func TestUsecase_CreateEstate(t *testing.T) {
 			repo: mockRepo,
 			args: args{
 				ctx:    context.Background(),
-				length: 2,
-				width:  2,
+				estate: m.Estate{Length: 2, Width: 2},
 			},
 			wantId:  "aabb",
 			wantErr: false,
 			mockCalls: []func() *gomock.Call{
 				func() *gomock.Call {
-					return mockRepo.EXPECT().CreateEstate(gomock.Any(), 2, 2).Return("aabb", nil)
+					return mockRepo.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 2, Width: 2}).Return("aabb", nil)
 				},
 			},
 		},
@@ -46,14 +45,13 @@ func TestUsecase_CreateEstate(t *testing.T) {
 			repo: mockRepo,
 			args: args{
 				ctx:    context.Background(),
-				length: 2,
-				width:  2,
+				estate: m.Estate{Length: 2, Width: 2},
 			},
 			wantId:  "",
 			wantErr: true,
 			mockCalls: []func() *gomock.Call{
 				func() *gomock.Call {
-					return mockRepo.EXPECT().CreateEstate(gomock.Any(), 2, 2).Return("", errors.New("db"))
+					return mockRepo.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 2, Width: 2}).Return("", errors.New("db"))
 				},
 			},
 		},
@@ -62,8 +60,7 @@ func TestUsecase_CreateEstate(t *testing.T) {
 			repo: mockRepo,
 			args: args{
 				ctx:    context.Background(),
-				length: -2,
-				width:  2,
+				estate: m.Estate{Length: -2, Width: 2},
 			},
 			wantId:    "",
 			wantErr:   true,
@@ -74,8 +71,151 @@ func TestUsecase_CreateEstate(t *testing.T) {
 			repo: mockRepo,
 			args: args{
 				ctx:    context.Background(),
-				length: 2,
-				width:  -2,
+				estate: m.Estate{Length: 2, Width: -2},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when estate has details, return no error and id",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Name: "Sungai Lilin", Code: "SL-01", Owner: "PT Sawit", Region: "Riau", PlantingYear: 2015, Tags: []string{"young", "flat"}, Length: 2, Width: 2},
+			},
+			wantId:  "aabb",
+			wantErr: false,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateEstate(gomock.Any(), m.Estate{Name: "Sungai Lilin", Code: "SL-01", Owner: "PT Sawit", Region: "Riau", PlantingYear: 2015, Tags: []string{"young", "flat"}, Length: 2, Width: 2}).Return("aabb", nil)
+				},
+			},
+		},
+		{
+			name: "when planting year not suitable, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{PlantingYear: 1850, Length: 2, Width: 2},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when tag is empty, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Tags: []string{"young", " "}, Length: 2, Width: 2},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when tag is duplicated, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Tags: []string{"young", "young"}, Length: 2, Width: 2},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when estate is georeferenced, return no error and id",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: 101.5, Bearing: 90, PlotSize: 9}},
+			},
+			wantId:  "aabb",
+			wantErr: false,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: 101.5, Bearing: 90, PlotSize: 9}}).Return("aabb", nil)
+				},
+			},
+		},
+		{
+			name: "when latitude not suitable, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 91, OriginLon: 101.5, Bearing: 90, PlotSize: 9}},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when longitude not suitable, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: -181, Bearing: 90, PlotSize: 9}},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when bearing not suitable, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: 101.5, Bearing: 360, PlotSize: 9}},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when plot size not suitable, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 2, Width: 2, Geo: &m.GeoAnchor{OriginLat: 1.5, OriginLon: 101.5, Bearing: 90, PlotSize: 0}},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when estate has boundary, return no error and id",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 4, Y: 1}, {X: 1, Y: 4}}},
+			},
+			wantId:  "aabb",
+			wantErr: false,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateEstate(gomock.Any(), m.Estate{Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 4, Y: 1}, {X: 1, Y: 4}}}).Return("aabb", nil)
+				},
+			},
+		},
+		{
+			name: "when boundary has less than 3 points, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 4, Y: 1}}},
+			},
+			wantId:    "",
+			wantErr:   true,
+			mockCalls: []func() *gomock.Call{},
+		},
+		{
+			name: "when boundary outside estate, return error",
+			repo: mockRepo,
+			args: args{
+				ctx:    context.Background(),
+				estate: m.Estate{Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 5, Y: 1}, {X: 1, Y: 4}}},
 			},
 			wantId:    "",
 			wantErr:   true,
@@ -94,7 +234,7 @@ func TestUsecase_CreateEstate(t *testing.T) {
 				Repo: tt.repo,
 			}
 
-			gotId, err := u.CreateEstate(tt.args.ctx, tt.args.length, tt.args.width)
+			gotId, err := u.CreateEstate(tt.args.ctx, tt.args.estate)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Usecase.CreateEstate() error = %v, wantErr %v", err, tt.wantErr)
 				return
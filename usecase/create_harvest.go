@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// dateOf returns the calendar day of t, as stored in DATE columns.
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// CreateHarvest records the bunches cut from an existing tree in the harvest
+// round of the day, today when not given. A tree is harvested at most once a
+// day.
+func (u *Usecase) CreateHarvest(ctx context.Context, harvest m.Harvest) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.createHarvest(ctx, harvest)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) createHarvest(ctx context.Context, harvest m.Harvest) (id string, err error) {
+	if harvest.Bunches < 0 || harvest.Weight < 0 {
+		return "", errors.New("harvest cannot be negative")
+	}
+	if (harvest.Bunches == 0) != (harvest.Weight == 0) {
+		return "", errors.New("harvest needs both bunches and weight")
+	}
+	if harvest.HarvestedAt.IsZero() {
+		harvest.HarvestedAt = time.Now()
+	}
+	if harvest.HarvestedAt.After(time.Now()) {
+		return "", errors.New("harvest is in the future")
+	}
+	day := dateOf(harvest.HarvestedAt)
+	harvest.HarvestedAt = day
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, harvest.EstateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return "", notExist("estate")
+	}
+
+	_, found, err := u.Repo.GetTreeAt(ctx, harvest.EstateID, harvest.X, harvest.Y)
+	if err != nil {
+		return
+	}
+	if !found {
+		return "", notExist("tree")
+	}
+
+	_, harvested, err := u.Repo.GetHarvestAt(ctx, harvest.EstateID, harvest.X, harvest.Y, day)
+	if err != nil {
+		return
+	}
+	if harvested {
+		return "", errors.New("tree is already harvested on this day")
+	}
+
+	return u.Repo.CreateHarvest(ctx, harvest)
+}
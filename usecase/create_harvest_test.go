@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_CreateHarvest(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	harvest := m.Harvest{EstateID: "aaa", X: 2, Y: 3, HarvestedAt: day.Add(9 * time.Hour), Bunches: 2, Weight: 41.5}
+	stored := m.Harvest{EstateID: "aaa", X: 2, Y: 3, HarvestedAt: day, Bunches: 2, Weight: 41.5}
+	tests := []struct {
+		name      string
+		harvest   m.Harvest
+		wantId    string
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when all good, store the harvest on its day",
+			harvest: harvest,
+			wantId:  "hhh",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{X: 2, Y: 3, Height: 5}, true, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetHarvestAt(gomock.Any(), "aaa", 2, 3, day).Return(m.Harvest{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateHarvest(gomock.Any(), stored).Return("hhh", nil)
+				},
+			},
+		},
+		{
+			name:    "when tree already harvested that day, return error",
+			harvest: harvest,
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{X: 2, Y: 3, Height: 5}, true, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetHarvestAt(gomock.Any(), "aaa", 2, 3, day).Return(m.Harvest{X: 2, Y: 3, HarvestedAt: day}, true, nil)
+				},
+			},
+		},
+		{
+			name:    "when plot has no tree, return error",
+			harvest: harvest,
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{}, false, nil)
+				},
+			},
+		},
+		{
+			name:    "when get estate return error, return error",
+			harvest: harvest,
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, errors.New("estate"))
+				},
+			},
+		},
+		{
+			name:    "when bunches are negative, return error",
+			harvest: m.Harvest{EstateID: "aaa", X: 2, Y: 3, Bunches: -1, Weight: 10},
+			wantErr: true,
+			repo:    mockRepo,
+		},
+		{
+			name:    "when weight is missing, return error",
+			harvest: m.Harvest{EstateID: "aaa", X: 2, Y: 3, Bunches: 2},
+			wantErr: true,
+			repo:    mockRepo,
+		},
+		{
+			name:    "when harvested in the future, return error",
+			harvest: m.Harvest{EstateID: "aaa", X: 2, Y: 3, Bunches: 2, Weight: 40, HarvestedAt: time.Now().AddDate(0, 0, 2)},
+			wantErr: true,
+			repo:    mockRepo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, call := range tt.mockCalls {
+				call()
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotId, err := u.CreateHarvest(context.Background(), tt.harvest)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateHarvest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("Usecase.CreateHarvest() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
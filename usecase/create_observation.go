@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// CreateObservation logs a pest or disease sighting on an existing tree. The
+// observation time defaults to now.
+func (u *Usecase) CreateObservation(ctx context.Context, observation m.Observation) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.createObservation(ctx, observation)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) createObservation(ctx context.Context, observation m.Observation) (id string, err error) {
+	if observation.Type == "" {
+		return "", errors.New("observation needs a type")
+	}
+	if observation.Severity < 1 || observation.Severity > 5 {
+		return "", errors.New("observation's severity is not in range")
+	}
+	if observation.ObservedAt.IsZero() {
+		observation.ObservedAt = time.Now()
+	}
+	if observation.ObservedAt.After(time.Now()) {
+		return "", errors.New("observation is in the future")
+	}
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, observation.EstateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return "", notExist("estate")
+	}
+
+	_, found, err := u.Repo.GetTreeAt(ctx, observation.EstateID, observation.X, observation.Y)
+	if err != nil {
+		return
+	}
+	if !found {
+		return "", notExist("tree")
+	}
+	return u.Repo.CreateObservation(ctx, observation)
+}
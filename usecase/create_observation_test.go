@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_CreateObservation(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	observation := m.Observation{EstateID: "aaa", X: 2, Y: 3, Type: "ganoderma", Severity: 2, ObservedAt: at}
+	tests := []struct {
+		name        string
+		observation m.Observation
+		wantId      string
+		wantErr     bool
+		repo        repository.RepositoryInterface
+		mockCalls   []func() *gomock.Call
+	}{
+		{
+			name:        "when all good, return id",
+			observation: observation,
+			wantId:      "ooo",
+			wantErr:     false,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{X: 2, Y: 3, Height: 5}, true, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateObservation(gomock.Any(), observation).Return("ooo", nil)
+				},
+			},
+		},
+		{
+			name:        "when plot has no tree, return error",
+			observation: observation,
+			wantErr:     true,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{}, false, nil)
+				},
+			},
+		},
+		{
+			name:        "when get tree return error, return error",
+			observation: observation,
+			wantErr:     true,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{}, false, errors.New("tree"))
+				},
+			},
+		},
+		{
+			name:        "when estate not found, return error",
+			observation: observation,
+			wantErr:     true,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name:        "when type is missing, return error",
+			observation: m.Observation{EstateID: "aaa", X: 2, Y: 3, Severity: 2},
+			wantErr:     true,
+			repo:        mockRepo,
+		},
+		{
+			name:        "when severity is out of range, return error",
+			observation: m.Observation{EstateID: "aaa", X: 2, Y: 3, Type: "ganoderma", Severity: 6},
+			wantErr:     true,
+			repo:        mockRepo,
+		},
+		{
+			name:        "when observed in the future, return error",
+			observation: m.Observation{EstateID: "aaa", X: 2, Y: 3, Type: "ganoderma", Severity: 2, ObservedAt: time.Now().Add(time.Hour)},
+			wantErr:     true,
+			repo:        mockRepo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, call := range tt.mockCalls {
+				call()
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotId, err := u.CreateObservation(context.Background(), tt.observation)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateObservation() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("Usecase.CreateObservation() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
+
+func TestUsecase_CreateObservation_DefaultTime(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	u := &Usecase{Repo: mockRepo}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{X: 2, Y: 3, Height: 5}, true, nil)
+	mockRepo.EXPECT().CreateObservation(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, o m.Observation) (string, error) {
+		if o.ObservedAt.IsZero() {
+			t.Errorf("Usecase.CreateObservation() stored no observation time")
+		}
+		return "ooo", nil
+	})
+
+	if _, err := u.CreateObservation(context.Background(), m.Observation{EstateID: "aaa", X: 2, Y: 3, Type: "ganoderma", Severity: 1}); err != nil {
+		t.Errorf("Usecase.CreateObservation() error = %v", err)
+	}
+}
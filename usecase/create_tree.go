@@ -3,26 +3,72 @@ package usecase
 import (
 	"context"
 	"errors"
+	"time"
 
 	m "github.com/SawitProRecruitment/UserService/types"
 )
 
 func (u *Usecase) CreateTree(ctx context.Context, estateID string, tree m.Tree) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.createTree(ctx, estateID, tree)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) createTree(ctx context.Context, estateID string, tree m.Tree) (id string, err error) {
 	// safeguard
 	estate, err := u.Repo.GetEstateByID(ctx, estateID)
 	if err != nil {
 		return
 	}
 	if estate.ID == "" {
-		return "", errors.New("estate is not exist")
+		return "", notExist("estate")
 	}
 	if tree.Height > 30 || tree.Height < 1 {
 		return "", errors.New("tree's height is not in range")
 	}
+	if tree.Status == "" {
+		tree.Status = m.TreeHealthy
+	}
+	if !validTreeStatus(tree.Status) {
+		return "", errors.New("tree's status is not valid")
+	}
+	if tree.PlantedAt != nil && tree.PlantedAt.After(time.Now()) {
+		return "", errors.New("tree's planting date is in the future")
+	}
 	if tree.X > estate.Length || tree.X < 1 ||
 		tree.Y > estate.Width || tree.Y < 1 {
 		return "", errors.New("tree is outside estate")
 	}
+	if !insideEstate(estate, tree.X, tree.Y) {
+		return "", errors.New("tree is outside estate boundary")
+	}
+
+	_, taken, err := u.Repo.GetTreeAt(ctx, estateID, tree.X, tree.Y)
+	if err != nil {
+		return
+	}
+	if taken {
+		return "", errors.New("plot already has a tree")
+	}
+
+	blocks, err := u.Repo.GetBlocks(ctx, estateID)
+	if err != nil {
+		return
+	}
+	tree.BlockID = blockAt(blocks, tree.X, tree.Y)
 
 	return u.Repo.CreateTree(ctx, estateID, tree)
 }
+
+func validTreeStatus(status string) bool {
+	switch status {
+	case m.TreeHealthy, m.TreeDiseased, m.TreeDead, m.TreeReplanted:
+		return true
+	}
+	return false
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/repository"
 	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
@@ -13,6 +14,9 @@ import (
 
 func TestUsecase_CreateTree(t *testing.T) {
 	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	planted := time.Date(2015, 3, 1, 0, 0, 0, 0, time.UTC)
+	future := time.Now().AddDate(1, 0, 0)
 	type args struct {
 		ctx      context.Context
 		estateID string
@@ -41,7 +45,104 @@ func TestUsecase_CreateTree(t *testing.T) {
 					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
 				},
 				func() *gomock.Call {
-					return mockRepo.EXPECT().CreateTree(gomock.Any(), "aaa", m.Tree{X: 1, Y: 1, Height: 2}).Return("aaa", nil)
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 1, 1).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateTree(gomock.Any(), "aaa", m.Tree{X: 1, Y: 1, Height: 2, Status: m.TreeHealthy}).Return("aaa", nil)
+				},
+			},
+		},
+		{
+			name: "when tree on the boundary edge, return id and no error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 2, Y: 3, Height: 2},
+			},
+			wantId:  "aaa",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 4, Y: 1}, {X: 1, Y: 4}}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateTree(gomock.Any(), "aaa", m.Tree{X: 2, Y: 3, Height: 2, Status: m.TreeHealthy}).Return("aaa", nil)
+				},
+			},
+		},
+		{
+			name: "when tree outside boundary, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 3, Y: 3, Height: 2},
+			},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 4, Y: 1}, {X: 1, Y: 4}}}, nil)
+				},
+			},
+		},
+		{
+			name: "when tree inside a block, store it in the block",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 2, Y: 2, Height: 2},
+			},
+			wantId:  "aaa",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 4, Width: 4}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 2).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{
+						{ID: "b1", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 1, YMax: 4}},
+						{ID: "b2", Extent: m.Extent{XMin: 2, YMin: 1, XMax: 4, YMax: 4}},
+					}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateTree(gomock.Any(), "aaa", m.Tree{X: 2, Y: 2, Height: 2, BlockID: "b2", Status: m.TreeHealthy}).Return("aaa", nil)
+				},
+			},
+		},
+		{
+			name: "when get blocks return error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 1, Y: 1, Height: 2},
+			},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 1, 1).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, errors.New("blocks"))
 				},
 			},
 		},
@@ -93,6 +194,101 @@ func TestUsecase_CreateTree(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "when tree has attributes, keep them",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 1, Y: 1, Height: 2, Variety: "DxP", PlantedAt: &planted, Status: m.TreeReplanted},
+			},
+			wantId:  "aaa",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 1, 1).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateTree(gomock.Any(), "aaa", m.Tree{X: 1, Y: 1, Height: 2, Variety: "DxP", PlantedAt: &planted, Status: m.TreeReplanted}).Return("aaa", nil)
+				},
+			},
+		},
+		{
+			name: "when plot already has a tree, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 1, Y: 2, Height: 2},
+			},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 1, 2).Return(m.Tree{X: 1, Y: 2, Height: 5}, true, nil)
+				},
+			},
+		},
+		{
+			name: "when get trees return error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 1, Y: 1, Height: 2},
+			},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 1, 1).Return(m.Tree{}, false, errors.New("trees"))
+				},
+			},
+		},
+		{
+			name: "when tree status is unknown, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 1, Y: 1, Height: 2, Status: "sick"},
+			},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+			},
+		},
+		{
+			name: "when tree is planted in the future, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				tree:     m.Tree{X: 1, Y: 1, Height: 2, PlantedAt: &future},
+			},
+			wantId:  "",
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+			},
+		},
 		{
 			name: "when tree is too tall, return error",
 			args: args{
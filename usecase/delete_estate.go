@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+)
+
+// DeleteEstate soft-deletes the estate. It stays hidden, together with its
+// trees, until restored or purged.
+func (u *Usecase) DeleteEstate(ctx context.Context, estateID string) (err error) {
+	deleted, err := u.Repo.DeleteEstate(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if !deleted {
+		return notExist("estate")
+	}
+	return nil
+}
+
+func (u *Usecase) RestoreEstate(ctx context.Context, estateID string) (err error) {
+	restored, err := u.Repo.RestoreEstate(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if !restored {
+		return errors.New("estate is not deleted")
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_DeleteEstate(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	type args struct {
+		ctx      context.Context
+		estateID string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when all good, delete the estate",
+			args:    args{ctx: context.Background(), estateID: "aaa"},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().DeleteEstate(gomock.Any(), "aaa").Return(true, nil)
+				},
+			},
+		},
+		{
+			name:    "when estate not found, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa"},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().DeleteEstate(gomock.Any(), "aaa").Return(false, nil)
+				},
+			},
+		},
+		{
+			name:    "when delete return error, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa"},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().DeleteEstate(gomock.Any(), "aaa").Return(false, errors.New("delete"))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			if err := u.DeleteEstate(tt.args.ctx, tt.args.estateID); (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.DeleteEstate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsecase_RestoreEstate(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+
+	mockRepo.EXPECT().RestoreEstate(gomock.Any(), "aaa").Return(true, nil)
+	if err := u.RestoreEstate(context.Background(), "aaa"); err != nil {
+		t.Errorf("Usecase.RestoreEstate() error = %v", err)
+	}
+
+	mockRepo.EXPECT().RestoreEstate(gomock.Any(), "bbb").Return(false, nil)
+	if err := u.RestoreEstate(context.Background(), "bbb"); err == nil || err.Error() != "estate is not deleted" {
+		t.Errorf("Usecase.RestoreEstate() error = %v, want estate is not deleted", err)
+	}
+}
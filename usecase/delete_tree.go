@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+)
+
+// DeleteTree soft-deletes the tree planted on plot (x, y).
+func (u *Usecase) DeleteTree(ctx context.Context, estateID string, x int, y int) (err error) {
+	return u.inTx(ctx, func(tx *Usecase) error {
+		return tx.deleteTree(ctx, estateID, x, y)
+	})
+}
+
+func (u *Usecase) deleteTree(ctx context.Context, estateID string, x int, y int) (err error) {
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return notExist("estate")
+	}
+
+	deleted, err := u.Repo.DeleteTree(ctx, estateID, x, y)
+	if err != nil {
+		return
+	}
+	if !deleted {
+		return notExist("tree")
+	}
+	return nil
+}
+
+// RestoreTree restores the tree deleted last from plot (x, y), unless another
+// tree has been planted there since or the plot is no longer in the estate.
+func (u *Usecase) RestoreTree(ctx context.Context, estateID string, x int, y int) (err error) {
+	return u.inTx(ctx, func(tx *Usecase) error {
+		return tx.restoreTree(ctx, estateID, x, y)
+	})
+}
+
+func (u *Usecase) restoreTree(ctx context.Context, estateID string, x int, y int) (err error) {
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return notExist("estate")
+	}
+	if x > estate.Length || x < 1 || y > estate.Width || y < 1 || !insideEstate(estate, x, y) {
+		return errors.New("tree is outside estate")
+	}
+
+	_, taken, err := u.Repo.GetTreeAt(ctx, estateID, x, y)
+	if err != nil {
+		return
+	}
+	if taken {
+		return errors.New("plot already has a tree")
+	}
+
+	restored, err := u.Repo.RestoreTree(ctx, estateID, x, y)
+	if err != nil {
+		return
+	}
+	if !restored {
+		return errors.New("tree is not deleted")
+	}
+	return nil
+}
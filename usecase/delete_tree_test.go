@@ -0,0 +1,221 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_DeleteTree(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	type args struct {
+		ctx      context.Context
+		estateID string
+		x        int
+		y        int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when all good, delete the tree",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 2, y: 3},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().DeleteTree(gomock.Any(), "aaa", 2, 3).Return(true, nil)
+				},
+			},
+		},
+		{
+			name:    "when plot has no tree, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 2, y: 3},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().DeleteTree(gomock.Any(), "aaa", 2, 3).Return(false, nil)
+				},
+			},
+		},
+		{
+			name:    "when estate not found, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 2, y: 3},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			if err := u.DeleteTree(tt.args.ctx, tt.args.estateID, tt.args.x, tt.args.y); (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.DeleteTree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsecase_RestoreTree(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	type args struct {
+		ctx      context.Context
+		estateID string
+		x        int
+		y        int
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when all good, restore the tree",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 2, y: 3},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().RestoreTree(gomock.Any(), "aaa", 2, 3).Return(true, nil)
+				},
+			},
+		},
+		{
+			name:    "when plot is outside the estate, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 6, y: 3},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+			},
+		},
+		{
+			name:    "when plot was replanted, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 2, y: 3},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{X: 2, Y: 3, Height: 4}, true, nil)
+				},
+			},
+		},
+		{
+			name:    "when no deleted tree, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 2, y: 3},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().RestoreTree(gomock.Any(), "aaa", 2, 3).Return(false, nil)
+				},
+			},
+		},
+		{
+			name:    "when restore return error, return error",
+			args:    args{ctx: context.Background(), estateID: "aaa", x: 2, y: 3},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 2, 3).Return(m.Tree{}, false, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().RestoreTree(gomock.Any(), "aaa", 2, 3).Return(false, errors.New("restore"))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			if err := u.RestoreTree(tt.args.ctx, tt.args.estateID, tt.args.x, tt.args.y); (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.RestoreTree() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUsecase_RestoreTree_afterShrink(t *testing.T) {
+	u := NewUsecase(repository.NewMemoryRepository())
+	ctx := context.Background()
+	estateID, _ := u.CreateEstate(ctx, m.Estate{Length: 10, Width: 10})
+	u.CreateTree(ctx, estateID, m.Tree{X: 10, Y: 10, Height: 5})
+	if err := u.DeleteTree(ctx, estateID, 10, 10); err != nil {
+		t.Fatalf("Usecase.DeleteTree() error = %v", err)
+	}
+
+	// the deleted tree keeps its plot in the estate
+	small := 5
+	if _, err := u.UpdateEstate(ctx, estateID, m.EstateUpdate{Length: &small, Width: &small}); err == nil {
+		t.Errorf("Usecase.UpdateEstate() expected error for shrinking over a deleted tree")
+	}
+	if err := u.RestoreTree(ctx, estateID, 10, 10); err != nil {
+		t.Errorf("Usecase.RestoreTree() error = %v", err)
+	}
+	if _, err := u.GetEstateMap(ctx, estateID, 4, false); err != nil {
+		t.Errorf("Usecase.GetEstateMap() error = %v", err)
+	}
+}
@@ -0,0 +1,191 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// blockCounts is the number of living trees of every block, those outside of
+// any block under "".
+type blockCounts map[string]int
+
+// countBlocks counts the streamed trees by block, leaving out the dead and
+// replanted ones which need no fertilizer.
+func countBlocks(trees treeStream) (blockCounts, error) {
+	counts := blockCounts{}
+	err := trees(func(t m.Tree) error {
+		if t.Status == m.TreeDead || t.Status == m.TreeReplanted {
+			return nil
+		}
+		counts[t.BlockID]++
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// scope returns the number of trees of the block, or of the whole estate
+// when blockID is empty.
+func (c blockCounts) scope(blockID string) (count int) {
+	if blockID != "" {
+		return c[blockID]
+	}
+	for _, n := range c {
+		count += n
+	}
+	return count
+}
+
+// CreateFertilizerProgram defines a fertilizer program of the estate, or of
+// one of its blocks, starting today when no day is given.
+func (u *Usecase) CreateFertilizerProgram(ctx context.Context, program m.FertilizerProgram) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.createFertilizerProgram(ctx, program)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) createFertilizerProgram(ctx context.Context, program m.FertilizerProgram) (id string, err error) {
+	if program.Product == "" {
+		return "", errors.New("fertilizer program needs a product")
+	}
+	if program.Dose <= 0 {
+		return "", errors.New("fertilizer dose must be positive")
+	}
+	if program.Interval < 1 {
+		return "", errors.New("fertilizer interval must be at least a day")
+	}
+	if program.StartOn.IsZero() {
+		program.StartOn = time.Now()
+	}
+	program.StartOn = dateOf(program.StartOn)
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, program.EstateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return "", notExist("estate")
+	}
+	if program.BlockID != "" {
+		blocks, err := u.Repo.GetBlocks(ctx, program.EstateID)
+		if err != nil {
+			return "", err
+		}
+		if !slices.ContainsFunc(blocks, func(b m.Block) bool { return b.ID == program.BlockID }) {
+			return "", notExist("block")
+		}
+	}
+
+	return u.Repo.CreateFertilizerProgram(ctx, program)
+}
+
+// ListFertilizerPrograms returns the fertilizer programs of the estate.
+func (u *Usecase) ListFertilizerPrograms(ctx context.Context, estateID string) (programs []m.FertilizerProgram, err error) {
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return nil, notExist("estate")
+	}
+
+	programs, err = u.Repo.GetFertilizerPrograms(ctx, estateID)
+	if err != nil {
+		return nil, err
+	}
+	if programs == nil {
+		programs = []m.FertilizerProgram{}
+	}
+	return programs, nil
+}
+
+// getFertilizerProgram loads the program, checking it belongs to the estate.
+func (u *Usecase) getFertilizerProgram(ctx context.Context, estateID string, programID string) (program m.FertilizerProgram, err error) {
+	programs, err := u.Repo.GetFertilizerPrograms(ctx, estateID)
+	if err != nil {
+		return
+	}
+	for _, p := range programs {
+		if p.ID == programID {
+			return p, nil
+		}
+	}
+	return m.FertilizerProgram{}, notExist("fertilizer program")
+}
+
+// RecordFertilizerApplication records an application of the program, today
+// when no day is given. Without a tree count every tree of the program is
+// assumed treated, and without a quantity the program's dose is assumed.
+func (u *Usecase) RecordFertilizerApplication(ctx context.Context, estateID string, application m.FertilizerApplication) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.recordFertilizerApplication(ctx, estateID, application)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) recordFertilizerApplication(ctx context.Context, estateID string, application m.FertilizerApplication) (id string, err error) {
+	if application.Trees < 0 || application.Quantity < 0 {
+		return "", errors.New("fertilizer application cannot be negative")
+	}
+	if application.AppliedOn.IsZero() {
+		application.AppliedOn = time.Now()
+	}
+	if application.AppliedOn.After(time.Now()) {
+		return "", errors.New("fertilizer application is in the future")
+	}
+	application.AppliedOn = dateOf(application.AppliedOn)
+
+	program, err := u.getFertilizerProgram(ctx, estateID, application.ProgramID)
+	if err != nil {
+		return
+	}
+	if application.Trees == 0 {
+		counts, err := countBlocks(u.repoStream(ctx, estateID))
+		if err != nil {
+			return "", err
+		}
+		application.Trees = counts.scope(program.BlockID)
+	}
+	if application.Quantity == 0 {
+		application.Quantity = float64(application.Trees) * program.Dose
+	}
+
+	return u.Repo.CreateFertilizerApplication(ctx, application)
+}
+
+// ListFertilizerApplications returns the applications of the program, oldest
+// first.
+func (u *Usecase) ListFertilizerApplications(ctx context.Context, estateID string, programID string) (applications []m.FertilizerApplication, err error) {
+	if _, err = u.getFertilizerProgram(ctx, estateID, programID); err != nil {
+		return
+	}
+
+	all, err := u.Repo.GetFertilizerApplications(ctx, estateID)
+	if err != nil {
+		return
+	}
+	applications = []m.FertilizerApplication{}
+	for _, a := range all {
+		if a.ProgramID == programID {
+			applications = append(applications, a)
+		}
+	}
+	return applications, nil
+}
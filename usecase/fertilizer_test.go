@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_CreateFertilizerProgram(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	program := m.FertilizerProgram{EstateID: "aaa", BlockID: "b1", Product: "NPK", Dose: 1.5, Interval: 90, StartOn: day}
+	tests := []struct {
+		name      string
+		program   m.FertilizerProgram
+		wantId    string
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when all good, return id",
+			program: program,
+			wantId:  "ppp",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "b1"}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateFertilizerProgram(gomock.Any(), program).Return("ppp", nil)
+				},
+			},
+		},
+		{
+			name:    "when block not found, return error",
+			program: program,
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, nil)
+				},
+			},
+		},
+		{
+			name:    "when estate not found, return error",
+			program: program,
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name:    "when product is missing, return error",
+			program: m.FertilizerProgram{EstateID: "aaa", Dose: 1, Interval: 30},
+			wantErr: true,
+			repo:    mockRepo,
+		},
+		{
+			name:    "when dose is not positive, return error",
+			program: m.FertilizerProgram{EstateID: "aaa", Product: "NPK", Interval: 30},
+			wantErr: true,
+			repo:    mockRepo,
+		},
+		{
+			name:    "when interval is missing, return error",
+			program: m.FertilizerProgram{EstateID: "aaa", Product: "NPK", Dose: 1},
+			wantErr: true,
+			repo:    mockRepo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, call := range tt.mockCalls {
+				call()
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotId, err := u.CreateFertilizerProgram(context.Background(), tt.program)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateFertilizerProgram() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("Usecase.CreateFertilizerProgram() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
+
+func TestUsecase_ListFertilizerPrograms(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetFertilizerPrograms(gomock.Any(), "aaa").Return(nil, nil)
+	if got, err := u.ListFertilizerPrograms(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, []m.FertilizerProgram{}) {
+		t.Errorf("Usecase.ListFertilizerPrograms() = %v, %v, want empty list", got, err)
+	}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+	if _, err := u.ListFertilizerPrograms(context.Background(), "aaa"); err == nil {
+		t.Errorf("Usecase.ListFertilizerPrograms() expected error for unknown estate")
+	}
+}
+
+func TestUsecase_RecordFertilizerApplication(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	u := &Usecase{Repo: mockRepo}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	programs := []m.FertilizerProgram{{ID: "ppp", EstateID: "aaa", BlockID: "b1", Product: "NPK", Dose: 1.5, Interval: 90}}
+
+	// tree count and quantity from the program
+	mockRepo.EXPECT().GetFertilizerPrograms(gomock.Any(), "aaa").Return(programs, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 1, Y: 1, BlockID: "b1"}, {X: 2, Y: 1, BlockID: "b1"}, {X: 3, Y: 1, BlockID: "b1", Status: m.TreeDead}, {X: 5, Y: 5}}, nil))
+	mockRepo.EXPECT().CreateFertilizerApplication(gomock.Any(), m.FertilizerApplication{ProgramID: "ppp", AppliedOn: day, Trees: 2, Quantity: 3}).Return("fff", nil)
+	if id, err := u.RecordFertilizerApplication(context.Background(), "aaa", m.FertilizerApplication{ProgramID: "ppp", AppliedOn: day.Add(10 * time.Hour)}); err != nil || id != "fff" {
+		t.Errorf("Usecase.RecordFertilizerApplication() = %v, %v, want fff", id, err)
+	}
+
+	// actual figures kept
+	mockRepo.EXPECT().GetFertilizerPrograms(gomock.Any(), "aaa").Return(programs, nil)
+	mockRepo.EXPECT().CreateFertilizerApplication(gomock.Any(), m.FertilizerApplication{ProgramID: "ppp", AppliedOn: day, Trees: 1, Quantity: 2}).Return("fff", nil)
+	if _, err := u.RecordFertilizerApplication(context.Background(), "aaa", m.FertilizerApplication{ProgramID: "ppp", AppliedOn: day, Trees: 1, Quantity: 2}); err != nil {
+		t.Errorf("Usecase.RecordFertilizerApplication() error = %v", err)
+	}
+
+	mockRepo.EXPECT().GetFertilizerPrograms(gomock.Any(), "aaa").Return(programs, nil)
+	if _, err := u.RecordFertilizerApplication(context.Background(), "aaa", m.FertilizerApplication{ProgramID: "qqq", AppliedOn: day}); err == nil {
+		t.Errorf("Usecase.RecordFertilizerApplication() expected error for unknown program")
+	}
+	if _, err := u.RecordFertilizerApplication(context.Background(), "aaa", m.FertilizerApplication{ProgramID: "ppp", AppliedOn: time.Now().AddDate(0, 0, 2)}); err == nil {
+		t.Errorf("Usecase.RecordFertilizerApplication() expected error for future application")
+	}
+	if _, err := u.RecordFertilizerApplication(context.Background(), "aaa", m.FertilizerApplication{ProgramID: "ppp", Trees: -1}); err == nil {
+		t.Errorf("Usecase.RecordFertilizerApplication() expected error for negative trees")
+	}
+}
+
+func TestUsecase_ListFertilizerApplications(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+	day := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	mockRepo.EXPECT().GetFertilizerPrograms(gomock.Any(), "aaa").Return([]m.FertilizerProgram{{ID: "ppp"}, {ID: "qqq"}}, nil)
+	mockRepo.EXPECT().GetFertilizerApplications(gomock.Any(), "aaa").Return([]m.FertilizerApplication{
+		{ID: "f1", ProgramID: "ppp", AppliedOn: day},
+		{ID: "f2", ProgramID: "qqq", AppliedOn: day},
+	}, nil)
+	want := []m.FertilizerApplication{{ID: "f1", ProgramID: "ppp", AppliedOn: day}}
+	if got, err := u.ListFertilizerApplications(context.Background(), "aaa", "ppp"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Usecase.ListFertilizerApplications() = %v, %v, want %v", got, err, want)
+	}
+}
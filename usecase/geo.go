@@ -0,0 +1,30 @@
+package usecase
+
+import (
+	"math"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+const earthRadius = 6378137.0
+
+// plotLocation returns the WGS84 coordinate of the centre of plot (x, y), or
+// nil when the estate is not georeferenced. Estates are small enough for the
+// offset from the origin to be projected on a flat earth.
+func plotLocation(geo *m.GeoAnchor, x int, y int) *m.Coordinate {
+	if geo == nil {
+		return nil
+	}
+
+	alongX := (float64(x) - 0.5) * geo.PlotSize
+	alongY := (float64(y) - 0.5) * geo.PlotSize
+	bearingX := geo.Bearing * math.Pi / 180
+	bearingY := bearingX - math.Pi/2
+
+	north := alongX*math.Cos(bearingX) + alongY*math.Cos(bearingY)
+	east := alongX*math.Sin(bearingX) + alongY*math.Sin(bearingY)
+
+	lat := geo.OriginLat + north/earthRadius*180/math.Pi
+	lon := geo.OriginLon + east/(earthRadius*math.Cos(geo.OriginLat*math.Pi/180))*180/math.Pi
+	return &m.Coordinate{Lat: lat, Lon: lon}
+}
@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"testing"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_plotLocation(t *testing.T) {
+	// one plot is roughly 1/1000 degree at the equator
+	plotSize := earthRadius * 3.141592653589793 / 180 / 1000
+
+	tests := []struct {
+		name string
+		geo  *m.GeoAnchor
+		x    int
+		y    int
+		want *m.Coordinate
+	}{
+		{
+			name: "when estate is not georeferenced, return nil",
+			geo:  nil,
+			x:    1,
+			y:    1,
+			want: nil,
+		},
+		{
+			name: "when x axis points east, x is longitude and y is latitude",
+			geo:  &m.GeoAnchor{OriginLat: 0, OriginLon: 100, Bearing: 90, PlotSize: plotSize},
+			x:    3,
+			y:    2,
+			want: &m.Coordinate{Lat: 0.0015, Lon: 100.0025},
+		},
+		{
+			name: "when x axis points north, y points west",
+			geo:  &m.GeoAnchor{OriginLat: 0, OriginLon: 100, Bearing: 0, PlotSize: plotSize},
+			x:    3,
+			y:    2,
+			want: &m.Coordinate{Lat: 0.0025, Lon: 99.9985},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := plotLocation(tt.geo, tt.x, tt.y)
+			if tt.want == nil {
+				assert.Nil(t, got)
+				return
+			}
+			if assert.NotNil(t, got) {
+				assert.InDelta(t, tt.want.Lat, got.Lat, 1e-9)
+				assert.InDelta(t, tt.want.Lon, got.Lon, 1e-9)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package usecase
+
+import (
+	"context"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// GetAuditLog returns the deletes, restores and purges of the estate and its
+// trees, oldest first. It works for deleted and purged estates too.
+func (u *Usecase) GetAuditLog(ctx context.Context, estateID string) (entries []m.AuditEntry, err error) {
+	entries, err = u.Repo.GetAuditLog(ctx, estateID)
+	if err != nil {
+		return nil, err
+	}
+	if entries == nil {
+		entries = []m.AuditEntry{}
+	}
+	return entries, nil
+}
@@ -0,0 +1,42 @@
+package usecase
+
+import (
+	"context"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// GetBlockDroneDistance returns the distance of a drone monitoring only the
+// plots of the block.
+func (u *Usecase) GetBlockDroneDistance(ctx context.Context, estateID string, blockID string) (distance int, err error) {
+	estate, block, err := u.getBlock(ctx, estateID, blockID)
+	if err != nil {
+		return
+	}
+
+	clipped, trees := clipToBlock(estate, u.repoStream(ctx, estateID), block)
+	return streamTraveledDistance(trees, clipped)
+}
+
+// GetBlockDroneWaypoints returns the waypoints of the drone plan of the block,
+// in estate coordinates.
+func (u *Usecase) GetBlockDroneWaypoints(ctx context.Context, estateID string, blockID string, withGeo bool) (waypoints []m.Waypoint, err error) {
+	estate, block, err := u.getBlock(ctx, estateID, blockID)
+	if err != nil {
+		return
+	}
+
+	clipped, trees := clipToBlock(estate, u.repoStream(ctx, estateID), block)
+	waypoints, err = streamDroneWaypoints(trees, clipped)
+	if err != nil {
+		return nil, err
+	}
+	for i := range waypoints {
+		waypoints[i].X += block.Extent.XMin - 1
+		waypoints[i].Y += block.Extent.YMin - 1
+		if withGeo {
+			waypoints[i].Location = plotLocation(estate.Geo, waypoints[i].X, waypoints[i].Y)
+		}
+	}
+	return waypoints, nil
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+func (u *Usecase) GetBlockStats(ctx context.Context, estateID string, blockID string, filter m.TreeFilter) (stat m.Stats, err error) {
+	estate, block, err := u.getBlock(ctx, estateID, blockID)
+	if err != nil {
+		return
+	}
+
+	_, trees := clipToBlock(estate, u.repoStream(ctx, estateID), block)
+	counts := heightCounts{}
+	err = trees(func(tree m.Tree) error {
+		if matchTree(tree, filter) {
+			counts[tree.Height]++
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	return counts.stats(), nil
+}
@@ -1,51 +1,211 @@
 package usecase
 
 import (
+	"cmp"
 	"context"
-	"errors"
+	"math"
+	"slices"
 
 	m "github.com/SawitProRecruitment/UserService/types"
 )
 
-func countTraveledDistance(trees []m.Tree, maxLength int, maxWidth int) int {
-	total := 0
-	y := 1
-	x := 1
-
-	curr_height := 0
-
-	for y <= maxWidth {
-		for x <= maxLength {
-			for _, t := range trees {
-				if t.X == x && t.Y == y {
-					if curr_height > t.Height+1 {
-						going_down := curr_height - (t.Height + 1)
-						total = total + going_down
-						curr_height = curr_height - going_down
-					} else if curr_height < t.Height+1 {
-						going_up := (t.Height + 1) - curr_height
-						curr_height = curr_height + going_up
-						total = total + going_up
-					}
-					break
-				}
-			}
+// flightSpan is a run of plots on row y flown from plot from to plot to.
+type flightSpan struct {
+	y    int
+	from int
+	to   int
+}
 
-			if x != maxLength {
-				total = total + 10
-			}
-			x++
+// flightSpans returns the runs of plots the drone flies over, in order. Every
+// row is flown west to east; plots outside the boundary are skipped.
+func flightSpans(estate m.Estate) []flightSpan {
+	spans := []flightSpan{}
+	for y := 1; y <= estate.Width; y++ {
+		for _, s := range rowSpans(estate, y) {
+			spans = append(spans, flightSpan{y: y, from: s[0], to: s[1]})
+		}
+	}
+	return spans
+}
+
+// flightCost is the distance flown from one plot to the next, 10 meters a
+// plot along a row. Moving on to a later row costs 10 meters a row wherever
+// the drone ended the previous one.
+func flightCost(from m.Point, to m.Point) int {
+	if from.Y != to.Y {
+		return 10 * abs(to.Y-from.Y)
+	}
+	return 10 * abs(to.X-from.X)
+}
+
+// walk visits the columns of the span in the order the drone flies over them.
+func (s flightSpan) walk(visit func(x int)) {
+	step := 1
+	if s.from > s.to {
+		step = -1
+	}
+	for x := s.from; x != s.to+step; x += step {
+		visit(x)
+	}
+}
+
+// treeStream calls fn with trees ordered by row then column, one at a time,
+// and stops at the first error of fn.
+type treeStream func(fn func(tree m.Tree) error) error
+
+// sliceStream streams the trees, sorted by row then column.
+func sliceStream(trees []m.Tree) treeStream {
+	trees = slices.Clone(trees)
+	slices.SortStableFunc(trees, func(a, b m.Tree) int {
+		if a.Y != b.Y {
+			return cmp.Compare(a.Y, b.Y)
 		}
-		if y != maxWidth {
-			total = total + 10
-		} else {
-			total = total + curr_height
+		return cmp.Compare(a.X, b.X)
+	})
+	return func(fn func(tree m.Tree) error) error {
+		for _, t := range trees {
+			if err := fn(t); err != nil {
+				return err
+			}
 		}
-		x = 1
-		y++
+		return nil
+	}
+}
+
+// repoStream streams the trees of the estate from the repository.
+func (u *Usecase) repoStream(ctx context.Context, estateID string) treeStream {
+	return func(fn func(tree m.Tree) error) error {
+		return u.Repo.EachTree(ctx, estateID, fn)
+	}
+}
+
+// flight follows the drone over the estate as its trees are added, ordered
+// by row then column, calling fly for every flight span in order with the
+// height of the first tree of every plot of its row by column. Only one row of
+// trees is kept at a time.
+type flight struct {
+	spans   []flightSpan
+	next    int
+	fly     func(s flightSpan, heights map[int]int)
+	rowY    int
+	heights map[int]int
+}
+
+func newFlight(estate m.Estate, fly func(s flightSpan, heights map[int]int)) *flight {
+	return &flight{spans: flightSpans(estate), fly: fly, heights: map[int]int{}}
+}
+
+// flyTo flies every span up to row y, over the given heights on row y.
+func (f *flight) flyTo(y int, heights map[int]int) {
+	for ; f.next < len(f.spans) && f.spans[f.next].y < y; f.next++ {
+		f.fly(f.spans[f.next], nil)
+	}
+	for ; f.next < len(f.spans) && f.spans[f.next].y == y; f.next++ {
+		f.fly(f.spans[f.next], heights)
+	}
+}
+
+// add reads the next tree of the estate.
+func (f *flight) add(t m.Tree) {
+	if t.Y != f.rowY {
+		f.flyTo(f.rowY, f.heights)
+		f.rowY, f.heights = t.Y, map[int]int{}
 	}
+	if _, ok := f.heights[t.X]; !ok {
+		f.heights[t.X] = t.Height
+	}
+}
+
+// land flies the rest of the estate once every tree was added.
+func (f *flight) land() {
+	f.flyTo(f.rowY, f.heights)
+	f.flyTo(math.MaxInt, nil)
+	f.heights = nil
+}
+
+// flySpans flies over the streamed trees of the estate, see flight.
+func flySpans(trees treeStream, estate m.Estate, fly func(s flightSpan, heights map[int]int)) error {
+	f := newFlight(estate, fly)
+	err := trees(func(t m.Tree) error {
+		f.add(t)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	f.land()
+	return nil
+}
+
+// droneTurns returns the plots where the drone changes direction, which is
+// enough to draw its whole path.
+func droneTurns(estate m.Estate) []m.Point {
+	turns := []m.Point{}
+	for _, s := range flightSpans(estate) {
+		turns = append(turns, m.Point{X: s.from, Y: s.y}, m.Point{X: s.to, Y: s.y})
+	}
+	return turns
+}
+
+func countTraveledDistance(trees []m.Tree, estate m.Estate) int {
+	// a slice never fails to stream
+	distance, _ := streamTraveledDistance(sliceStream(trees), estate)
+	return distance
+}
+
+// distanceMeter measures the distance the drone travels over the trees added
+// to its flight.
+type distanceMeter struct {
+	*flight
+	total  int
+	prev   *m.Point
+	height int
+}
 
-	return total
+func newDistanceMeter(estate m.Estate) *distanceMeter {
+	d := &distanceMeter{}
+	d.flight = newFlight(estate, d.fly)
+	return d
+}
+
+func (d *distanceMeter) fly(s flightSpan, heights map[int]int) {
+	s.walk(func(x int) {
+		if d.prev != nil {
+			d.total = d.total + flightCost(*d.prev, m.Point{X: x, Y: s.y})
+		}
+		d.prev = &m.Point{X: x, Y: s.y}
+
+		if height, ok := heights[x]; ok {
+			if d.height > height+1 {
+				going_down := d.height - (height + 1)
+				d.total = d.total + going_down
+				d.height = d.height - going_down
+			} else if d.height < height+1 {
+				going_up := (height + 1) - d.height
+				d.height = d.height + going_up
+				d.total = d.total + going_up
+			}
+		}
+	})
+}
+
+// distance lands the drone and returns the whole distance it traveled.
+func (d *distanceMeter) distance() int {
+	d.land()
+	// landing
+	return d.total + d.height
+}
+
+func streamTraveledDistance(trees treeStream, estate m.Estate) (int, error) {
+	d := newDistanceMeter(estate)
+	err := trees(func(t m.Tree) error {
+		d.add(t)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return d.distance(), nil
 }
 
 func (u *Usecase) GetDroneDistance(ctx context.Context, estateID string) (distance int, err error) {
@@ -55,12 +215,8 @@ func (u *Usecase) GetDroneDistance(ctx context.Context, estateID string) (distan
 		return
 	}
 	if estate.ID == "" {
-		return 0, errors.New("estate is not exist")
+		return 0, notExist("estate")
 	}
 
-	trees, err := u.Repo.GetTree(ctx, estateID)
-	if err != nil {
-		return
-	}
-	return countTraveledDistance(trees, estate.Length, estate.Width), nil
+	return streamTraveledDistance(u.repoStream(ctx, estateID), estate)
 }
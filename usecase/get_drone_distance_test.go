@@ -3,6 +3,7 @@ package usecase
 import (
 	"context"
 	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/SawitProRecruitment/UserService/repository"
@@ -13,9 +14,8 @@ import (
 
 func Test_countTraveledDistance(t *testing.T) {
 	type args struct {
-		trees     []m.Tree
-		maxLength int
-		maxWidth  int
+		trees  []m.Tree
+		estate m.Estate
 	}
 	tests := []struct {
 		name string
@@ -31,21 +31,51 @@ func Test_countTraveledDistance(t *testing.T) {
 					{X: 4, Y: 1, Height: 4},
 					{X: 4, Y: 2, Height: 4},
 				},
-				maxLength: 5,
-				maxWidth:  2,
+				estate: m.Estate{Length: 5, Width: 2},
 			},
 			want: 104,
 		},
+		{
+			name: "every row is flown west to east",
+			args: args{
+				trees: []m.Tree{
+					{X: 3, Y: 1, Height: 9},
+					{X: 1, Y: 2, Height: 9},
+					{X: 3, Y: 2, Height: 1},
+				},
+				estate: m.Estate{Length: 3, Width: 2},
+			},
+			want: 70,
+		},
+		{
+			name: "plots outside boundary are skipped",
+			args: args{
+				trees: []m.Tree{
+					{X: 1, Y: 1, Height: 4},
+					{X: 2, Y: 3, Height: 4},
+				},
+				estate: m.Estate{Length: 3, Width: 3, Boundary: []m.Point{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 3, Y: 2}, {X: 2, Y: 3}, {X: 1, Y: 2}}},
+			},
+			want: 70,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := countTraveledDistance(tt.args.trees, tt.args.maxLength, tt.args.maxWidth); got != tt.want {
+			if got := countTraveledDistance(tt.args.trees, tt.args.estate); got != tt.want {
 				t.Errorf("countTraveledDistance() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func Test_droneTurns(t *testing.T) {
+	got := droneTurns(m.Estate{Length: 3, Width: 3})
+	want := []m.Point{{X: 1, Y: 1}, {X: 3, Y: 1}, {X: 1, Y: 2}, {X: 3, Y: 2}, {X: 1, Y: 3}, {X: 3, Y: 3}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("droneTurns() = %v, want %v", got, want)
+	}
+}
+
 func TestUsecase_GetDroneDistance(t *testing.T) {
 	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
 	type args struct {
@@ -74,10 +104,10 @@ func TestUsecase_GetDroneDistance(t *testing.T) {
 					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 1}, nil)
 				},
 				func() *gomock.Call {
-					return mockRepo.EXPECT().GetTree(gomock.Any(), "aaa").Return([]m.Tree{
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{
 						{X: 2, Y: 1, Height: 5},
 						{X: 3, Y: 1, Height: 3},
-						{X: 4, Y: 1, Height: 4}}, nil)
+						{X: 4, Y: 1, Height: 4}}, nil))
 				},
 			},
 		},
@@ -125,7 +155,7 @@ func TestUsecase_GetDroneDistance(t *testing.T) {
 					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 1}, nil)
 				},
 				func() *gomock.Call {
-					return mockRepo.EXPECT().GetTree(gomock.Any(), "aaa").Return([]m.Tree{}, errors.New("tree"))
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{}, errors.New("tree")))
 				},
 			},
 		},
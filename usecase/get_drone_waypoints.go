@@ -0,0 +1,73 @@
+package usecase
+
+import (
+	"context"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// droneWaypoints returns the positions where the drone turns or changes its
+// altitude along its path, from take off to landing. Between two waypoints the
+// drone flies straight, changing altitude once it reaches the next one.
+func droneWaypoints(trees []m.Tree, estate m.Estate) []m.Waypoint {
+	// a slice never fails to stream
+	waypoints, _ := streamDroneWaypoints(sliceStream(trees), estate)
+	return waypoints
+}
+
+func streamDroneWaypoints(trees treeStream, estate m.Estate) ([]m.Waypoint, error) {
+	waypoints := []m.Waypoint{}
+	add := func(x int, y int, altitude int) {
+		wp := m.Waypoint{X: x, Y: y, Altitude: altitude}
+		if len(waypoints) > 0 && waypoints[len(waypoints)-1] == wp {
+			return
+		}
+		waypoints = append(waypoints, wp)
+	}
+
+	curr_height := 0
+	err := flySpans(trees, estate, func(s flightSpan, heights map[int]int) {
+		add(s.from, s.y, curr_height)
+		s.walk(func(x int) {
+			if height, ok := heights[x]; ok && height+1 != curr_height {
+				add(x, s.y, curr_height)
+				curr_height = height + 1
+				add(x, s.y, curr_height)
+			}
+		})
+		add(s.to, s.y, curr_height)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(waypoints) > 0 {
+		last := waypoints[len(waypoints)-1]
+		add(last.X, last.Y, 0)
+	}
+	return waypoints, nil
+}
+
+// GetDroneWaypoints returns the waypoints of the drone plan. When withGeo is
+// set and the estate is georeferenced every waypoint has its location.
+func (u *Usecase) GetDroneWaypoints(ctx context.Context, estateID string, withGeo bool) (waypoints []m.Waypoint, err error) {
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return nil, notExist("estate")
+	}
+
+	waypoints, err = streamDroneWaypoints(u.repoStream(ctx, estateID), estate)
+	if err != nil {
+		return nil, err
+	}
+	if withGeo {
+		for i := range waypoints {
+			waypoints[i].Location = plotLocation(estate.Geo, waypoints[i].X, waypoints[i].Y)
+		}
+	}
+	return waypoints, nil
+}
@@ -0,0 +1,183 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_droneWaypoints(t *testing.T) {
+	trees := []m.Tree{
+		{X: 2, Y: 1, Height: 5},
+		{X: 3, Y: 1, Height: 3},
+		{X: 4, Y: 1, Height: 4},
+		{X: 4, Y: 2, Height: 4},
+	}
+	want := []m.Waypoint{
+		{X: 1, Y: 1, Altitude: 0},
+		{X: 2, Y: 1, Altitude: 0},
+		{X: 2, Y: 1, Altitude: 6},
+		{X: 3, Y: 1, Altitude: 6},
+		{X: 3, Y: 1, Altitude: 4},
+		{X: 4, Y: 1, Altitude: 4},
+		{X: 4, Y: 1, Altitude: 5},
+		{X: 5, Y: 1, Altitude: 5},
+		{X: 1, Y: 2, Altitude: 5},
+		{X: 5, Y: 2, Altitude: 5},
+		{X: 5, Y: 2, Altitude: 0},
+	}
+
+	got := droneWaypoints(trees, m.Estate{Length: 5, Width: 2})
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("droneWaypoints() = %v, want %v", got, want)
+	}
+
+	// flying through the waypoints must cost the same as the drone distance
+	distance := 0
+	for i := 1; i < len(got); i++ {
+		distance += flightCost(m.Point{X: got[i-1].X, Y: got[i-1].Y}, m.Point{X: got[i].X, Y: got[i].Y}) + abs(got[i].Altitude-got[i-1].Altitude)
+	}
+	if want := countTraveledDistance(trees, m.Estate{Length: 5, Width: 2}); distance != want {
+		t.Errorf("droneWaypoints() distance = %v, want %v", distance, want)
+	}
+}
+
+func TestUsecase_GetDroneWaypoints(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	geo := &m.GeoAnchor{OriginLat: 1, OriginLon: 101, Bearing: 90, PlotSize: 10}
+	type args struct {
+		ctx      context.Context
+		estateID string
+		withGeo  bool
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantWaypoints []m.Waypoint
+		wantErr       bool
+		repo          repository.RepositoryInterface
+		mockCalls     []func() *gomock.Call
+	}{
+		{
+			name: "when all good, return waypoints",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantWaypoints: []m.Waypoint{
+				{X: 1, Y: 1, Altitude: 0},
+				{X: 1, Y: 1, Altitude: 6},
+				{X: 2, Y: 1, Altitude: 6},
+				{X: 2, Y: 1, Altitude: 0},
+			},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 1, Geo: geo}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 1, Y: 1, Height: 5}}, nil))
+				},
+			},
+		},
+		{
+			name: "when geo requested, return waypoints with location",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				withGeo:  true,
+			},
+			wantWaypoints: []m.Waypoint{
+				{X: 1, Y: 1, Altitude: 0, Location: plotLocation(geo, 1, 1)},
+				{X: 2, Y: 1, Altitude: 0, Location: plotLocation(geo, 2, 1)},
+			},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 1, Geo: geo}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, nil))
+				},
+			},
+		},
+		{
+			name: "when estate not found, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantWaypoints: nil,
+			wantErr:       true,
+			repo:          mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name: "when get estate give error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantWaypoints: nil,
+			wantErr:       true,
+			repo:          mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, errors.New("estate"))
+				},
+			},
+		},
+		{
+			name: "when get tree give error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantWaypoints: nil,
+			wantErr:       true,
+			repo:          mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 1}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, errors.New("tree")))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotWaypoints, err := u.GetDroneWaypoints(tt.args.ctx, tt.args.estateID, tt.args.withGeo)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.GetDroneWaypoints() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotWaypoints, tt.wantWaypoints) {
+				t.Errorf("Usecase.GetDroneWaypoints() = %v, want %v", gotWaypoints, tt.wantWaypoints)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package usecase
+
+import (
+	"context"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// GetEstateDivisions returns the estate with its divisions, each holding its
+// blocks.
+func (u *Usecase) GetEstateDivisions(ctx context.Context, estateID string) (estate m.Estate, err error) {
+	estate, err = u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.Estate{}, notExist("estate")
+	}
+
+	divisions, err := u.Repo.GetDivisions(ctx, estateID)
+	if err != nil {
+		return m.Estate{}, err
+	}
+	blocks, err := u.Repo.GetBlocks(ctx, estateID)
+	if err != nil {
+		return m.Estate{}, err
+	}
+
+	estate.Divisions = []m.Division{}
+	for _, d := range divisions {
+		d.Blocks = []m.Block{}
+		for _, b := range blocks {
+			if b.DivisionID == d.ID {
+				d.Blocks = append(d.Blocks, b)
+			}
+		}
+		estate.Divisions = append(estate.Divisions, d)
+	}
+	return estate, nil
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_GetEstateDivisions(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	type args struct {
+		ctx      context.Context
+		estateID string
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantEstate m.Estate
+		wantErr    bool
+		repo       repository.RepositoryInterface
+		mockCalls  []func() *gomock.Call
+	}{
+		{
+			name: "when all good, return divisions with their blocks",
+			args: args{ctx: context.Background(), estateID: "aaa"},
+			wantEstate: m.Estate{ID: "aaa", Length: 10, Width: 10, Divisions: []m.Division{
+				{ID: "d1", Blocks: []m.Block{{ID: "b1", DivisionID: "d1"}, {ID: "b3", DivisionID: "d1"}}},
+				{ID: "d2", Blocks: []m.Block{}},
+			}},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return([]m.Division{{ID: "d1"}, {ID: "d2"}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "b1", DivisionID: "d1"}, {ID: "b3", DivisionID: "d1"}}, nil)
+				},
+			},
+		},
+		{
+			name:       "when estate not found, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa"},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name:       "when get blocks return error, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa"},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return(nil, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, errors.New("blocks"))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotEstate, err := u.GetEstateDivisions(tt.args.ctx, tt.args.estateID)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.GetEstateDivisions() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotEstate, tt.wantEstate) {
+				t.Errorf("Usecase.GetEstateDivisions() = %v, want %v", gotEstate, tt.wantEstate)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+const maxMapResolution = 4096
+
+// downsampleEstate groups the plots into square cells so the grid is at most
+// resolution cells wide and tall. A cell gets the average height of its
+// trees; plots with more than one tree only count the first one, and trees
+// off the grid are left out. Only the cells are kept as the trees stream.
+func downsampleEstate(trees treeStream, maxLength int, maxWidth int, resolution int) (m.EstateMap, error) {
+	scale := (max(maxLength, maxWidth) + resolution - 1) / resolution
+	estateMap := m.EstateMap{
+		Columns: (maxLength + scale - 1) / scale,
+		Rows:    (maxWidth + scale - 1) / scale,
+		Scale:   scale,
+	}
+
+	sum := make([]int, estateMap.Columns*estateMap.Rows)
+	count := make([]int, estateMap.Columns*estateMap.Rows)
+	// plots with more than one tree follow each other in the stream
+	var prev m.Point
+	err := trees(func(t m.Tree) error {
+		if t.X < 1 || t.X > maxLength || t.Y < 1 || t.Y > maxWidth {
+			return nil
+		}
+		if (m.Point{X: t.X, Y: t.Y}) == prev {
+			return nil
+		}
+		prev = m.Point{X: t.X, Y: t.Y}
+
+		idx := (t.Y-1)/scale*estateMap.Columns + (t.X-1)/scale
+		sum[idx] += t.Height
+		count[idx]++
+		return nil
+	})
+	if err != nil {
+		return m.EstateMap{}, err
+	}
+
+	estateMap.Heights = make([]int, len(sum))
+	for i := range sum {
+		if count[i] > 0 {
+			estateMap.Heights[i] = (sum[i] + count[i]/2) / count[i]
+		}
+	}
+	return estateMap, nil
+}
+
+// outsideCells marks the cells of the map without any plot inside the estate
+// boundary, or returns nil when the estate has no boundary.
+func outsideCells(estate m.Estate, estateMap m.EstateMap) []bool {
+	if estate.Boundary == nil {
+		return nil
+	}
+	outside := make([]bool, estateMap.Columns*estateMap.Rows)
+	for i := range outside {
+		outside[i] = true
+	}
+	for y := 1; y <= estate.Width; y++ {
+		row := (y - 1) / estateMap.Scale * estateMap.Columns
+		for _, s := range rowSpans(estate, y) {
+			for col := (s[0] - 1) / estateMap.Scale; col <= (s[1]-1)/estateMap.Scale; col++ {
+				outside[row+col] = false
+			}
+		}
+	}
+	return outside
+}
+
+// downsamplePath converts the plots of a path into cell coordinates. When
+// several rows of plots share a row of cells the drone sweeps that row several
+// times, so only where it enters, its far end and where it leaves are kept.
+func downsamplePath(path []m.Point, scale int) []m.Point {
+	cells := []m.Point{}
+	for start := 0; start < len(path); {
+		cellY := (path[start].Y - 1) / scale
+		end := start
+		for end < len(path) && (path[end].Y-1)/scale == cellY {
+			end++
+		}
+
+		first := m.Point{X: (path[start].X - 1) / scale, Y: cellY}
+		last := m.Point{X: (path[end-1].X - 1) / scale, Y: cellY}
+		far := first
+		for _, p := range path[start:end] {
+			cell := m.Point{X: (p.X - 1) / scale, Y: cellY}
+			if abs(cell.X-first.X) > abs(far.X-first.X) {
+				far = cell
+			}
+		}
+
+		for _, cell := range []m.Point{first, far, last} {
+			if len(cells) > 0 && cells[len(cells)-1] == cell {
+				continue
+			}
+			cells = append(cells, cell)
+		}
+		start = end
+	}
+	return cells
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+func (u *Usecase) GetEstateMap(ctx context.Context, estateID string, resolution int, withDronePath bool) (estateMap m.EstateMap, err error) {
+	if resolution < 1 || resolution > maxMapResolution {
+		return m.EstateMap{}, errors.New("map resolution is not in range")
+	}
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.EstateMap{}, notExist("estate")
+	}
+
+	estateMap, err = downsampleEstate(u.repoStream(ctx, estateID), estate.Length, estate.Width, resolution)
+	if err != nil {
+		return m.EstateMap{}, err
+	}
+	estateMap.Outside = outsideCells(estate, estateMap)
+	if withDronePath {
+		estateMap.DronePath = downsamplePath(droneTurns(estate), estateMap.Scale)
+	}
+	return estateMap, nil
+}
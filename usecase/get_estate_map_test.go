@@ -0,0 +1,243 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_downsampleEstate(t *testing.T) {
+	type args struct {
+		trees      []m.Tree
+		maxLength  int
+		maxWidth   int
+		resolution int
+	}
+	tests := []struct {
+		name string
+		args args
+		want m.EstateMap
+	}{
+		{
+			name: "when estate fits the resolution, one cell per plot",
+			args: args{
+				trees:      []m.Tree{{X: 1, Y: 1, Height: 5}, {X: 3, Y: 2, Height: 7}},
+				maxLength:  3,
+				maxWidth:   2,
+				resolution: 10,
+			},
+			want: m.EstateMap{Columns: 3, Rows: 2, Scale: 1, Heights: []int{5, 0, 0, 0, 0, 7}},
+		},
+		{
+			name: "when estate is larger than the resolution, average the cell",
+			args: args{
+				trees: []m.Tree{
+					{X: 1, Y: 1, Height: 4},
+					{X: 2, Y: 2, Height: 7},
+					{X: 2, Y: 2, Height: 30},
+					{X: 5, Y: 3, Height: 10},
+				},
+				maxLength:  5,
+				maxWidth:   3,
+				resolution: 3,
+			},
+			want: m.EstateMap{Columns: 3, Rows: 2, Scale: 2, Heights: []int{6, 0, 0, 0, 0, 10}},
+		},
+		{
+			name: "when a tree is off the grid, leave it out",
+			args: args{
+				trees:      []m.Tree{{X: 2, Y: 1, Height: 5}, {X: 10, Y: 10, Height: 7}},
+				maxLength:  2,
+				maxWidth:   2,
+				resolution: 10,
+			},
+			want: m.EstateMap{Columns: 2, Rows: 2, Scale: 1, Heights: []int{0, 5, 0, 0}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, _ := downsampleEstate(sliceStream(tt.args.trees), tt.args.maxLength, tt.args.maxWidth, tt.args.resolution); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("downsampleEstate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_outsideCells(t *testing.T) {
+	estateMap := m.EstateMap{Columns: 2, Rows: 2, Scale: 2}
+	// triangle covering the north-west corner of a 4 x 4 estate
+	estate := m.Estate{Length: 4, Width: 4, Boundary: []m.Point{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 1, Y: 2}}}
+	want := []bool{false, true, true, true}
+	if got := outsideCells(estate, estateMap); !reflect.DeepEqual(got, want) {
+		t.Errorf("outsideCells() = %v, want %v", got, want)
+	}
+
+	if got := outsideCells(m.Estate{Length: 4, Width: 4}, estateMap); got != nil {
+		t.Errorf("outsideCells() = %v, want nil without a boundary", got)
+	}
+}
+
+func Test_downsamplePath(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []m.Point
+		scale int
+		want  []m.Point
+	}{
+		{
+			name:  "when not downsampled, keep every turn",
+			path:  droneTurns(m.Estate{Length: 3, Width: 2}),
+			scale: 1,
+			want:  []m.Point{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 0, Y: 1}, {X: 2, Y: 1}},
+		},
+		{
+			name:  "when rows share a cell row, keep entry, far end and exit",
+			path:  droneTurns(m.Estate{Length: 4, Width: 4}),
+			scale: 2,
+			want:  []m.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := downsamplePath(tt.path, tt.scale); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("downsamplePath() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUsecase_GetEstateMap(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	type args struct {
+		ctx           context.Context
+		estateID      string
+		resolution    int
+		withDronePath bool
+	}
+	tests := []struct {
+		name          string
+		args          args
+		wantEstateMap m.EstateMap
+		wantErr       bool
+		repo          repository.RepositoryInterface
+		mockCalls     []func() *gomock.Call
+	}{
+		{
+			name: "when all good, return map with drone path",
+			args: args{
+				ctx:           context.Background(),
+				estateID:      "aaa",
+				resolution:    512,
+				withDronePath: true,
+			},
+			wantEstateMap: m.EstateMap{
+				Columns:   2,
+				Rows:      2,
+				Scale:     1,
+				Heights:   []int{5, 0, 0, 3},
+				DronePath: []m.Point{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}},
+			},
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 1, Y: 1, Height: 5}, {X: 2, Y: 2, Height: 3}}, nil))
+				},
+			},
+		},
+		{
+			name: "when resolution not in range, return error",
+			args: args{
+				ctx:        context.Background(),
+				estateID:   "aaa",
+				resolution: 0,
+			},
+			wantEstateMap: m.EstateMap{},
+			wantErr:       true,
+			repo:          mockRepo,
+			mockCalls:     []func() *gomock.Call{},
+		},
+		{
+			name: "when estate not found, return error",
+			args: args{
+				ctx:        context.Background(),
+				estateID:   "aaa",
+				resolution: 512,
+			},
+			wantEstateMap: m.EstateMap{},
+			wantErr:       true,
+			repo:          mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name: "when get estate give error, return error",
+			args: args{
+				ctx:        context.Background(),
+				estateID:   "aaa",
+				resolution: 512,
+			},
+			wantEstateMap: m.EstateMap{},
+			wantErr:       true,
+			repo:          mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, errors.New("estate"))
+				},
+			},
+		},
+		{
+			name: "when get tree give error, return error",
+			args: args{
+				ctx:        context.Background(),
+				estateID:   "aaa",
+				resolution: 512,
+			},
+			wantEstateMap: m.EstateMap{},
+			wantErr:       true,
+			repo:          mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, errors.New("tree")))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotEstateMap, err := u.GetEstateMap(tt.args.ctx, tt.args.estateID, tt.args.resolution, tt.args.withDronePath)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.GetEstateMap() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotEstateMap, tt.wantEstateMap) {
+				t.Errorf("Usecase.GetEstateMap() = %v, want %v", gotEstateMap, tt.wantEstateMap)
+			}
+		})
+	}
+}
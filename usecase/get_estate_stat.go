@@ -2,46 +2,93 @@ package usecase
 
 import (
 	"context"
-	"errors"
 	"slices"
 
 	m "github.com/SawitProRecruitment/UserService/types"
 )
 
-func countStat(trees []m.Tree) (stats m.Stats) {
-	height := []int{}
-	for _, t := range trees {
-		height = append(height, t.Height)
+// heightCounts counts the trees of each height, which is all the statistics
+// need however many trees there are.
+type heightCounts map[int]int
+
+func (c heightCounts) stats() (stats m.Stats) {
+	height := make([]int, 0, len(c))
+	for h, n := range c {
+		height = append(height, h)
+		stats.Count += n
+	}
+	if stats.Count == 0 {
+		return stats
 	}
 	slices.Sort(height)
 
-	center_idx := len(height) / 2
-	if len(height)%2 == 1 {
-		stats.Median = height[center_idx]
+	// nth returns the height at index i of the sorted heights of the trees
+	nth := func(i int) int {
+		for _, h := range height {
+			if i < c[h] {
+				return h
+			}
+			i -= c[h]
+		}
+		return 0
+	}
+	center_idx := stats.Count / 2
+	if stats.Count%2 == 1 {
+		stats.Median = nth(center_idx)
 	} else {
-		stats.Median = (height[center_idx-1] + height[center_idx]) / 2
+		stats.Median = (nth(center_idx-1) + nth(center_idx)) / 2
 	}
 
-	stats.Count = len(trees)
-	stats.Max = slices.Max(height)
-	stats.Min = slices.Min(height)
+	stats.Max = height[len(height)-1]
+	stats.Min = height[0]
 	return stats
 }
 
-func (u *Usecase) GetEstateStats(ctx context.Context, estateID string) (stat m.Stats, err error) {
+func countStat(trees []m.Tree) (stats m.Stats) {
+	counts := heightCounts{}
+	for _, t := range trees {
+		counts[t.Height]++
+	}
+	return counts.stats()
+}
+
+// matchTree tells if the tree matches every set field of the filter.
+func matchTree(t m.Tree, filter m.TreeFilter) bool {
+	if filter.Variety != "" && t.Variety != filter.Variety {
+		return false
+	}
+	if filter.Status != "" && t.Status != filter.Status {
+		return false
+	}
+	if filter.PlantedFrom != nil && (t.PlantedAt == nil || t.PlantedAt.Before(*filter.PlantedFrom)) {
+		return false
+	}
+	if filter.PlantedTo != nil && (t.PlantedAt == nil || t.PlantedAt.After(*filter.PlantedTo)) {
+		return false
+	}
+	return true
+}
+
+func (u *Usecase) GetEstateStats(ctx context.Context, estateID string, filter m.TreeFilter) (stat m.Stats, err error) {
 	// check if estate exist
 	estate, err := u.Repo.GetEstateByID(ctx, estateID)
 	if err != nil {
 		return
 	}
 	if estate.ID == "" {
-		return m.Stats{}, errors.New("estate is not exist")
+		return m.Stats{}, notExist("estate")
 	}
 
-	trees, err := u.Repo.GetTree(ctx, estateID)
+	// the trees are counted as they come, without keeping them
+	counts := heightCounts{}
+	err = u.Repo.EachTree(ctx, estateID, func(tree m.Tree) error {
+		if matchTree(tree, filter) {
+			counts[tree.Height]++
+		}
+		return nil
+	})
 	if err != nil {
 		return
 	}
-
-	return countStat(trees), nil
+	return counts.stats(), nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/SawitProRecruitment/UserService/repository"
 	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
@@ -14,9 +15,12 @@ import (
 
 func TestUsecase_GetEstateStats(t *testing.T) {
 	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	from := time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	later := time.Date(2018, 6, 1, 0, 0, 0, 0, time.UTC)
 	type args struct {
 		ctx      context.Context
 		estateID string
+		filter   m.TreeFilter
 	}
 	tests := []struct {
 		name      string
@@ -40,12 +44,54 @@ func TestUsecase_GetEstateStats(t *testing.T) {
 					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
 				},
 				func() *gomock.Call {
-					return mockRepo.EXPECT().GetTree(gomock.Any(), "aaa").Return([]m.Tree{
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{
 						{X: 2, Y: 1, Height: 5},
 						{X: 3, Y: 1, Height: 3},
 						{X: 4, Y: 1, Height: 4},
 						{X: 4, Y: 2, Height: 4},
-					}, nil)
+					}, nil))
+				},
+			},
+		},
+		{
+			name: "when filtered, count only matching trees",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				filter:   m.TreeFilter{Status: m.TreeHealthy, PlantedFrom: &from},
+			},
+			wantStat: m.Stats{Count: 2, Max: 5, Min: 3, Median: 4},
+			wantErr:  false,
+			repo:     mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{
+						{X: 2, Y: 1, Height: 5, Status: m.TreeHealthy, PlantedAt: &from},
+						{X: 3, Y: 1, Height: 3, Status: m.TreeHealthy, PlantedAt: &later},
+						{X: 4, Y: 1, Height: 4, Status: m.TreeHealthy},
+						{X: 4, Y: 2, Height: 4, Status: m.TreeDiseased, PlantedAt: &later},
+					}, nil))
+				},
+			},
+		},
+		{
+			name: "when estate has no tree, return zero stats",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantStat: m.Stats{Count: 0, Max: 0, Min: 0, Median: 0},
+			wantErr:  false,
+			repo:     mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, nil))
 				},
 			},
 		},
@@ -93,7 +139,7 @@ func TestUsecase_GetEstateStats(t *testing.T) {
 					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
 				},
 				func() *gomock.Call {
-					return mockRepo.EXPECT().GetTree(gomock.Any(), "aaa").Return([]m.Tree{}, errors.New("tree"))
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{}, errors.New("tree")))
 				},
 			},
 		},
@@ -109,7 +155,7 @@ func TestUsecase_GetEstateStats(t *testing.T) {
 			u := &Usecase{
 				Repo: tt.repo,
 			}
-			gotStat, err := u.GetEstateStats(tt.args.ctx, tt.args.estateID)
+			gotStat, err := u.GetEstateStats(tt.args.ctx, tt.args.estateID, tt.args.filter)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Usecase.GetEstateStats() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -120,3 +166,23 @@ func TestUsecase_GetEstateStats(t *testing.T) {
 		})
 	}
 }
+
+func TestCountStat(t *testing.T) {
+	tests := []struct {
+		heights []int
+		want    m.Stats
+	}{
+		{heights: nil, want: m.Stats{}},
+		{heights: []int{7, 3, 3, 9, 3}, want: m.Stats{Count: 5, Max: 9, Min: 3, Median: 3}},
+		{heights: []int{10, 3, 4, 4, 10, 12}, want: m.Stats{Count: 6, Max: 12, Min: 3, Median: 7}},
+	}
+	for _, tt := range tests {
+		trees := []m.Tree{}
+		for _, h := range tt.heights {
+			trees = append(trees, m.Tree{Height: h})
+		}
+		if got := countStat(trees); got != tt.want {
+			t.Errorf("countStat(%v) = %+v, want %+v", tt.heights, got, tt.want)
+		}
+	}
+}
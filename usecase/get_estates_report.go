@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+var reportSortKeys = map[string]func(r m.EstateReport) float64{
+	"count":    func(r m.EstateReport) float64 { return float64(r.Count) },
+	"density":  func(r m.EstateReport) float64 { return r.Density },
+	"max":      func(r m.EstateReport) float64 { return float64(r.Max) },
+	"min":      func(r m.EstateReport) float64 { return float64(r.Min) },
+	"median":   func(r m.EstateReport) float64 { return float64(r.Median) },
+	"distance": func(r m.EstateReport) float64 { return float64(r.DroneDistance) },
+}
+
+// estateTally counts the heights of the trees added to an estate as the
+// drone flies over them.
+type estateTally struct {
+	estate m.Estate
+	counts heightCounts
+	meter  *distanceMeter
+}
+
+func newEstateTally(estate m.Estate) *estateTally {
+	return &estateTally{estate: estate, counts: heightCounts{}, meter: newDistanceMeter(estate)}
+}
+
+// add reads the next tree of the estate, ordered by row then column.
+func (t *estateTally) add(tree m.Tree) {
+	t.counts[tree.Height]++
+	t.meter.add(tree)
+}
+
+// report lands the drone and builds the report of the estate.
+func (t *estateTally) report() m.EstateReport {
+	stat := t.counts.stats()
+	return m.EstateReport{
+		EstateID:      t.estate.ID,
+		Length:        t.estate.Length,
+		Width:         t.estate.Width,
+		Count:         stat.Count,
+		Density:       float64(stat.Count) / float64(t.estate.Length*t.estate.Width),
+		Max:           stat.Max,
+		Min:           stat.Min,
+		Median:        stat.Median,
+		DroneDistance: t.meter.distance(),
+	}
+}
+
+func matchReportFilter(report m.EstateReport, filter m.EstateReportFilter) bool {
+	if filter.MinCount != nil && report.Count < *filter.MinCount {
+		return false
+	}
+	if filter.MaxCount != nil && report.Count > *filter.MaxCount {
+		return false
+	}
+	if filter.MinDensity != nil && report.Density < *filter.MinDensity {
+		return false
+	}
+	if filter.MaxDensity != nil && report.Density > *filter.MaxDensity {
+		return false
+	}
+	return true
+}
+
+// GetEstatesReport builds the comparison report of every estate. The trees of
+// all the estates are streamed in a single query and split by estate, so only
+// a row of them is held at once.
+func (u *Usecase) GetEstatesReport(ctx context.Context, filter m.EstateReportFilter) (reports []m.EstateReport, err error) {
+	sortKey, ok := reportSortKeys[filter.SortBy]
+	if filter.SortBy != "" && !ok {
+		return nil, errors.New("sort field is not supported")
+	}
+	if filter.Order != "" && filter.Order != "asc" && filter.Order != "desc" {
+		return nil, errors.New("sort order is not supported")
+	}
+
+	estates, err := u.Repo.ListEstates(ctx)
+	if err != nil {
+		return
+	}
+	if len(estates) == 0 {
+		return []m.EstateReport{}, nil
+	}
+	ids := make([]string, len(estates))
+	tallies := make(map[string]*estateTally, len(estates))
+	for i, estate := range estates {
+		ids[i] = estate.ID
+		tallies[estate.ID] = newEstateTally(estate)
+	}
+	err = u.Repo.EachTreeOfEstates(ctx, ids, func(estateID string, tree m.Tree) error {
+		if tally, ok := tallies[estateID]; ok {
+			tally.add(tree)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reports = []m.EstateReport{}
+	for _, estate := range estates {
+		if report := tallies[estate.ID].report(); matchReportFilter(report, filter) {
+			reports = append(reports, report)
+		}
+	}
+
+	if sortKey != nil {
+		slices.SortStableFunc(reports, func(a, b m.EstateReport) int {
+			if filter.Order == "desc" {
+				return cmp.Compare(sortKey(b), sortKey(a))
+			}
+			return cmp.Compare(sortKey(a), sortKey(b))
+		})
+	}
+	return reports, nil
+}
@@ -0,0 +1,205 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_GetEstatesReport(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	minCount := 1
+	maxDensity := 0.5
+
+	estates := []m.Estate{
+		{ID: "aaa", Length: 5, Width: 1},
+		{ID: "bbb", Length: 2, Width: 2},
+		{ID: "ccc", Length: 1, Width: 1},
+	}
+	trees := map[string][]m.Tree{
+		"aaa": {
+			{X: 2, Y: 1, Height: 5},
+			{X: 3, Y: 1, Height: 3},
+			{X: 4, Y: 1, Height: 4},
+		},
+		"bbb": {
+			{X: 1, Y: 1, Height: 10},
+			{X: 2, Y: 2, Height: 20},
+		},
+	}
+	// the estates stream their trees one estate after the other
+	eachEstateTree := func(ctx context.Context, estateIDs []string, fn func(estateID string, tree m.Tree) error) error {
+		for _, id := range estateIDs {
+			err := sliceStream(trees[id])(func(tree m.Tree) error { return fn(id, tree) })
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	aaa := m.EstateReport{EstateID: "aaa", Length: 5, Width: 1, Count: 3, Density: 0.6, Max: 5, Min: 3, Median: 4, DroneDistance: 54}
+	bbb := m.EstateReport{EstateID: "bbb", Length: 2, Width: 2, Count: 2, Density: 0.5, Max: 20, Min: 10, Median: 15, DroneDistance: 72}
+	ccc := m.EstateReport{EstateID: "ccc", Length: 1, Width: 1, Count: 0, Density: 0, Max: 0, Min: 0, Median: 0, DroneDistance: 0}
+
+	type args struct {
+		ctx    context.Context
+		filter m.EstateReportFilter
+	}
+	tests := []struct {
+		name        string
+		args        args
+		wantReports []m.EstateReport
+		wantErr     bool
+		repo        repository.RepositoryInterface
+		mockCalls   []func() *gomock.Call
+	}{
+		{
+			name: "when all good, return report of every estate",
+			args: args{
+				ctx: context.Background(),
+			},
+			wantReports: []m.EstateReport{aaa, bbb, ccc},
+			wantErr:     false,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().ListEstates(gomock.Any()).Return(estates, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTreeOfEstates(gomock.Any(), []string{"aaa", "bbb", "ccc"}, gomock.Any()).DoAndReturn(eachEstateTree)
+				},
+			},
+		},
+		{
+			name: "when sorted by distance descending, return sorted report",
+			args: args{
+				ctx:    context.Background(),
+				filter: m.EstateReportFilter{SortBy: "distance", Order: "desc"},
+			},
+			wantReports: []m.EstateReport{bbb, aaa, ccc},
+			wantErr:     false,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().ListEstates(gomock.Any()).Return(estates, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTreeOfEstates(gomock.Any(), []string{"aaa", "bbb", "ccc"}, gomock.Any()).DoAndReturn(eachEstateTree)
+				},
+			},
+		},
+		{
+			name: "when filtered, return matching estates only",
+			args: args{
+				ctx:    context.Background(),
+				filter: m.EstateReportFilter{SortBy: "median", MinCount: &minCount, MaxDensity: &maxDensity},
+			},
+			wantReports: []m.EstateReport{bbb},
+			wantErr:     false,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().ListEstates(gomock.Any()).Return(estates, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTreeOfEstates(gomock.Any(), []string{"aaa", "bbb", "ccc"}, gomock.Any()).DoAndReturn(eachEstateTree)
+				},
+			},
+		},
+		{
+			name: "when there is no estate, return empty report",
+			args: args{
+				ctx: context.Background(),
+			},
+			wantReports: []m.EstateReport{},
+			wantErr:     false,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().ListEstates(gomock.Any()).Return(nil, nil)
+				},
+			},
+		},
+		{
+			name: "when sort field not supported, return error",
+			args: args{
+				ctx:    context.Background(),
+				filter: m.EstateReportFilter{SortBy: "height"},
+			},
+			wantReports: nil,
+			wantErr:     true,
+			repo:        mockRepo,
+			mockCalls:   []func() *gomock.Call{},
+		},
+		{
+			name: "when sort order not supported, return error",
+			args: args{
+				ctx:    context.Background(),
+				filter: m.EstateReportFilter{SortBy: "count", Order: "up"},
+			},
+			wantReports: nil,
+			wantErr:     true,
+			repo:        mockRepo,
+			mockCalls:   []func() *gomock.Call{},
+		},
+		{
+			name: "when list estates give error, return error",
+			args: args{
+				ctx: context.Background(),
+			},
+			wantReports: nil,
+			wantErr:     true,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().ListEstates(gomock.Any()).Return(nil, errors.New("estate"))
+				},
+			},
+		},
+		{
+			name: "when get tree give error, return error",
+			args: args{
+				ctx: context.Background(),
+			},
+			wantReports: nil,
+			wantErr:     true,
+			repo:        mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().ListEstates(gomock.Any()).Return(estates, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTreeOfEstates(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("tree"))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotReports, err := u.GetEstatesReport(tt.args.ctx, tt.args.filter)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.GetEstatesReport() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotReports, tt.wantReports) {
+				t.Errorf("Usecase.GetEstatesReport() = %v, want %v", gotReports, tt.wantReports)
+			}
+		})
+	}
+}
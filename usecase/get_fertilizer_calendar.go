@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// maxCalendarDays bounds the period of a fertilizer calendar.
+const maxCalendarDays = 366
+
+// GetFertilizerCalendar returns the applications due from today for the given
+// number of days, today included, with the quantities needed for the living
+// trees planted now. A
+// program is next due one interval after its last application, or on its
+// start day when never applied. An overdue application is listed first and
+// the following ones are counted from today.
+func (u *Usecase) GetFertilizerCalendar(ctx context.Context, estateID string, days int) (calendar m.FertilizerCalendar, err error) {
+	if days < 1 || days > maxCalendarDays {
+		return m.FertilizerCalendar{}, errors.New("calendar period is not in range")
+	}
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.FertilizerCalendar{}, notExist("estate")
+	}
+
+	programs, err := u.Repo.GetFertilizerPrograms(ctx, estateID)
+	if err != nil {
+		return
+	}
+	applications, err := u.Repo.GetFertilizerApplications(ctx, estateID)
+	if err != nil {
+		return
+	}
+	lastApplied := map[string]time.Time{}
+	for _, a := range applications {
+		if a.AppliedOn.After(lastApplied[a.ProgramID]) {
+			lastApplied[a.ProgramID] = a.AppliedOn
+		}
+	}
+	counts, err := countBlocks(u.repoStream(ctx, estateID))
+	if err != nil {
+		return
+	}
+
+	today := dateOf(time.Now())
+	end := today.AddDate(0, 0, days)
+	materials := map[string]float64{}
+	calendar.Due = []m.FertilizerDue{}
+	for _, p := range programs {
+		count := counts.scope(p.BlockID)
+		due := m.FertilizerDue{ProgramID: p.ID, BlockID: p.BlockID, Product: p.Product, Trees: count, Quantity: float64(count) * p.Dose}
+
+		next := p.StartOn
+		if last, ok := lastApplied[p.ID]; ok {
+			next = last.AddDate(0, 0, p.Interval)
+		}
+		if next.Before(today) {
+			due.DueOn, due.Overdue = next, true
+			calendar.Due = append(calendar.Due, due)
+			materials[p.Product] += due.Quantity
+			next = today.AddDate(0, 0, p.Interval)
+		}
+		due.Overdue = false
+		for ; next.Before(end); next = next.AddDate(0, 0, p.Interval) {
+			due.DueOn = next
+			calendar.Due = append(calendar.Due, due)
+			materials[p.Product] += due.Quantity
+		}
+	}
+	slices.SortStableFunc(calendar.Due, func(a, b m.FertilizerDue) int {
+		if c := a.DueOn.Compare(b.DueOn); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Product, b.Product)
+	})
+
+	calendar.Materials = []m.Material{}
+	for product, quantity := range materials {
+		calendar.Materials = append(calendar.Materials, m.Material{Product: product, Quantity: quantity})
+	}
+	slices.SortFunc(calendar.Materials, func(a, b m.Material) int { return cmp.Compare(a.Product, b.Product) })
+	return calendar, nil
+}
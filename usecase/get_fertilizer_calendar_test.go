@@ -0,0 +1,57 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_GetFertilizerCalendar(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+	today := dateOf(time.Now())
+
+	programs := []m.FertilizerProgram{
+		// applied 20 days ago, due again in 10 then 40 days
+		{ID: "p1", EstateID: "aaa", BlockID: "b1", Product: "NPK", Dose: 1.5, Interval: 30, StartOn: today.AddDate(0, -6, 0)},
+		// never applied and started 5 days ago: overdue, then in 60 days, the
+		// day after the period
+		{ID: "p2", EstateID: "aaa", Product: "Urea", Dose: 0.5, Interval: 60, StartOn: today.AddDate(0, 0, -5)},
+		// starts after the period
+		{ID: "p3", EstateID: "aaa", Product: "Borate", Dose: 0.1, Interval: 180, StartOn: today.AddDate(0, 0, 90)},
+	}
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetFertilizerPrograms(gomock.Any(), "aaa").Return(programs, nil)
+	mockRepo.EXPECT().GetFertilizerApplications(gomock.Any(), "aaa").Return([]m.FertilizerApplication{
+		{ProgramID: "p1", AppliedOn: today.AddDate(0, 0, -50)},
+		{ProgramID: "p1", AppliedOn: today.AddDate(0, 0, -20)},
+	}, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 1, Y: 1, BlockID: "b1"}, {X: 2, Y: 1, BlockID: "b1"}, {X: 5, Y: 5}, {X: 4, Y: 5}, {X: 3, Y: 1, BlockID: "b1", Status: m.TreeDead}, {X: 3, Y: 5, Status: m.TreeReplanted}}, nil))
+
+	want := m.FertilizerCalendar{
+		Due: []m.FertilizerDue{
+			{ProgramID: "p2", Product: "Urea", DueOn: today.AddDate(0, 0, -5), Overdue: true, Trees: 4, Quantity: 2},
+			{ProgramID: "p1", BlockID: "b1", Product: "NPK", DueOn: today.AddDate(0, 0, 10), Trees: 2, Quantity: 3},
+			{ProgramID: "p1", BlockID: "b1", Product: "NPK", DueOn: today.AddDate(0, 0, 40), Trees: 2, Quantity: 3},
+		},
+		Materials: []m.Material{{Product: "NPK", Quantity: 6}, {Product: "Urea", Quantity: 2}},
+	}
+	got, err := u.GetFertilizerCalendar(context.Background(), "aaa", 60)
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Usecase.GetFertilizerCalendar() = %+v, %v, want %+v", got, err, want)
+	}
+
+	if _, err := u.GetFertilizerCalendar(context.Background(), "aaa", 0); err == nil {
+		t.Errorf("Usecase.GetFertilizerCalendar() expected error for empty period")
+	}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+	if _, err := u.GetFertilizerCalendar(context.Background(), "aaa", 30); err == nil {
+		t.Errorf("Usecase.GetFertilizerCalendar() expected error for unknown estate")
+	}
+}
@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"context"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// harvestRoutes walks the trees in the order the drone flies over them and
+// cuts the walk into one stretch per team, so every team has the same number
+// of trees give or take one. The trees are read along the flight, so only
+// their plots are kept.
+func harvestRoutes(trees treeStream, estate m.Estate, teams int) ([]m.HarvestRoute, error) {
+	walk := []m.Point{}
+	err := flySpans(trees, estate, func(s flightSpan, heights map[int]int) {
+		s.walk(func(x int) {
+			if _, ok := heights[x]; ok {
+				walk = append(walk, m.Point{X: x, Y: s.y})
+			}
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	routes := make([]m.HarvestRoute, teams)
+	start := 0
+	for i := range routes {
+		size := len(walk) / teams
+		if i < len(walk)%teams {
+			size++
+		}
+		routes[i] = m.HarvestRoute{Team: i + 1, Trees: walk[start : start+size]}
+		start += size
+	}
+	return routes, nil
+}
+
+// GetHarvestPlan returns the route of every team of the harvest round.
+func (u *Usecase) GetHarvestPlan(ctx context.Context, estateID string, roundID string) (routes []m.HarvestRoute, err error) {
+	rounds, err := u.Repo.GetHarvestRounds(ctx, estateID)
+	if err != nil {
+		return
+	}
+	var round m.HarvestRound
+	for _, r := range rounds {
+		if r.ID == roundID {
+			round = r
+		}
+	}
+	if round.ID == "" {
+		return nil, notExist("harvest round")
+	}
+
+	if round.BlockID == "" {
+		estate, err := u.Repo.GetEstateByID(ctx, estateID)
+		if err != nil {
+			return nil, err
+		}
+		if estate.ID == "" {
+			return nil, notExist("estate")
+		}
+		return harvestRoutes(u.repoStream(ctx, estateID), estate, round.Teams)
+	}
+
+	estate, block, err := u.getBlock(ctx, estateID, round.BlockID)
+	if err != nil {
+		return
+	}
+
+	clipped, blockTrees := clipToBlock(estate, u.repoStream(ctx, estateID), block)
+	routes, err = harvestRoutes(blockTrees, clipped, round.Teams)
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range routes {
+		for i := range r.Trees {
+			r.Trees[i].X += block.Extent.XMin - 1
+			r.Trees[i].Y += block.Extent.YMin - 1
+		}
+	}
+	return routes, nil
+}
@@ -0,0 +1,66 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestHarvestRoutes(t *testing.T) {
+	estate := m.Estate{Length: 3, Width: 2}
+	trees := []m.Tree{{X: 1, Y: 1}, {X: 2, Y: 1}, {X: 3, Y: 1}, {X: 1, Y: 2}, {X: 2, Y: 2}, {X: 3, Y: 2}}
+
+	want := []m.HarvestRoute{
+		{Team: 1, Trees: []m.Point{{X: 1, Y: 1}, {X: 2, Y: 1}}},
+		{Team: 2, Trees: []m.Point{{X: 3, Y: 1}, {X: 1, Y: 2}}},
+		{Team: 3, Trees: []m.Point{{X: 2, Y: 2}}},
+		{Team: 4, Trees: []m.Point{{X: 3, Y: 2}}},
+	}
+	if got, _ := harvestRoutes(sliceStream(trees), estate, 4); !reflect.DeepEqual(got, want) {
+		t.Errorf("harvestRoutes() = %v, want %v", got, want)
+	}
+
+	// more teams than trees
+	got, _ := harvestRoutes(sliceStream(trees[:1]), estate, 2)
+	if len(got) != 2 || len(got[0].Trees) != 1 || len(got[1].Trees) != 0 {
+		t.Errorf("harvestRoutes() = %v, want one tree for the first team only", got)
+	}
+}
+
+func TestUsecase_GetHarvestPlan(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+	rounds := []m.HarvestRound{{ID: "r1", EstateID: "aaa", Teams: 1}, {ID: "r2", EstateID: "aaa", BlockID: "b1", Teams: 2}}
+	estate := m.Estate{ID: "aaa", Length: 5, Width: 5}
+	trees := []m.Tree{{X: 1, Y: 1}, {X: 4, Y: 4, BlockID: "b1"}, {X: 5, Y: 4, BlockID: "b1"}, {X: 4, Y: 5, BlockID: "b1"}}
+
+	mockRepo.EXPECT().GetHarvestRounds(gomock.Any(), "aaa").Return(rounds, nil)
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil))
+	want := []m.HarvestRoute{{Team: 1, Trees: []m.Point{{X: 1, Y: 1}, {X: 4, Y: 4}, {X: 5, Y: 4}, {X: 4, Y: 5}}}}
+	if got, err := u.GetHarvestPlan(context.Background(), "aaa", "r1"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Usecase.GetHarvestPlan() = %v, %v, want %v", got, err, want)
+	}
+
+	// block round, in estate coordinates
+	mockRepo.EXPECT().GetHarvestRounds(gomock.Any(), "aaa").Return(rounds, nil)
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil)
+	mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "b1", Extent: m.Extent{XMin: 4, YMin: 4, XMax: 5, YMax: 5}}}, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil))
+	want = []m.HarvestRoute{
+		{Team: 1, Trees: []m.Point{{X: 4, Y: 4}, {X: 5, Y: 4}}},
+		{Team: 2, Trees: []m.Point{{X: 4, Y: 5}}},
+	}
+	if got, err := u.GetHarvestPlan(context.Background(), "aaa", "r2"); err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Usecase.GetHarvestPlan() for block = %v, %v, want %v", got, err, want)
+	}
+
+	mockRepo.EXPECT().GetHarvestRounds(gomock.Any(), "aaa").Return(rounds, nil)
+	if _, err := u.GetHarvestPlan(context.Background(), "aaa", "r3"); err == nil {
+		t.Errorf("Usecase.GetHarvestPlan() expected error for unknown round")
+	}
+}
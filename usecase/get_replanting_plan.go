@@ -0,0 +1,141 @@
+package usecase
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+const (
+	// defaultReplantingAge is the age in years after which a palm yields too
+	// little and is replanted.
+	defaultReplantingAge = 25
+	// defaultReplantingShare replants a 25th of the estate a year, which
+	// renews it over one lifetime of a palm.
+	defaultReplantingShare = 4
+)
+
+// treeAge returns the age of the tree in whole years at now, from its
+// planting date or else from the planting year of the estate, 0 when
+// unknown.
+func treeAge(t m.Tree, estate m.Estate, now time.Time) int {
+	if t.PlantedAt != nil {
+		age := now.Year() - t.PlantedAt.Year()
+		if now.Month() < t.PlantedAt.Month() || now.Month() == t.PlantedAt.Month() && now.Day() < t.PlantedAt.Day() {
+			age--
+		}
+		return max(age, 0)
+	}
+	if estate.PlantingYear > 0 {
+		return max(now.Year()-estate.PlantingYear, 0)
+	}
+	return 0
+}
+
+// GetReplantingPlan lists the dead trees and the trees older than the maximum
+// age and spreads their replanting over the years so that no more than the
+// maximum share of the estate is replanted a year, but at least one tree so
+// that small estates are renewed too. Dead trees go first, then the oldest.
+func (u *Usecase) GetReplantingPlan(ctx context.Context, estateID string, options m.ReplantingOptions) (plan m.ReplantingPlan, err error) {
+	now := time.Now()
+	if options.MaxAge == 0 {
+		options.MaxAge = defaultReplantingAge
+	}
+	if options.MaxShare == 0 {
+		options.MaxShare = defaultReplantingShare
+	}
+	if options.StartYear == 0 {
+		options.StartYear = now.Year()
+	}
+	if options.MaxAge < 1 {
+		return m.ReplantingPlan{}, errors.New("maximum age must be positive")
+	}
+	if options.MaxShare < 0 || options.MaxShare > 100 {
+		return m.ReplantingPlan{}, errors.New("replanting share is not in range")
+	}
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.ReplantingPlan{}, notExist("estate")
+	}
+
+	blocks, err := u.Repo.GetBlocks(ctx, estateID)
+	if err != nil {
+		return
+	}
+
+	count := 0
+	plan.Candidates = []m.ReplantingCandidate{}
+	workload := map[string]*m.BlockWorkload{}
+	err = u.Repo.EachTree(ctx, estateID, func(t m.Tree) error {
+		count++
+		if workload[t.BlockID] == nil {
+			workload[t.BlockID] = &m.BlockWorkload{BlockID: t.BlockID}
+		}
+		workload[t.BlockID].Trees++
+
+		age := treeAge(t, estate, now)
+		if t.Status != m.TreeDead && age <= options.MaxAge {
+			return nil
+		}
+		workload[t.BlockID].Candidates++
+		plan.Candidates = append(plan.Candidates, m.ReplantingCandidate{X: t.X, Y: t.Y, BlockID: t.BlockID, Status: t.Status, Age: age})
+		return nil
+	})
+	if err != nil {
+		return m.ReplantingPlan{}, err
+	}
+	plan.AnnualLimit = int(float64(count) * options.MaxShare / 100)
+	if len(plan.Candidates) > 0 {
+		plan.AnnualLimit = max(plan.AnnualLimit, 1)
+	}
+
+	slices.SortFunc(plan.Candidates, func(a, b m.ReplantingCandidate) int {
+		if (a.Status == m.TreeDead) != (b.Status == m.TreeDead) {
+			if a.Status == m.TreeDead {
+				return -1
+			}
+			return 1
+		}
+		if a.Age != b.Age {
+			return cmp.Compare(b.Age, a.Age)
+		}
+		if a.BlockID != b.BlockID {
+			return cmp.Compare(a.BlockID, b.BlockID)
+		}
+		if a.Y != b.Y {
+			return cmp.Compare(a.Y, b.Y)
+		}
+		return cmp.Compare(a.X, b.X)
+	})
+
+	plan.Phases = []m.ReplantingPhase{}
+	for i := range plan.Candidates {
+		year := options.StartYear + i/plan.AnnualLimit
+		plan.Candidates[i].Year = year
+		if len(plan.Phases) == 0 || plan.Phases[len(plan.Phases)-1].Year != year {
+			plan.Phases = append(plan.Phases, m.ReplantingPhase{Year: year})
+		}
+		plan.Phases[len(plan.Phases)-1].Trees++
+	}
+
+	// blocks in name order, then the trees outside every block
+	plan.Blocks = []m.BlockWorkload{}
+	for _, b := range blocks {
+		if w := workload[b.ID]; w != nil {
+			plan.Blocks = append(plan.Blocks, *w)
+		}
+	}
+	if w := workload[""]; w != nil {
+		plan.Blocks = append(plan.Blocks, *w)
+	}
+	return plan, nil
+}
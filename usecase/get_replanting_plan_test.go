@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_GetReplantingPlan(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+	now := time.Now()
+	planted := func(years int) *time.Time {
+		at := dateOf(now).AddDate(-years, 0, 0)
+		return &at
+	}
+
+	estate := m.Estate{ID: "aaa", Length: 5, Width: 5, PlantingYear: now.Year() - 28}
+	trees := []m.Tree{
+		{X: 1, Y: 1, BlockID: "b1", Status: m.TreeHealthy, PlantedAt: planted(30)},
+		{X: 2, Y: 1, BlockID: "b1", Status: m.TreeDead, PlantedAt: planted(5)},
+		{X: 3, Y: 1, BlockID: "b1", Status: m.TreeHealthy, PlantedAt: planted(10)},
+		// no planting date, as old as the estate
+		{X: 1, Y: 2, Status: m.TreeDiseased},
+		{X: 2, Y: 2, BlockID: "b2", Status: m.TreeHealthy, PlantedAt: planted(1)},
+	}
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil).Times(2)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil)).Times(2)
+	mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "b1"}, {ID: "b2"}}, nil).Times(2)
+
+	want := m.ReplantingPlan{
+		Candidates: []m.ReplantingCandidate{
+			{X: 2, Y: 1, BlockID: "b1", Status: m.TreeDead, Age: 5, Year: 2030},
+			{X: 1, Y: 1, BlockID: "b1", Status: m.TreeHealthy, Age: 30, Year: 2030},
+			{X: 1, Y: 2, Status: m.TreeDiseased, Age: 28, Year: 2031},
+		},
+		Blocks: []m.BlockWorkload{
+			{BlockID: "b1", Trees: 3, Candidates: 2},
+			{BlockID: "b2", Trees: 1},
+			{Trees: 1, Candidates: 1},
+		},
+		Phases:      []m.ReplantingPhase{{Year: 2030, Trees: 2}, {Year: 2031, Trees: 1}},
+		AnnualLimit: 2,
+	}
+	got, err := u.GetReplantingPlan(context.Background(), "aaa", m.ReplantingOptions{MaxShare: 40, StartYear: 2030})
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Usecase.GetReplantingPlan() = %+v, %v, want %+v", got, err, want)
+	}
+
+	// a share of less than one tree a year still replants one
+	got, err = u.GetReplantingPlan(context.Background(), "aaa", m.ReplantingOptions{MaxShare: 10, StartYear: 2030})
+	if err != nil || got.AnnualLimit != 1 || !reflect.DeepEqual(got.Phases, []m.ReplantingPhase{{Year: 2030, Trees: 1}, {Year: 2031, Trees: 1}, {Year: 2032, Trees: 1}}) {
+		t.Errorf("Usecase.GetReplantingPlan() = %+v, %v, want one tree a year", got, err)
+	}
+
+	if _, err := u.GetReplantingPlan(context.Background(), "aaa", m.ReplantingOptions{MaxShare: 150}); err == nil {
+		t.Errorf("Usecase.GetReplantingPlan() expected error for share out of range")
+	}
+	if _, err := u.GetReplantingPlan(context.Background(), "aaa", m.ReplantingOptions{MaxAge: -1}); err == nil {
+		t.Errorf("Usecase.GetReplantingPlan() expected error for negative age")
+	}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+	if _, err := u.GetReplantingPlan(context.Background(), "aaa", m.ReplantingOptions{}); err == nil {
+		t.Errorf("Usecase.GetReplantingPlan() expected error for unknown estate")
+	}
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// infectionGrid buckets the infected plots into square cells as wide as the
+// radius, so that the plots within the radius of any plot lie in the cell of
+// that plot or in one of the eight around it.
+type infectionGrid struct {
+	radius int
+	cells  map[m.Point][]m.Point
+}
+
+func (g infectionGrid) cell(p m.Point) m.Point {
+	return m.Point{X: (p.X - 1) / g.radius, Y: (p.Y - 1) / g.radius}
+}
+
+func (g infectionGrid) add(p m.Point) {
+	c := g.cell(p)
+	g.cells[c] = append(g.cells[c], p)
+}
+
+// near tells if an infected plot lies within the radius of p.
+func (g infectionGrid) near(p m.Point) bool {
+	c := g.cell(p)
+	for y := c.Y - 1; y <= c.Y+1; y++ {
+		for x := c.X - 1; x <= c.X+1; x++ {
+			for _, q := range g.cells[m.Point{X: x, Y: y}] {
+				if max(abs(q.X-p.X), abs(q.Y-p.Y)) <= g.radius {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// GetTreesAtRisk returns the trees within radius plots of a tree with an
+// observation of obsType, or of any type when obsType is empty. Distance is
+// counted on the grid, diagonals included, so a radius of 1 covers the eight
+// neighbouring plots. The infected trees themselves are left out. Trees are
+// ordered by row then column.
+func (u *Usecase) GetTreesAtRisk(ctx context.Context, estateID string, radius int, obsType string) (trees []m.Tree, err error) {
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return nil, notExist("estate")
+	}
+	if radius < 1 || radius > max(estate.Length, estate.Width) {
+		return nil, errors.New("radius is not in range")
+	}
+
+	observations, err := u.Repo.GetObservations(ctx, estateID, m.ObservationFilter{Type: obsType})
+	if err != nil {
+		return
+	}
+	infected := map[m.Point]bool{}
+	grid := infectionGrid{radius: radius, cells: map[m.Point][]m.Point{}}
+	for _, o := range observations {
+		p := m.Point{X: o.X, Y: o.Y}
+		if !infected[p] {
+			infected[p] = true
+			grid.add(p)
+		}
+	}
+
+	trees = []m.Tree{}
+	err = u.Repo.EachTree(ctx, estateID, func(t m.Tree) error {
+		if !infected[m.Point{X: t.X, Y: t.Y}] && grid.near(m.Point{X: t.X, Y: t.Y}) {
+			trees = append(trees, t)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
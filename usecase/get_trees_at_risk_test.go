@@ -0,0 +1,169 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func Test_infectionGrid(t *testing.T) {
+	grid := infectionGrid{radius: 2, cells: map[m.Point][]m.Point{}}
+	grid.add(m.Point{X: 4, Y: 4})
+
+	// the infected plot is found from the cells around it, and only within
+	// the radius
+	for p, want := range map[m.Point]bool{
+		{X: 2, Y: 2}: true,
+		{X: 6, Y: 6}: true,
+		{X: 6, Y: 2}: true,
+		{X: 5, Y: 4}: true,
+		{X: 7, Y: 4}: false,
+		{X: 1, Y: 4}: false,
+		{X: 4, Y: 7}: false,
+	} {
+		if got := grid.near(p); got != want {
+			t.Errorf("infectionGrid.near(%v) = %v, want %v", p, got, want)
+		}
+	}
+}
+
+func TestUsecase_GetTreesAtRisk(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	estate := m.Estate{ID: "aaa", Length: 10, Width: 10}
+	trees := []m.Tree{
+		{X: 5, Y: 5, Height: 5},
+		{X: 6, Y: 6, Height: 5},
+		{X: 4, Y: 5, Height: 5},
+		{X: 7, Y: 5, Height: 5},
+		{X: 1, Y: 1, Height: 5},
+	}
+	type args struct {
+		radius  int
+		obsType string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantTrees []m.Tree
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:      "when radius is one, return the neighbours",
+			args:      args{radius: 1, obsType: "ganoderma"},
+			wantTrees: []m.Tree{{X: 4, Y: 5, Height: 5}, {X: 6, Y: 6, Height: 5}},
+			wantErr:   false,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetObservations(gomock.Any(), "aaa", m.ObservationFilter{Type: "ganoderma"}).Return([]m.Observation{{X: 5, Y: 5}, {X: 5, Y: 5}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil))
+				},
+			},
+		},
+		{
+			name:      "when radius is two, reach further",
+			args:      args{radius: 2},
+			wantTrees: []m.Tree{{X: 4, Y: 5, Height: 5}, {X: 7, Y: 5, Height: 5}, {X: 6, Y: 6, Height: 5}},
+			wantErr:   false,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetObservations(gomock.Any(), "aaa", m.ObservationFilter{}).Return([]m.Observation{{X: 5, Y: 5}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil))
+				},
+			},
+		},
+		{
+			name:      "when nothing is infected, return no tree",
+			args:      args{radius: 3},
+			wantTrees: []m.Tree{},
+			wantErr:   false,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetObservations(gomock.Any(), "aaa", m.ObservationFilter{}).Return(nil, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil))
+				},
+			},
+		},
+		{
+			name:    "when get observations return error, return error",
+			args:    args{radius: 1},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetObservations(gomock.Any(), "aaa", m.ObservationFilter{}).Return(nil, errors.New("observation"))
+				},
+			},
+		},
+		{
+			name:    "when radius is out of range, return error",
+			args:    args{radius: 0},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(estate, nil)
+				},
+			},
+		},
+		{
+			name:    "when estate not found, return error",
+			args:    args{radius: 1},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, call := range tt.mockCalls {
+				call()
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotTrees, err := u.GetTreesAtRisk(context.Background(), "aaa", tt.args.radius, tt.args.obsType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.GetTreesAtRisk() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(gotTrees, tt.wantTrees) {
+				t.Errorf("Usecase.GetTreesAtRisk() = %v, want %v", gotTrees, tt.wantTrees)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"math"
+	"slices"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// heightBand is the range of tree heights grouped together in a yield report.
+const heightBand = 5
+
+// periodStart returns the start of the period holding day. Weeks start on
+// Monday.
+func periodStart(day time.Time, period string) time.Time {
+	switch period {
+	case m.PeriodDay:
+		return day
+	case m.PeriodWeek:
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+}
+
+// correlation returns the Pearson correlation of xs and ys, or 0 when either
+// does not vary.
+func correlation(xs []float64, ys []float64) float64 {
+	n := float64(len(xs))
+	if n < 2 {
+		return 0
+	}
+	var sumX, sumY float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+	}
+	meanX, meanY := sumX/n, sumY/n
+
+	var cov, varX, varY float64
+	for i := range xs {
+		dx, dy := xs[i]-meanX, ys[i]-meanY
+		cov += dx * dy
+		varX += dx * dx
+		varY += dy * dy
+	}
+	if varX == 0 || varY == 0 {
+		return 0
+	}
+	return cov / math.Sqrt(varX*varY)
+}
+
+// GetYieldReport sums the harvests of the estate, or of one of its blocks, by
+// period, by block and by tree height. Harvests are placed in a block by the
+// plot of their tree, and only trees still standing count towards the height
+// figures.
+func (u *Usecase) GetYieldReport(ctx context.Context, estateID string, filter m.YieldFilter) (report m.YieldReport, err error) {
+	if filter.Period == "" {
+		filter.Period = m.PeriodMonth
+	}
+	if filter.Period != m.PeriodDay && filter.Period != m.PeriodWeek && filter.Period != m.PeriodMonth {
+		return m.YieldReport{}, errors.New("period is not valid")
+	}
+	if filter.From != nil && filter.To != nil && filter.From.After(*filter.To) {
+		return m.YieldReport{}, errors.New("period start is after its end")
+	}
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.YieldReport{}, notExist("estate")
+	}
+
+	blocks, err := u.Repo.GetBlocks(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if filter.BlockID != "" && !slices.ContainsFunc(blocks, func(b m.Block) bool { return b.ID == filter.BlockID }) {
+		return m.YieldReport{}, notExist("block")
+	}
+
+	harvests, err := u.Repo.GetHarvests(ctx, estateID, filter.From, filter.To)
+	if err != nil {
+		return
+	}
+	periods := map[time.Time]*m.YieldPeriod{}
+	byBlock := map[string]*m.BlockYield{}
+	byTree := map[m.Point]*m.Harvest{}
+	for _, h := range harvests {
+		blockID := blockAt(blocks, h.X, h.Y)
+		if filter.BlockID != "" && blockID != filter.BlockID {
+			continue
+		}
+		report.Bunches += h.Bunches
+		report.Weight += h.Weight
+
+		start := periodStart(h.HarvestedAt, filter.Period)
+		if periods[start] == nil {
+			periods[start] = &m.YieldPeriod{Start: start}
+		}
+		periods[start].Bunches += h.Bunches
+		periods[start].Weight += h.Weight
+
+		if byBlock[blockID] == nil {
+			byBlock[blockID] = &m.BlockYield{BlockID: blockID}
+		}
+		byBlock[blockID].Bunches += h.Bunches
+		byBlock[blockID].Weight += h.Weight
+
+		p := m.Point{X: h.X, Y: h.Y}
+		if byTree[p] == nil {
+			byTree[p] = &m.Harvest{X: h.X, Y: h.Y}
+		}
+		byTree[p].Bunches += h.Bunches
+		byTree[p].Weight += h.Weight
+	}
+	report.Trees = len(byTree)
+
+	// only the heights of the harvested trees are kept
+	heights := map[m.Point]int{}
+	err = u.Repo.EachTree(ctx, estateID, func(t m.Tree) error {
+		if p := (m.Point{X: t.X, Y: t.Y}); byTree[p] != nil {
+			heights[p] = t.Height
+		}
+		return nil
+	})
+	if err != nil {
+		return m.YieldReport{}, err
+	}
+
+	report.Periods = []m.YieldPeriod{}
+	for _, p := range periods {
+		report.Periods = append(report.Periods, *p)
+	}
+	slices.SortFunc(report.Periods, func(a, b m.YieldPeriod) int { return a.Start.Compare(b.Start) })
+
+	// blocks in name order, then the trees outside every block
+	report.Blocks = []m.BlockYield{}
+	for _, b := range blocks {
+		if y := byBlock[b.ID]; y != nil {
+			report.Blocks = append(report.Blocks, *y)
+		}
+	}
+	if y := byBlock[""]; y != nil {
+		report.Blocks = append(report.Blocks, *y)
+	}
+
+	bands := map[int]*m.HeightYield{}
+	var xs, ys []float64
+	for p, h := range byTree {
+		height, ok := heights[p]
+		if !ok {
+			continue
+		}
+		band := (height - 1) / heightBand
+		if bands[band] == nil {
+			bands[band] = &m.HeightYield{MinHeight: band*heightBand + 1, MaxHeight: (band + 1) * heightBand}
+		}
+		bands[band].Trees++
+		bands[band].Bunches += h.Bunches
+		bands[band].Weight += h.Weight
+		xs = append(xs, float64(height))
+		ys = append(ys, h.Weight)
+	}
+	report.Heights = []m.HeightYield{}
+	for _, b := range bands {
+		report.Heights = append(report.Heights, *b)
+	}
+	slices.SortFunc(report.Heights, func(a, b m.HeightYield) int { return a.MinHeight - b.MinHeight })
+	report.HeightCorrelation = correlation(xs, ys)
+
+	return report, nil
+}
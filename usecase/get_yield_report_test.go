@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"context"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestPeriodStart(t *testing.T) {
+	day := time.Date(2024, 1, 17, 0, 0, 0, 0, time.UTC) // Wednesday
+	tests := map[string]time.Time{
+		m.PeriodDay:   day,
+		m.PeriodWeek:  time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC),
+		m.PeriodMonth: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	for period, want := range tests {
+		if got := periodStart(day, period); !got.Equal(want) {
+			t.Errorf("periodStart(%v) = %v, want %v", period, got, want)
+		}
+	}
+	sunday := time.Date(2024, 1, 21, 0, 0, 0, 0, time.UTC)
+	if got := periodStart(sunday, m.PeriodWeek); !got.Equal(tests[m.PeriodWeek]) {
+		t.Errorf("periodStart(sunday) = %v, want %v", got, tests[m.PeriodWeek])
+	}
+}
+
+func TestCorrelation(t *testing.T) {
+	if got := correlation([]float64{1, 2, 3}, []float64{2, 4, 6}); math.Abs(got-1) > 1e-9 {
+		t.Errorf("correlation() = %v, want 1", got)
+	}
+	if got := correlation([]float64{1, 2, 3}, []float64{6, 4, 2}); math.Abs(got+1) > 1e-9 {
+		t.Errorf("correlation() = %v, want -1", got)
+	}
+	if got := correlation([]float64{1, 1}, []float64{2, 4}); got != 0 {
+		t.Errorf("correlation() = %v, want 0", got)
+	}
+}
+
+func TestUsecase_GetYieldReport(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+
+	jan := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2024, 2, 10, 0, 0, 0, 0, time.UTC)
+	blocks := []m.Block{{ID: "b1", Extent: m.Extent{XMin: 1, YMin: 1, XMax: 2, YMax: 5}}}
+	harvests := []m.Harvest{
+		{X: 1, Y: 1, HarvestedAt: jan, Bunches: 1, Weight: 10},
+		{X: 4, Y: 1, HarvestedAt: jan, Bunches: 2, Weight: 30},
+		{X: 1, Y: 1, HarvestedAt: feb, Bunches: 1, Weight: 10},
+		{X: 5, Y: 5, HarvestedAt: feb, Bunches: 1, Weight: 5},
+	}
+	trees := []m.Tree{{X: 1, Y: 1, Height: 3}, {X: 4, Y: 1, Height: 12}}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(blocks, nil)
+	mockRepo.EXPECT().GetHarvests(gomock.Any(), "aaa", nil, nil).Return(harvests, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil))
+
+	want := m.YieldReport{
+		Bunches: 5, Weight: 55, Trees: 3,
+		Periods: []m.YieldPeriod{
+			{Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), Bunches: 3, Weight: 40},
+			{Start: time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), Bunches: 2, Weight: 15},
+		},
+		Blocks: []m.BlockYield{{BlockID: "b1", Bunches: 2, Weight: 20}, {Bunches: 3, Weight: 35}},
+		Heights: []m.HeightYield{
+			{MinHeight: 1, MaxHeight: 5, Trees: 1, Bunches: 2, Weight: 20},
+			{MinHeight: 11, MaxHeight: 15, Trees: 1, Bunches: 2, Weight: 30},
+		},
+		HeightCorrelation: 1,
+	}
+	got, err := u.GetYieldReport(context.Background(), "aaa", m.YieldFilter{})
+	if err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Usecase.GetYieldReport() = %+v, %v, want %+v", got, err, want)
+	}
+
+	// one block
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(blocks, nil)
+	mockRepo.EXPECT().GetHarvests(gomock.Any(), "aaa", nil, nil).Return(harvests, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(trees, nil))
+
+	got, err = u.GetYieldReport(context.Background(), "aaa", m.YieldFilter{BlockID: "b1", Period: m.PeriodDay})
+	if err != nil || got.Bunches != 2 || got.Trees != 1 || len(got.Periods) != 2 || !got.Periods[0].Start.Equal(jan) {
+		t.Errorf("Usecase.GetYieldReport() for block = %+v, %v", got, err)
+	}
+
+	// unknown block
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(blocks, nil)
+	if _, err := u.GetYieldReport(context.Background(), "aaa", m.YieldFilter{BlockID: "b2"}); err == nil {
+		t.Errorf("Usecase.GetYieldReport() expected error for unknown block")
+	}
+
+	// invalid filters
+	if _, err := u.GetYieldReport(context.Background(), "aaa", m.YieldFilter{Period: "year"}); err == nil {
+		t.Errorf("Usecase.GetYieldReport() expected error for unknown period")
+	}
+	if _, err := u.GetYieldReport(context.Background(), "aaa", m.YieldFilter{From: &feb, To: &jan}); err == nil {
+		t.Errorf("Usecase.GetYieldReport() expected error for reversed range")
+	}
+
+	// unknown estate
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+	if _, err := u.GetYieldReport(context.Background(), "aaa", m.YieldFilter{}); err == nil {
+		t.Errorf("Usecase.GetYieldReport() expected error for unknown estate")
+	}
+}
@@ -0,0 +1,102 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// minRoundInterval is the shortest time between two harvest rounds of the
+// same area. Rounds are due every 7 to 10 days; a late round is accepted so a
+// missed one can be caught up.
+const minRoundInterval = 7 * 24 * time.Hour
+
+// CreateHarvestRound schedules a harvest round of the estate, or of one of its
+// blocks, today when no day is given.
+func (u *Usecase) CreateHarvestRound(ctx context.Context, round m.HarvestRound) (id string, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		id, err = tx.createHarvestRound(ctx, round)
+		return err
+	})
+	if err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (u *Usecase) createHarvestRound(ctx context.Context, round m.HarvestRound) (id string, err error) {
+	if round.Teams < 1 {
+		return "", errors.New("harvest round needs at least one team")
+	}
+	if round.ScheduledOn.IsZero() {
+		round.ScheduledOn = time.Now()
+	}
+	round.ScheduledOn = dateOf(round.ScheduledOn)
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, round.EstateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return "", notExist("estate")
+	}
+	if round.BlockID != "" {
+		blocks, err := u.Repo.GetBlocks(ctx, round.EstateID)
+		if err != nil {
+			return "", err
+		}
+		if !slices.ContainsFunc(blocks, func(b m.Block) bool { return b.ID == round.BlockID }) {
+			return "", notExist("block")
+		}
+	}
+
+	rounds, err := u.Repo.GetHarvestRounds(ctx, round.EstateID)
+	if err != nil {
+		return
+	}
+	for _, r := range rounds {
+		gap := r.ScheduledOn.Sub(round.ScheduledOn)
+		if r.BlockID == round.BlockID && gap < minRoundInterval && gap > -minRoundInterval {
+			return "", errors.New("harvest rounds must be at least 7 days apart")
+		}
+	}
+
+	return u.Repo.CreateHarvestRound(ctx, round)
+}
+
+// ListHarvestRounds returns the harvest rounds of the estate, earliest first.
+func (u *Usecase) ListHarvestRounds(ctx context.Context, estateID string) (rounds []m.HarvestRound, err error) {
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return nil, notExist("estate")
+	}
+
+	rounds, err = u.Repo.GetHarvestRounds(ctx, estateID)
+	if err != nil {
+		return nil, err
+	}
+	if rounds == nil {
+		rounds = []m.HarvestRound{}
+	}
+	return rounds, nil
+}
+
+// CompleteHarvestRound records that every team finished the round.
+func (u *Usecase) CompleteHarvestRound(ctx context.Context, estateID string, roundID string) (err error) {
+	completed, err := u.Repo.CompleteHarvestRound(ctx, estateID, roundID)
+	if err != nil {
+		return
+	}
+	if !completed {
+		return errors.New("harvest round is not exist or already completed")
+	}
+	return nil
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_CreateHarvestRound(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	day := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	previous := []m.HarvestRound{{ID: "r1", EstateID: "aaa", Teams: 2, ScheduledOn: day.AddDate(0, 0, -7)}, {ID: "r2", EstateID: "aaa", BlockID: "b1", Teams: 1, ScheduledOn: day.AddDate(0, 0, -3)}}
+	tests := []struct {
+		name      string
+		round     m.HarvestRound
+		wantId    string
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name:    "when a week after the previous round, return id",
+			round:   m.HarvestRound{EstateID: "aaa", Teams: 3, ScheduledOn: day.Add(8 * time.Hour)},
+			wantId:  "rrr",
+			wantErr: false,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetHarvestRounds(gomock.Any(), "aaa").Return(previous, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().CreateHarvestRound(gomock.Any(), m.HarvestRound{EstateID: "aaa", Teams: 3, ScheduledOn: day}).Return("rrr", nil)
+				},
+			},
+		},
+		{
+			name:    "when too close to the previous round of the block, return error",
+			round:   m.HarvestRound{EstateID: "aaa", BlockID: "b1", Teams: 1, ScheduledOn: day},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "b1"}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetHarvestRounds(gomock.Any(), "aaa").Return(previous, nil)
+				},
+			},
+		},
+		{
+			name:    "when block not found, return error",
+			round:   m.HarvestRound{EstateID: "aaa", BlockID: "b2", Teams: 1, ScheduledOn: day},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return([]m.Block{{ID: "b1"}}, nil)
+				},
+			},
+		},
+		{
+			name:    "when estate not found, return error",
+			round:   m.HarvestRound{EstateID: "aaa", Teams: 1, ScheduledOn: day},
+			wantErr: true,
+			repo:    mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name:    "when there is no team, return error",
+			round:   m.HarvestRound{EstateID: "aaa", ScheduledOn: day},
+			wantErr: true,
+			repo:    mockRepo,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, call := range tt.mockCalls {
+				call()
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotId, err := u.CreateHarvestRound(context.Background(), tt.round)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.CreateHarvestRound() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if gotId != tt.wantId {
+				t.Errorf("Usecase.CreateHarvestRound() = %v, want %v", gotId, tt.wantId)
+			}
+		})
+	}
+}
+
+func TestUsecase_ListHarvestRounds(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetHarvestRounds(gomock.Any(), "aaa").Return(nil, nil)
+	if got, err := u.ListHarvestRounds(context.Background(), "aaa"); err != nil || !reflect.DeepEqual(got, []m.HarvestRound{}) {
+		t.Errorf("Usecase.ListHarvestRounds() = %v, %v, want empty list", got, err)
+	}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+	if _, err := u.ListHarvestRounds(context.Background(), "aaa"); err == nil {
+		t.Errorf("Usecase.ListHarvestRounds() expected error for unknown estate")
+	}
+}
+
+func TestUsecase_CompleteHarvestRound(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+
+	mockRepo.EXPECT().CompleteHarvestRound(gomock.Any(), "aaa", "rrr").Return(true, nil)
+	if err := u.CompleteHarvestRound(context.Background(), "aaa", "rrr"); err != nil {
+		t.Errorf("Usecase.CompleteHarvestRound() error = %v", err)
+	}
+
+	mockRepo.EXPECT().CompleteHarvestRound(gomock.Any(), "aaa", "rrr").Return(false, nil)
+	if err := u.CompleteHarvestRound(context.Background(), "aaa", "rrr"); err == nil {
+		t.Errorf("Usecase.CompleteHarvestRound() expected error for completed round")
+	}
+
+	mockRepo.EXPECT().CompleteHarvestRound(gomock.Any(), "aaa", "rrr").Return(false, errors.New("round"))
+	if err := u.CompleteHarvestRound(context.Background(), "aaa", "rrr"); err == nil {
+		t.Errorf("Usecase.CompleteHarvestRound() expected error")
+	}
+}
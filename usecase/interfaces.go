@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"time"
 
 	m "github.com/SawitProRecruitment/UserService/types"
 )
@@ -9,9 +10,46 @@ import (
 //go:generate mockgen --build_flags=--mod=mod -destination=mock/interfaces.mock.gen.go -package=usecase . UsecaseInterface
 type UsecaseInterface interface {
 	GetEstateByID(ctx context.Context, id string) (estate m.Estate, err error)
-	CreateEstate(ctx context.Context, length int, width int) (id string, err error)
+	CreateEstate(ctx context.Context, estate m.Estate) (id string, err error)
+	UpdateEstate(ctx context.Context, estateID string, update m.EstateUpdate) (estate m.Estate, err error)
+	DeleteEstate(ctx context.Context, estateID string) (err error)
+	RestoreEstate(ctx context.Context, estateID string) (err error)
+	SearchEstates(ctx context.Context, search m.EstateSearch, includeDeleted bool) (estates []m.Estate, err error)
 	CreateTree(ctx context.Context, estateID string, tree m.Tree) (id string, err error)
+	DeleteTree(ctx context.Context, estateID string, x int, y int) (err error)
+	RestoreTree(ctx context.Context, estateID string, x int, y int) (err error)
 
-	GetEstateStats(ctx context.Context, estateID string) (stat m.Stats, err error)
+	GetEstateStats(ctx context.Context, estateID string, filter m.TreeFilter) (stat m.Stats, err error)
 	GetDroneDistance(ctx context.Context, estateID string) (distance int, err error)
+	GetEstatesReport(ctx context.Context, filter m.EstateReportFilter) (reports []m.EstateReport, err error)
+	AnalyzeDensity(ctx context.Context, estateID string, gapLimit int) (analysis m.DensityAnalysis, err error)
+	GetEstateMap(ctx context.Context, estateID string, resolution int, withDronePath bool) (estateMap m.EstateMap, err error)
+	ListTrees(ctx context.Context, estateID string, filter m.TreeFilter, withGeo bool, includeDeleted bool) (trees []m.Tree, err error)
+	EachTree(ctx context.Context, estateID string, filter m.TreeFilter, withGeo bool, includeDeleted bool, fn func(tree m.Tree) error) (err error)
+	GetDroneWaypoints(ctx context.Context, estateID string, withGeo bool) (waypoints []m.Waypoint, err error)
+
+	CreateDivision(ctx context.Context, division m.Division) (id string, err error)
+	CreateBlock(ctx context.Context, block m.Block) (id string, err error)
+	GetEstateDivisions(ctx context.Context, estateID string) (estate m.Estate, err error)
+	GetBlockStats(ctx context.Context, estateID string, blockID string, filter m.TreeFilter) (stat m.Stats, err error)
+	GetBlockDroneDistance(ctx context.Context, estateID string, blockID string) (distance int, err error)
+	GetBlockDroneWaypoints(ctx context.Context, estateID string, blockID string, withGeo bool) (waypoints []m.Waypoint, err error)
+
+	PurgeDeleted(ctx context.Context, retention time.Duration) (purged m.PurgeResult, err error)
+	GetAuditLog(ctx context.Context, estateID string) (entries []m.AuditEntry, err error)
+	CreateObservation(ctx context.Context, observation m.Observation) (id string, err error)
+	ListObservations(ctx context.Context, estateID string, filter m.ObservationFilter) (observations []m.Observation, err error)
+	GetTreesAtRisk(ctx context.Context, estateID string, radius int, obsType string) (trees []m.Tree, err error)
+	CreateHarvest(ctx context.Context, harvest m.Harvest) (id string, err error)
+	GetYieldReport(ctx context.Context, estateID string, filter m.YieldFilter) (report m.YieldReport, err error)
+	CreateHarvestRound(ctx context.Context, round m.HarvestRound) (id string, err error)
+	ListHarvestRounds(ctx context.Context, estateID string) (rounds []m.HarvestRound, err error)
+	GetHarvestPlan(ctx context.Context, estateID string, roundID string) (routes []m.HarvestRoute, err error)
+	CompleteHarvestRound(ctx context.Context, estateID string, roundID string) (err error)
+	CreateFertilizerProgram(ctx context.Context, program m.FertilizerProgram) (id string, err error)
+	ListFertilizerPrograms(ctx context.Context, estateID string) (programs []m.FertilizerProgram, err error)
+	RecordFertilizerApplication(ctx context.Context, estateID string, application m.FertilizerApplication) (id string, err error)
+	ListFertilizerApplications(ctx context.Context, estateID string, programID string) (applications []m.FertilizerApplication, err error)
+	GetFertilizerCalendar(ctx context.Context, estateID string, days int) (calendar m.FertilizerCalendar, err error)
+	GetReplantingPlan(ctx context.Context, estateID string, options m.ReplantingOptions) (plan m.ReplantingPlan, err error)
 }
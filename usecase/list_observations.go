@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"context"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// ListObservations returns the observations of the estate matching the
+// filter, oldest first.
+func (u *Usecase) ListObservations(ctx context.Context, estateID string, filter m.ObservationFilter) (observations []m.Observation, err error) {
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return nil, notExist("estate")
+	}
+
+	observations, err = u.Repo.GetObservations(ctx, estateID, filter)
+	if err != nil {
+		return nil, err
+	}
+	if observations == nil {
+		observations = []m.Observation{}
+	}
+	return observations, nil
+}
@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_ListObservations(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+	filter := m.ObservationFilter{Type: "ganoderma"}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetObservations(gomock.Any(), "aaa", filter).Return(nil, nil)
+	if got, err := u.ListObservations(context.Background(), "aaa", filter); err != nil || !reflect.DeepEqual(got, []m.Observation{}) {
+		t.Errorf("Usecase.ListObservations() = %v, %v, want empty list", got, err)
+	}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 5, Width: 5}, nil)
+	mockRepo.EXPECT().GetObservations(gomock.Any(), "aaa", filter).Return(nil, errors.New("observation"))
+	if _, err := u.ListObservations(context.Background(), "aaa", filter); err == nil {
+		t.Errorf("Usecase.ListObservations() expected error")
+	}
+
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+	if _, err := u.ListObservations(context.Background(), "aaa", filter); err == nil {
+		t.Errorf("Usecase.ListObservations() expected error for unknown estate")
+	}
+}
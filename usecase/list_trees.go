@@ -0,0 +1,52 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// ListTrees returns the trees of the estate matching the filter, ordered by
+// row then column. When withGeo is set and the estate is georeferenced every
+// tree has its location.
+// With includeDeleted soft-deleted trees, and the trees of a soft-deleted
+// estate, are listed too.
+func (u *Usecase) ListTrees(ctx context.Context, estateID string, filter m.TreeFilter, withGeo bool, includeDeleted bool) (trees []m.Tree, err error) {
+	trees = []m.Tree{}
+	err = u.EachTree(ctx, estateID, filter, withGeo, includeDeleted, func(tree m.Tree) error {
+		trees = append(trees, tree)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return trees, nil
+}
+
+// EachTree calls fn with the trees ListTrees returns, one at a time, so that
+// they are never all kept. It stops at the first error of fn and returns it.
+func (u *Usecase) EachTree(ctx context.Context, estateID string, filter m.TreeFilter, withGeo bool, includeDeleted bool, fn func(tree m.Tree) error) (err error) {
+	if includeDeleted {
+		ctx = repository.WithDeleted(ctx)
+	}
+
+	// check if estate exist
+	estate, err := u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return notExist("estate")
+	}
+
+	return u.Repo.EachTree(ctx, estateID, func(tree m.Tree) error {
+		if !matchTree(tree, filter) {
+			return nil
+		}
+		if withGeo {
+			tree.Location = plotLocation(estate.Geo, tree.X, tree.Y)
+		}
+		return fn(tree)
+	})
+}
@@ -0,0 +1,200 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_ListTrees(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	geo := &m.GeoAnchor{OriginLat: 1, OriginLon: 101, Bearing: 90, PlotSize: 10}
+	type args struct {
+		ctx      context.Context
+		estateID string
+		filter   m.TreeFilter
+		withGeo  bool
+	}
+	tests := []struct {
+		name      string
+		args      args
+		wantTrees []m.Tree
+		wantErr   bool
+		repo      repository.RepositoryInterface
+		mockCalls []func() *gomock.Call
+	}{
+		{
+			name: "when all good, return trees ordered by row",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantTrees: []m.Tree{{X: 1, Y: 1, Height: 3}, {X: 2, Y: 1, Height: 4}, {X: 1, Y: 2, Height: 5}},
+			wantErr:   false,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2, Geo: geo}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 1, Y: 2, Height: 5}, {X: 2, Y: 1, Height: 4}, {X: 1, Y: 1, Height: 3}}, nil))
+				},
+			},
+		},
+		{
+			name: "when filtered by variety, return matching trees only",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				filter:   m.TreeFilter{Variety: "DxP"},
+			},
+			wantTrees: []m.Tree{{X: 2, Y: 1, Height: 4, Variety: "DxP"}},
+			wantErr:   false,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 1, Y: 2, Height: 5, Variety: "Tenera"}, {X: 2, Y: 1, Height: 4, Variety: "DxP"}}, nil))
+				},
+			},
+		},
+		{
+			name: "when geo requested, return trees with location",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				withGeo:  true,
+			},
+			wantTrees: []m.Tree{{X: 2, Y: 1, Height: 4, Location: plotLocation(geo, 2, 1)}},
+			wantErr:   false,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2, Geo: geo}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 2, Y: 1, Height: 4}}, nil))
+				},
+			},
+		},
+		{
+			name: "when estate has no tree, return empty list",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+				withGeo:  true,
+			},
+			wantTrees: []m.Tree{},
+			wantErr:   false,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, nil))
+				},
+			},
+		},
+		{
+			name: "when estate not found, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantTrees: nil,
+			wantErr:   true,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name: "when get estate give error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantTrees: nil,
+			wantErr:   true,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, errors.New("estate"))
+				},
+			},
+		},
+		{
+			name: "when get tree give error, return error",
+			args: args{
+				ctx:      context.Background(),
+				estateID: "aaa",
+			},
+			wantTrees: nil,
+			wantErr:   true,
+			repo:      mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, errors.New("tree")))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotTrees, err := u.ListTrees(tt.args.ctx, tt.args.estateID, tt.args.filter, tt.args.withGeo, false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.ListTrees() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotTrees, tt.wantTrees) {
+				t.Errorf("Usecase.ListTrees() = %v, want %v", gotTrees, tt.wantTrees)
+			}
+		})
+	}
+}
+
+func TestUsecase_EachTree(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+
+	// the trees matching the filter come one at a time until fn fails
+	mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 3, Width: 3}, nil)
+	mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{
+		{X: 1, Y: 1, Height: 3, Variety: "DxP"}, {X: 2, Y: 1, Height: 4}, {X: 3, Y: 1, Height: 5, Variety: "DxP"}, {X: 1, Y: 2, Height: 6, Variety: "DxP"},
+	}, nil))
+	var got []int
+	err := u.EachTree(context.Background(), "aaa", m.TreeFilter{Variety: "DxP"}, false, false, func(tree m.Tree) error {
+		got = append(got, tree.Height)
+		if len(got) == 2 {
+			return errors.New("enough")
+		}
+		return nil
+	})
+	if err == nil || !reflect.DeepEqual(got, []int{3, 5}) {
+		t.Errorf("Usecase.EachTree() = %v, %v, want [3 5] then enough", got, err)
+	}
+}
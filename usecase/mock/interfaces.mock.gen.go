@@ -12,6 +12,7 @@ package usecase
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	types "github.com/SawitProRecruitment/UserService/types"
 	gomock "go.uber.org/mock/gomock"
@@ -40,19 +41,138 @@ func (m *MockUsecaseInterface) EXPECT() *MockUsecaseInterfaceMockRecorder {
 	return m.recorder
 }
 
+// AnalyzeDensity mocks base method.
+func (m *MockUsecaseInterface) AnalyzeDensity(arg0 context.Context, arg1 string, arg2 int) (types.DensityAnalysis, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AnalyzeDensity", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types.DensityAnalysis)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AnalyzeDensity indicates an expected call of AnalyzeDensity.
+func (mr *MockUsecaseInterfaceMockRecorder) AnalyzeDensity(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AnalyzeDensity", reflect.TypeOf((*MockUsecaseInterface)(nil).AnalyzeDensity), arg0, arg1, arg2)
+}
+
+// CompleteHarvestRound mocks base method.
+func (m *MockUsecaseInterface) CompleteHarvestRound(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CompleteHarvestRound", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CompleteHarvestRound indicates an expected call of CompleteHarvestRound.
+func (mr *MockUsecaseInterfaceMockRecorder) CompleteHarvestRound(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CompleteHarvestRound", reflect.TypeOf((*MockUsecaseInterface)(nil).CompleteHarvestRound), arg0, arg1, arg2)
+}
+
+// CreateBlock mocks base method.
+func (m *MockUsecaseInterface) CreateBlock(arg0 context.Context, arg1 types.Block) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBlock", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateBlock indicates an expected call of CreateBlock.
+func (mr *MockUsecaseInterfaceMockRecorder) CreateBlock(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBlock", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateBlock), arg0, arg1)
+}
+
+// CreateDivision mocks base method.
+func (m *MockUsecaseInterface) CreateDivision(arg0 context.Context, arg1 types.Division) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateDivision", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateDivision indicates an expected call of CreateDivision.
+func (mr *MockUsecaseInterfaceMockRecorder) CreateDivision(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateDivision", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateDivision), arg0, arg1)
+}
+
 // CreateEstate mocks base method.
-func (m *MockUsecaseInterface) CreateEstate(arg0 context.Context, arg1, arg2 int) (string, error) {
+func (m *MockUsecaseInterface) CreateEstate(arg0 context.Context, arg1 types.Estate) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateEstate", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "CreateEstate", arg0, arg1)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // CreateEstate indicates an expected call of CreateEstate.
-func (mr *MockUsecaseInterfaceMockRecorder) CreateEstate(arg0, arg1, arg2 any) *gomock.Call {
+func (mr *MockUsecaseInterfaceMockRecorder) CreateEstate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEstate", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateEstate), arg0, arg1)
+}
+
+// CreateFertilizerProgram mocks base method.
+func (m *MockUsecaseInterface) CreateFertilizerProgram(arg0 context.Context, arg1 types.FertilizerProgram) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateFertilizerProgram", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateFertilizerProgram indicates an expected call of CreateFertilizerProgram.
+func (mr *MockUsecaseInterfaceMockRecorder) CreateFertilizerProgram(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateFertilizerProgram", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateFertilizerProgram), arg0, arg1)
+}
+
+// CreateHarvest mocks base method.
+func (m *MockUsecaseInterface) CreateHarvest(arg0 context.Context, arg1 types.Harvest) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHarvest", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHarvest indicates an expected call of CreateHarvest.
+func (mr *MockUsecaseInterfaceMockRecorder) CreateHarvest(arg0, arg1 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateEstate", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateEstate), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHarvest", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateHarvest), arg0, arg1)
+}
+
+// CreateHarvestRound mocks base method.
+func (m *MockUsecaseInterface) CreateHarvestRound(arg0 context.Context, arg1 types.HarvestRound) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateHarvestRound", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateHarvestRound indicates an expected call of CreateHarvestRound.
+func (mr *MockUsecaseInterfaceMockRecorder) CreateHarvestRound(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateHarvestRound", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateHarvestRound), arg0, arg1)
+}
+
+// CreateObservation mocks base method.
+func (m *MockUsecaseInterface) CreateObservation(arg0 context.Context, arg1 types.Observation) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateObservation", arg0, arg1)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateObservation indicates an expected call of CreateObservation.
+func (mr *MockUsecaseInterfaceMockRecorder) CreateObservation(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateObservation", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateObservation), arg0, arg1)
 }
 
 // CreateTree mocks base method.
@@ -70,6 +190,108 @@ func (mr *MockUsecaseInterfaceMockRecorder) CreateTree(arg0, arg1, arg2 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTree", reflect.TypeOf((*MockUsecaseInterface)(nil).CreateTree), arg0, arg1, arg2)
 }
 
+// DeleteEstate mocks base method.
+func (m *MockUsecaseInterface) DeleteEstate(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteEstate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteEstate indicates an expected call of DeleteEstate.
+func (mr *MockUsecaseInterfaceMockRecorder) DeleteEstate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteEstate", reflect.TypeOf((*MockUsecaseInterface)(nil).DeleteEstate), arg0, arg1)
+}
+
+// DeleteTree mocks base method.
+func (m *MockUsecaseInterface) DeleteTree(arg0 context.Context, arg1 string, arg2, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTree", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTree indicates an expected call of DeleteTree.
+func (mr *MockUsecaseInterfaceMockRecorder) DeleteTree(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTree", reflect.TypeOf((*MockUsecaseInterface)(nil).DeleteTree), arg0, arg1, arg2, arg3)
+}
+
+// EachTree mocks base method.
+func (m *MockUsecaseInterface) EachTree(arg0 context.Context, arg1 string, arg2 types.TreeFilter, arg3, arg4 bool, arg5 func(types.Tree) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachTree", arg0, arg1, arg2, arg3, arg4, arg5)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachTree indicates an expected call of EachTree.
+func (mr *MockUsecaseInterfaceMockRecorder) EachTree(arg0, arg1, arg2, arg3, arg4, arg5 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachTree", reflect.TypeOf((*MockUsecaseInterface)(nil).EachTree), arg0, arg1, arg2, arg3, arg4, arg5)
+}
+
+// GetAuditLog mocks base method.
+func (m *MockUsecaseInterface) GetAuditLog(arg0 context.Context, arg1 string) ([]types.AuditEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAuditLog", arg0, arg1)
+	ret0, _ := ret[0].([]types.AuditEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAuditLog indicates an expected call of GetAuditLog.
+func (mr *MockUsecaseInterfaceMockRecorder) GetAuditLog(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAuditLog", reflect.TypeOf((*MockUsecaseInterface)(nil).GetAuditLog), arg0, arg1)
+}
+
+// GetBlockDroneDistance mocks base method.
+func (m *MockUsecaseInterface) GetBlockDroneDistance(arg0 context.Context, arg1, arg2 string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockDroneDistance", arg0, arg1, arg2)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockDroneDistance indicates an expected call of GetBlockDroneDistance.
+func (mr *MockUsecaseInterfaceMockRecorder) GetBlockDroneDistance(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockDroneDistance", reflect.TypeOf((*MockUsecaseInterface)(nil).GetBlockDroneDistance), arg0, arg1, arg2)
+}
+
+// GetBlockDroneWaypoints mocks base method.
+func (m *MockUsecaseInterface) GetBlockDroneWaypoints(arg0 context.Context, arg1, arg2 string, arg3 bool) ([]types.Waypoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockDroneWaypoints", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]types.Waypoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockDroneWaypoints indicates an expected call of GetBlockDroneWaypoints.
+func (mr *MockUsecaseInterfaceMockRecorder) GetBlockDroneWaypoints(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockDroneWaypoints", reflect.TypeOf((*MockUsecaseInterface)(nil).GetBlockDroneWaypoints), arg0, arg1, arg2, arg3)
+}
+
+// GetBlockStats mocks base method.
+func (m *MockUsecaseInterface) GetBlockStats(arg0 context.Context, arg1, arg2 string, arg3 types.TreeFilter) (types.Stats, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlockStats", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(types.Stats)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlockStats indicates an expected call of GetBlockStats.
+func (mr *MockUsecaseInterfaceMockRecorder) GetBlockStats(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlockStats", reflect.TypeOf((*MockUsecaseInterface)(nil).GetBlockStats), arg0, arg1, arg2, arg3)
+}
+
 // GetDroneDistance mocks base method.
 func (m *MockUsecaseInterface) GetDroneDistance(arg0 context.Context, arg1 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -85,6 +307,21 @@ func (mr *MockUsecaseInterfaceMockRecorder) GetDroneDistance(arg0, arg1 any) *go
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDroneDistance", reflect.TypeOf((*MockUsecaseInterface)(nil).GetDroneDistance), arg0, arg1)
 }
 
+// GetDroneWaypoints mocks base method.
+func (m *MockUsecaseInterface) GetDroneWaypoints(arg0 context.Context, arg1 string, arg2 bool) ([]types.Waypoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDroneWaypoints", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.Waypoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDroneWaypoints indicates an expected call of GetDroneWaypoints.
+func (mr *MockUsecaseInterfaceMockRecorder) GetDroneWaypoints(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDroneWaypoints", reflect.TypeOf((*MockUsecaseInterface)(nil).GetDroneWaypoints), arg0, arg1, arg2)
+}
+
 // GetEstateByID mocks base method.
 func (m *MockUsecaseInterface) GetEstateByID(arg0 context.Context, arg1 string) (types.Estate, error) {
 	m.ctrl.T.Helper()
@@ -100,17 +337,300 @@ func (mr *MockUsecaseInterfaceMockRecorder) GetEstateByID(arg0, arg1 any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateByID", reflect.TypeOf((*MockUsecaseInterface)(nil).GetEstateByID), arg0, arg1)
 }
 
+// GetEstateDivisions mocks base method.
+func (m *MockUsecaseInterface) GetEstateDivisions(arg0 context.Context, arg1 string) (types.Estate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstateDivisions", arg0, arg1)
+	ret0, _ := ret[0].(types.Estate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstateDivisions indicates an expected call of GetEstateDivisions.
+func (mr *MockUsecaseInterfaceMockRecorder) GetEstateDivisions(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateDivisions", reflect.TypeOf((*MockUsecaseInterface)(nil).GetEstateDivisions), arg0, arg1)
+}
+
+// GetEstateMap mocks base method.
+func (m *MockUsecaseInterface) GetEstateMap(arg0 context.Context, arg1 string, arg2 int, arg3 bool) (types.EstateMap, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstateMap", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(types.EstateMap)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstateMap indicates an expected call of GetEstateMap.
+func (mr *MockUsecaseInterfaceMockRecorder) GetEstateMap(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateMap", reflect.TypeOf((*MockUsecaseInterface)(nil).GetEstateMap), arg0, arg1, arg2, arg3)
+}
+
 // GetEstateStats mocks base method.
-func (m *MockUsecaseInterface) GetEstateStats(arg0 context.Context, arg1 string) (types.Stats, error) {
+func (m *MockUsecaseInterface) GetEstateStats(arg0 context.Context, arg1 string, arg2 types.TreeFilter) (types.Stats, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetEstateStats", arg0, arg1)
+	ret := m.ctrl.Call(m, "GetEstateStats", arg0, arg1, arg2)
 	ret0, _ := ret[0].(types.Stats)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetEstateStats indicates an expected call of GetEstateStats.
-func (mr *MockUsecaseInterfaceMockRecorder) GetEstateStats(arg0, arg1 any) *gomock.Call {
+func (mr *MockUsecaseInterfaceMockRecorder) GetEstateStats(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateStats", reflect.TypeOf((*MockUsecaseInterface)(nil).GetEstateStats), arg0, arg1, arg2)
+}
+
+// GetEstatesReport mocks base method.
+func (m *MockUsecaseInterface) GetEstatesReport(arg0 context.Context, arg1 types.EstateReportFilter) ([]types.EstateReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetEstatesReport", arg0, arg1)
+	ret0, _ := ret[0].([]types.EstateReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetEstatesReport indicates an expected call of GetEstatesReport.
+func (mr *MockUsecaseInterfaceMockRecorder) GetEstatesReport(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstatesReport", reflect.TypeOf((*MockUsecaseInterface)(nil).GetEstatesReport), arg0, arg1)
+}
+
+// GetFertilizerCalendar mocks base method.
+func (m *MockUsecaseInterface) GetFertilizerCalendar(arg0 context.Context, arg1 string, arg2 int) (types.FertilizerCalendar, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFertilizerCalendar", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types.FertilizerCalendar)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFertilizerCalendar indicates an expected call of GetFertilizerCalendar.
+func (mr *MockUsecaseInterfaceMockRecorder) GetFertilizerCalendar(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFertilizerCalendar", reflect.TypeOf((*MockUsecaseInterface)(nil).GetFertilizerCalendar), arg0, arg1, arg2)
+}
+
+// GetHarvestPlan mocks base method.
+func (m *MockUsecaseInterface) GetHarvestPlan(arg0 context.Context, arg1, arg2 string) ([]types.HarvestRoute, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHarvestPlan", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.HarvestRoute)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHarvestPlan indicates an expected call of GetHarvestPlan.
+func (mr *MockUsecaseInterfaceMockRecorder) GetHarvestPlan(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHarvestPlan", reflect.TypeOf((*MockUsecaseInterface)(nil).GetHarvestPlan), arg0, arg1, arg2)
+}
+
+// GetReplantingPlan mocks base method.
+func (m *MockUsecaseInterface) GetReplantingPlan(arg0 context.Context, arg1 string, arg2 types.ReplantingOptions) (types.ReplantingPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReplantingPlan", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types.ReplantingPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReplantingPlan indicates an expected call of GetReplantingPlan.
+func (mr *MockUsecaseInterfaceMockRecorder) GetReplantingPlan(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReplantingPlan", reflect.TypeOf((*MockUsecaseInterface)(nil).GetReplantingPlan), arg0, arg1, arg2)
+}
+
+// GetTreesAtRisk mocks base method.
+func (m *MockUsecaseInterface) GetTreesAtRisk(arg0 context.Context, arg1 string, arg2 int, arg3 string) ([]types.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTreesAtRisk", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]types.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTreesAtRisk indicates an expected call of GetTreesAtRisk.
+func (mr *MockUsecaseInterfaceMockRecorder) GetTreesAtRisk(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTreesAtRisk", reflect.TypeOf((*MockUsecaseInterface)(nil).GetTreesAtRisk), arg0, arg1, arg2, arg3)
+}
+
+// GetYieldReport mocks base method.
+func (m *MockUsecaseInterface) GetYieldReport(arg0 context.Context, arg1 string, arg2 types.YieldFilter) (types.YieldReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetYieldReport", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types.YieldReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetYieldReport indicates an expected call of GetYieldReport.
+func (mr *MockUsecaseInterfaceMockRecorder) GetYieldReport(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetYieldReport", reflect.TypeOf((*MockUsecaseInterface)(nil).GetYieldReport), arg0, arg1, arg2)
+}
+
+// ListFertilizerApplications mocks base method.
+func (m *MockUsecaseInterface) ListFertilizerApplications(arg0 context.Context, arg1, arg2 string) ([]types.FertilizerApplication, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFertilizerApplications", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.FertilizerApplication)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFertilizerApplications indicates an expected call of ListFertilizerApplications.
+func (mr *MockUsecaseInterfaceMockRecorder) ListFertilizerApplications(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFertilizerApplications", reflect.TypeOf((*MockUsecaseInterface)(nil).ListFertilizerApplications), arg0, arg1, arg2)
+}
+
+// ListFertilizerPrograms mocks base method.
+func (m *MockUsecaseInterface) ListFertilizerPrograms(arg0 context.Context, arg1 string) ([]types.FertilizerProgram, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListFertilizerPrograms", arg0, arg1)
+	ret0, _ := ret[0].([]types.FertilizerProgram)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListFertilizerPrograms indicates an expected call of ListFertilizerPrograms.
+func (mr *MockUsecaseInterfaceMockRecorder) ListFertilizerPrograms(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListFertilizerPrograms", reflect.TypeOf((*MockUsecaseInterface)(nil).ListFertilizerPrograms), arg0, arg1)
+}
+
+// ListHarvestRounds mocks base method.
+func (m *MockUsecaseInterface) ListHarvestRounds(arg0 context.Context, arg1 string) ([]types.HarvestRound, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListHarvestRounds", arg0, arg1)
+	ret0, _ := ret[0].([]types.HarvestRound)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListHarvestRounds indicates an expected call of ListHarvestRounds.
+func (mr *MockUsecaseInterfaceMockRecorder) ListHarvestRounds(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListHarvestRounds", reflect.TypeOf((*MockUsecaseInterface)(nil).ListHarvestRounds), arg0, arg1)
+}
+
+// ListObservations mocks base method.
+func (m *MockUsecaseInterface) ListObservations(arg0 context.Context, arg1 string, arg2 types.ObservationFilter) ([]types.Observation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListObservations", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.Observation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListObservations indicates an expected call of ListObservations.
+func (mr *MockUsecaseInterfaceMockRecorder) ListObservations(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObservations", reflect.TypeOf((*MockUsecaseInterface)(nil).ListObservations), arg0, arg1, arg2)
+}
+
+// ListTrees mocks base method.
+func (m *MockUsecaseInterface) ListTrees(arg0 context.Context, arg1 string, arg2 types.TreeFilter, arg3, arg4 bool) ([]types.Tree, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTrees", arg0, arg1, arg2, arg3, arg4)
+	ret0, _ := ret[0].([]types.Tree)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTrees indicates an expected call of ListTrees.
+func (mr *MockUsecaseInterfaceMockRecorder) ListTrees(arg0, arg1, arg2, arg3, arg4 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTrees", reflect.TypeOf((*MockUsecaseInterface)(nil).ListTrees), arg0, arg1, arg2, arg3, arg4)
+}
+
+// PurgeDeleted mocks base method.
+func (m *MockUsecaseInterface) PurgeDeleted(arg0 context.Context, arg1 time.Duration) (types.PurgeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeleted", arg0, arg1)
+	ret0, _ := ret[0].(types.PurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeleted indicates an expected call of PurgeDeleted.
+func (mr *MockUsecaseInterfaceMockRecorder) PurgeDeleted(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeleted", reflect.TypeOf((*MockUsecaseInterface)(nil).PurgeDeleted), arg0, arg1)
+}
+
+// RecordFertilizerApplication mocks base method.
+func (m *MockUsecaseInterface) RecordFertilizerApplication(arg0 context.Context, arg1 string, arg2 types.FertilizerApplication) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFertilizerApplication", arg0, arg1, arg2)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordFertilizerApplication indicates an expected call of RecordFertilizerApplication.
+func (mr *MockUsecaseInterfaceMockRecorder) RecordFertilizerApplication(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFertilizerApplication", reflect.TypeOf((*MockUsecaseInterface)(nil).RecordFertilizerApplication), arg0, arg1, arg2)
+}
+
+// RestoreEstate mocks base method.
+func (m *MockUsecaseInterface) RestoreEstate(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreEstate", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreEstate indicates an expected call of RestoreEstate.
+func (mr *MockUsecaseInterfaceMockRecorder) RestoreEstate(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreEstate", reflect.TypeOf((*MockUsecaseInterface)(nil).RestoreEstate), arg0, arg1)
+}
+
+// RestoreTree mocks base method.
+func (m *MockUsecaseInterface) RestoreTree(arg0 context.Context, arg1 string, arg2, arg3 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreTree", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RestoreTree indicates an expected call of RestoreTree.
+func (mr *MockUsecaseInterfaceMockRecorder) RestoreTree(arg0, arg1, arg2, arg3 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreTree", reflect.TypeOf((*MockUsecaseInterface)(nil).RestoreTree), arg0, arg1, arg2, arg3)
+}
+
+// SearchEstates mocks base method.
+func (m *MockUsecaseInterface) SearchEstates(arg0 context.Context, arg1 types.EstateSearch, arg2 bool) ([]types.Estate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchEstates", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]types.Estate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchEstates indicates an expected call of SearchEstates.
+func (mr *MockUsecaseInterfaceMockRecorder) SearchEstates(arg0, arg1, arg2 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchEstates", reflect.TypeOf((*MockUsecaseInterface)(nil).SearchEstates), arg0, arg1, arg2)
+}
+
+// UpdateEstate mocks base method.
+func (m *MockUsecaseInterface) UpdateEstate(arg0 context.Context, arg1 string, arg2 types.EstateUpdate) (types.Estate, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateEstate", arg0, arg1, arg2)
+	ret0, _ := ret[0].(types.Estate)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateEstate indicates an expected call of UpdateEstate.
+func (mr *MockUsecaseInterfaceMockRecorder) UpdateEstate(arg0, arg1, arg2 any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetEstateStats", reflect.TypeOf((*MockUsecaseInterface)(nil).GetEstateStats), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateEstate", reflect.TypeOf((*MockUsecaseInterface)(nil).UpdateEstate), arg0, arg1, arg2)
 }
@@ -0,0 +1,14 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// PurgeDeleted hard-deletes the estates and trees soft-deleted for longer than
+// the retention window.
+func (u *Usecase) PurgeDeleted(ctx context.Context, retention time.Duration) (purged m.PurgeResult, err error) {
+	return u.Repo.PurgeDeleted(ctx, time.Now().Add(-retention))
+}
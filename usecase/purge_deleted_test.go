@@ -0,0 +1,28 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_PurgeDeleted(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+
+	// the cut-off is the retention window before now
+	from := time.Now().Add(-time.Hour)
+	mockRepo.EXPECT().PurgeDeleted(gomock.Any(), gomock.Cond(func(x any) bool {
+		before := x.(time.Time)
+		return !before.Before(from) && !before.After(time.Now().Add(-time.Hour))
+	})).Return(m.PurgeResult{Estates: 1, Trees: 4}, nil)
+
+	got, err := u.PurgeDeleted(context.Background(), time.Hour)
+	if want := (m.PurgeResult{Estates: 1, Trees: 4}); err != nil || got != want {
+		t.Errorf("Usecase.PurgeDeleted() = %v, %v, want %v", got, err, want)
+	}
+}
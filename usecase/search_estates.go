@@ -0,0 +1,25 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// SearchEstates lists the estates matching the search, ordered by name. With
+// includeDeleted soft-deleted estates are listed too.
+func (u *Usecase) SearchEstates(ctx context.Context, search m.EstateSearch, includeDeleted bool) (estates []m.Estate, err error) {
+	if includeDeleted {
+		ctx = repository.WithDeleted(ctx)
+	}
+
+	estates, err = u.Repo.SearchEstates(ctx, search)
+	if err != nil {
+		return nil, err
+	}
+	if estates == nil {
+		estates = []m.Estate{}
+	}
+	return estates, nil
+}
@@ -0,0 +1,36 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_SearchEstates(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	u := &Usecase{Repo: mockRepo}
+	search := m.EstateSearch{Query: "lilin", Tag: "young"}
+
+	mockRepo.EXPECT().SearchEstates(gomock.Any(), search).Return([]m.Estate{{ID: "aaa", Name: "Sungai Lilin"}}, nil)
+	got, err := u.SearchEstates(context.Background(), search, false)
+	if want := []m.Estate{{ID: "aaa", Name: "Sungai Lilin"}}; err != nil || !reflect.DeepEqual(got, want) {
+		t.Errorf("Usecase.SearchEstates() = %v, %v, want %v", got, err, want)
+	}
+
+	// no match is an empty list, not nil
+	mockRepo.EXPECT().SearchEstates(gomock.Any(), search).Return(nil, nil)
+	got, err = u.SearchEstates(context.Background(), search, true)
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("Usecase.SearchEstates() = %v, %v, want empty list", got, err)
+	}
+
+	mockRepo.EXPECT().SearchEstates(gomock.Any(), search).Return(nil, errors.New("search"))
+	if _, err = u.SearchEstates(context.Background(), search, false); err == nil {
+		t.Errorf("Usecase.SearchEstates() expected error")
+	}
+}
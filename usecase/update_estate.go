@@ -0,0 +1,109 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	m "github.com/SawitProRecruitment/UserService/types"
+)
+
+// checkResize makes sure nothing already placed in the estate ends up outside
+// of it once resized, soft-deleted trees included as they can be restored.
+func (u *Usecase) checkResize(ctx context.Context, estate m.Estate) error {
+	if err := validateBoundary(estate.Boundary, estate.Length, estate.Width); err != nil {
+		return errors.New("resize would leave the boundary outside estate")
+	}
+
+	err := u.Repo.EachTree(repository.WithDeleted(ctx), estate.ID, func(t m.Tree) error {
+		if t.X > estate.Length || t.Y > estate.Width {
+			return errors.New("resize would leave trees outside estate")
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	divisions, err := u.Repo.GetDivisions(ctx, estate.ID)
+	if err != nil {
+		return err
+	}
+	bounds := m.Extent{XMin: 1, YMin: 1, XMax: estate.Length, YMax: estate.Width}
+	for _, d := range divisions {
+		if !extentContains(bounds, d.Extent) {
+			return errors.New("resize would leave divisions outside estate")
+		}
+	}
+	return nil
+}
+
+func (u *Usecase) UpdateEstate(ctx context.Context, estateID string, update m.EstateUpdate) (estate m.Estate, err error) {
+	err = u.inTx(ctx, func(tx *Usecase) error {
+		estate, err = tx.updateEstate(ctx, estateID, update)
+		return err
+	})
+	if err != nil {
+		return m.Estate{}, err
+	}
+	return estate, nil
+}
+
+func (u *Usecase) updateEstate(ctx context.Context, estateID string, update m.EstateUpdate) (estate m.Estate, err error) {
+	estate, err = u.Repo.GetEstateByID(ctx, estateID)
+	if err != nil {
+		return
+	}
+	if estate.ID == "" {
+		return m.Estate{}, notExist("estate")
+	}
+
+	if update.Name != nil {
+		estate.Name = *update.Name
+	}
+	if update.Code != nil {
+		estate.Code = *update.Code
+	}
+	if update.Owner != nil {
+		estate.Owner = *update.Owner
+	}
+	if update.Region != nil {
+		estate.Region = *update.Region
+	}
+	if update.PlantingYear != nil {
+		estate.PlantingYear = *update.PlantingYear
+	}
+	if update.Tags != nil {
+		estate.Tags = update.Tags
+	}
+	if update.Metadata != nil {
+		estate.Metadata = update.Metadata
+	}
+	if err = validateDetails(estate); err != nil {
+		return m.Estate{}, err
+	}
+	if update.Length != nil || update.Width != nil {
+		shrunk := false
+		if update.Length != nil {
+			shrunk = shrunk || *update.Length < estate.Length
+			estate.Length = *update.Length
+		}
+		if update.Width != nil {
+			shrunk = shrunk || *update.Width < estate.Width
+			estate.Width = *update.Width
+		}
+		if err = validateSize(estate.Length, estate.Width); err != nil {
+			return m.Estate{}, err
+		}
+		if shrunk {
+			if err = u.checkResize(ctx, estate); err != nil {
+				return m.Estate{}, err
+			}
+		}
+	}
+
+	if err = u.Repo.UpdateEstate(ctx, estate); err != nil {
+		return m.Estate{}, err
+	}
+	return estate, nil
+}
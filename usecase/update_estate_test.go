@@ -0,0 +1,206 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
+	"go.uber.org/mock/gomock"
+)
+
+func TestUsecase_UpdateEstate(t *testing.T) {
+	mockRepo := repoMock.NewMockRepositoryInterface(gomock.NewController(t))
+	expectTx(mockRepo)
+	name, small, large := "north", 3, 20
+	code, year := "STH", 2015
+	type args struct {
+		ctx      context.Context
+		estateID string
+		update   m.EstateUpdate
+	}
+	tests := []struct {
+		name       string
+		args       args
+		wantEstate m.Estate
+		wantErr    bool
+		repo       repository.RepositoryInterface
+		mockCalls  []func() *gomock.Call
+	}{
+		{
+			name:       "when renamed, store the name",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Name: &name, Metadata: map[string]string{"owner": "budi"}}},
+			wantEstate: m.Estate{ID: "aaa", Name: "north", Length: 10, Width: 10, Metadata: map[string]string{"owner": "budi"}},
+			wantErr:    false,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().UpdateEstate(gomock.Any(), m.Estate{ID: "aaa", Name: "north", Length: 10, Width: 10, Metadata: map[string]string{"owner": "budi"}}).Return(nil)
+				},
+			},
+		},
+		{
+			name:       "when details change, store them",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Code: &code, PlantingYear: &year, Tags: []string{"flat"}}},
+			wantEstate: m.Estate{ID: "aaa", Name: "south", Code: "STH", Region: "Riau", PlantingYear: 2015, Tags: []string{"flat"}, Length: 10, Width: 10},
+			wantErr:    false,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Name: "south", Region: "Riau", Tags: []string{"young"}, Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().UpdateEstate(gomock.Any(), m.Estate{ID: "aaa", Name: "south", Code: "STH", Region: "Riau", PlantingYear: 2015, Tags: []string{"flat"}, Length: 10, Width: 10}).Return(nil)
+				},
+			},
+		},
+		{
+			name:       "when planting year not suitable, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{PlantingYear: &small}},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+			},
+		},
+		{
+			name:       "when enlarged, do not check the trees",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Length: &large}},
+			wantEstate: m.Estate{ID: "aaa", Length: 20, Width: 10},
+			wantErr:    false,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().UpdateEstate(gomock.Any(), m.Estate{ID: "aaa", Length: 20, Width: 10}).Return(nil)
+				},
+			},
+		},
+		{
+			name:       "when shrunk around the trees, store the size",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Width: &small}},
+			wantEstate: m.Estate{ID: "aaa", Length: 10, Width: 3},
+			wantErr:    false,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 10, Y: 3, Height: 5}}, nil))
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return([]m.Division{{Extent: m.Extent{XMin: 1, YMin: 1, XMax: 10, YMax: 3}}}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().UpdateEstate(gomock.Any(), m.Estate{ID: "aaa", Length: 10, Width: 3}).Return(nil)
+				},
+			},
+		},
+		{
+			name:       "when shrunk over a tree, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Length: &small}},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees([]m.Tree{{X: 4, Y: 1, Height: 5}}, nil))
+				},
+			},
+		},
+		{
+			name:       "when shrunk over a division, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Length: &small}},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().EachTree(gomock.Any(), "aaa", gomock.Any()).DoAndReturn(streamTrees(nil, nil))
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetDivisions(gomock.Any(), "aaa").Return([]m.Division{{Extent: m.Extent{XMin: 1, YMin: 1, XMax: 5, YMax: 5}}}, nil)
+				},
+			},
+		},
+		{
+			name:       "when shrunk over the boundary, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Length: &small}},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10, Boundary: []m.Point{{X: 1, Y: 1}, {X: 10, Y: 1}, {X: 1, Y: 10}}}, nil)
+				},
+			},
+		},
+		{
+			name:       "when estate not found, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Name: &name}},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{}, nil)
+				},
+			},
+		},
+		{
+			name:       "when update return error, return error",
+			args:       args{ctx: context.Background(), estateID: "aaa", update: m.EstateUpdate{Name: &name}},
+			wantEstate: m.Estate{},
+			wantErr:    true,
+			repo:       mockRepo,
+			mockCalls: []func() *gomock.Call{
+				func() *gomock.Call {
+					return mockRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 10, Width: 10}, nil)
+				},
+				func() *gomock.Call {
+					return mockRepo.EXPECT().UpdateEstate(gomock.Any(), gomock.Any()).Return(errors.New("update"))
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.mockCalls != nil {
+				for _, call := range tt.mockCalls {
+					call()
+				}
+			}
+
+			u := &Usecase{
+				Repo: tt.repo,
+			}
+
+			gotEstate, err := u.UpdateEstate(tt.args.ctx, tt.args.estateID, tt.args.update)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Usecase.UpdateEstate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotEstate, tt.wantEstate) {
+				t.Errorf("Usecase.UpdateEstate() = %v, want %v", gotEstate, tt.wantEstate)
+			}
+		})
+	}
+}
@@ -1,11 +1,39 @@
 package usecase
 
-import "github.com/SawitProRecruitment/UserService/repository"
+import (
+	"context"
+
+	"github.com/SawitProRecruitment/UserService/repository"
+)
 
 type Usecase struct {
 	Repo repository.RepositoryInterface
 }
 
+// NotExistError reports that the estate, or another record asked for, does
+// not exist.
+type NotExistError struct {
+	What string
+}
+
+func (e *NotExistError) Error() string {
+	return e.What + " is not exist"
+}
+
+func notExist(what string) error {
+	return &NotExistError{What: what}
+}
+
 func NewUsecase(repo repository.RepositoryInterface) *Usecase {
 	return &Usecase{Repo: repo}
 }
+
+// inTx runs fn with a usecase whose repository calls share one transaction,
+// so what fn reads still holds when it writes.
+func (u *Usecase) inTx(ctx context.Context, fn func(tx *Usecase) error) error {
+	return u.Repo.WithTx(ctx, func(repo repository.RepositoryInterface) error {
+		tx := *u
+		tx.Repo = repo
+		return fn(&tx)
+	})
+}
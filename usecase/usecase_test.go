@@ -1,13 +1,62 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/SawitProRecruitment/UserService/repository"
+	repoMock "github.com/SawitProRecruitment/UserService/repository/mock"
+	m "github.com/SawitProRecruitment/UserService/types"
 	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
 )
 
 func TestNewUsecase(t *testing.T) {
 	instance := NewUsecase(&repository.Repository{})
 	assert.NotNil(t, instance)
 }
+
+// expectTx lets the usecase run transactions on the mock, with the mock
+// itself as the repository of the transaction.
+func expectTx(mockRepo *repoMock.MockRepositoryInterface) {
+	mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repo repository.RepositoryInterface) error) error {
+		return fn(mockRepo)
+	}).AnyTimes()
+}
+
+// streamTrees lets the mock stream the trees, sorted as the repository does,
+// or fail with err.
+func streamTrees(trees []m.Tree, err error) func(ctx context.Context, estateID string, fn func(tree m.Tree) error) error {
+	return func(ctx context.Context, estateID string, fn func(tree m.Tree) error) error {
+		if err != nil {
+			return err
+		}
+		return sliceStream(trees)(fn)
+	}
+}
+
+func TestUsecase_inTx(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockRepo := repoMock.NewMockRepositoryInterface(ctrl)
+	txRepo := repoMock.NewMockRepositoryInterface(ctrl)
+	u := &Usecase{Repo: mockRepo}
+
+	// every call goes through the repository of the transaction
+	mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, fn func(repo repository.RepositoryInterface) error) error {
+		return fn(txRepo)
+	})
+	txRepo.EXPECT().GetEstateByID(gomock.Any(), "aaa").Return(m.Estate{ID: "aaa", Length: 2, Width: 2}, nil)
+	txRepo.EXPECT().GetTreeAt(gomock.Any(), "aaa", 1, 1).Return(m.Tree{}, false, nil)
+	txRepo.EXPECT().GetBlocks(gomock.Any(), "aaa").Return(nil, nil)
+	txRepo.EXPECT().CreateTree(gomock.Any(), "aaa", gomock.Any()).Return("ttt", nil)
+	if id, err := u.CreateTree(context.Background(), "aaa", m.Tree{X: 1, Y: 1, Height: 2}); err != nil || id != "ttt" {
+		t.Errorf("Usecase.CreateTree() = %v, %v, want ttt", id, err)
+	}
+
+	// a commit failure fails the usecase
+	mockRepo.EXPECT().WithTx(gomock.Any(), gomock.Any()).Return(errors.New("commit"))
+	if id, err := u.CreateTree(context.Background(), "aaa", m.Tree{X: 1, Y: 1, Height: 2}); err == nil || id != "" {
+		t.Errorf("Usecase.CreateTree() = %v, %v, want error", id, err)
+	}
+}